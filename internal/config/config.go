@@ -0,0 +1,71 @@
+// Package config holds the user's settings, read once at startup from
+// ~/.prodbooster/config.json. Every setting has a default, so the file is
+// optional and may list only what it changes.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/charmbracelet/glamour/styles"
+)
+
+type Config struct {
+	// AutoCompleteParents marks a todo done as soon as its last subtask is
+	AutoCompleteParents bool `json:"auto_complete_parents"`
+
+	// TrashRetentionDays is how long deleted items stay in the trash before
+	// they are purged at startup. 0 keeps them until purged by hand.
+	TrashRetentionDays int `json:"trash_retention_days"`
+
+	// MarkdownStyle is the glamour style notes are rendered in, "auto" for
+	// dark or light by the terminal's background
+	MarkdownStyle string `json:"markdown_style"`
+}
+
+// Default returns the settings used when the config file doesn't say otherwise
+func Default() Config {
+	return Config{
+		AutoCompleteParents: true,
+		TrashRetentionDays:  30,
+		MarkdownStyle:       styles.AutoStyle,
+	}
+}
+
+var current = Default()
+
+// Load reads the config file at path over the defaults. A missing file is
+// not an error.
+func Load(path string) error {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		current = cfg
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.TrashRetentionDays < 0 {
+		return fmt.Errorf("config %s: trash_retention_days can't be negative", path)
+	}
+	if _, ok := styles.DefaultStyles[cfg.MarkdownStyle]; !ok && cfg.MarkdownStyle != styles.AutoStyle {
+		return fmt.Errorf("config %s: unknown markdown_style %q", path, cfg.MarkdownStyle)
+	}
+
+	current = cfg
+	return nil
+}
+
+// Get returns the current settings
+func Get() Config {
+	return current
+}
@@ -0,0 +1,535 @@
+// Package dateparse turns the loose date and time phrases people type into
+// forms ("tomorrow 3pm", "next fri", "in 2h", "+3d") into time.Time values.
+package dateparse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrEmpty is returned when there is nothing to parse
+var ErrEmpty = errors.New("no date given")
+
+// Layout is the canonical form used to fill inputs with an existing value.
+// Parse always accepts it back.
+const Layout = "2006-01-02 15:04"
+
+var (
+	clockRe    = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm|a|p)?$`)
+	isoDateRe  = regexp.MustCompile(`^(\d{4})[-/](\d{1,2})[-/](\d{1,2})$`)
+	amountRe   = regexp.MustCompile(`^(\d+)([a-z]*)$`)
+	compoundRe = regexp.MustCompile(`^(?:\d+(?:\.\d+)?(?:h|m|s))+$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"jan": time.January, "january": time.January,
+	"feb": time.February, "february": time.February,
+	"mar": time.March, "march": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May,
+	"jun": time.June, "june": time.June,
+	"jul": time.July, "july": time.July,
+	"aug": time.August, "august": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// offset is a relative shift. Days and months go through AddDate so they
+// keep the wall clock across DST changes.
+type offset struct {
+	months   int
+	days     int
+	duration time.Duration
+}
+
+func (o offset) apply(t time.Time) time.Time {
+	return t.AddDate(0, o.months, o.days).Add(o.duration)
+}
+
+// Parse resolves input relative to now, in now's location. It understands:
+//
+//	2025-12-25 14:30, 2025-12-25, dec 25, 25 dec 2026
+//	today, tonight, tomorrow, yesterday
+//	mon, next fri, next week, next month
+//	3pm, 9:30am, 15:04, noon, midnight
+//	in 2h, in 3 days, +3d, -1w, in 1h30m
+//
+// and combinations such as "tomorrow 3pm" or "mon 09:30". Inputs that only
+// name a day take their time of day from dayTime (an offset from midnight,
+// e.g. 9*time.Hour); relative offsets keep the current time.
+func Parse(input string, now time.Time, dayTime time.Duration) (time.Time, error) {
+	s := normalize(input)
+	if s == "" {
+		return time.Time{}, ErrEmpty
+	}
+	if s == "now" {
+		return now, nil
+	}
+
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	var day *time.Time
+	var clock *time.Duration
+	var shift *offset
+	tonight := false // evening unless a time is given, see below
+
+	setDay := func(d time.Time) error {
+		if day != nil || shift != nil {
+			return fmt.Errorf("more than one date in %q", input)
+		}
+		day = &d
+		return nil
+	}
+	setClock := func(c time.Duration) error {
+		if clock != nil {
+			return fmt.Errorf("more than one time in %q", input)
+		}
+		clock = &c
+		return nil
+	}
+
+	tokens := strings.Fields(s)
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		next := ""
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+
+		switch {
+		case tok == "at" || tok == "on" || tok == "by":
+			continue
+
+		case tok == "today":
+			if err := setDay(today); err != nil {
+				return time.Time{}, err
+			}
+		case tok == "tonight":
+			if err := setDay(today); err != nil {
+				return time.Time{}, err
+			}
+			tonight = true
+		case tok == "tomorrow" || tok == "tmr" || tok == "tmrw":
+			if err := setDay(today.AddDate(0, 0, 1)); err != nil {
+				return time.Time{}, err
+			}
+		case tok == "yesterday":
+			if err := setDay(today.AddDate(0, 0, -1)); err != nil {
+				return time.Time{}, err
+			}
+		case tok == "noon":
+			if err := setClock(12 * time.Hour); err != nil {
+				return time.Time{}, err
+			}
+		case tok == "midnight":
+			if err := setClock(0); err != nil {
+				return time.Time{}, err
+			}
+
+		case tok == "next":
+			i++
+			switch {
+			case next == "week":
+				if err := setDay(today.AddDate(0, 0, 7)); err != nil {
+					return time.Time{}, err
+				}
+			case next == "month":
+				if err := setDay(today.AddDate(0, 1, 0)); err != nil {
+					return time.Time{}, err
+				}
+			case isWeekday(next):
+				if err := setDay(nextWeekday(today, weekdays[next], false)); err != nil {
+					return time.Time{}, err
+				}
+			default:
+				return time.Time{}, fmt.Errorf("expected a weekday, week or month after \"next\" in %q", input)
+			}
+
+		case isWeekday(tok):
+			if err := setDay(nextWeekday(today, weekdays[tok], true)); err != nil {
+				return time.Time{}, err
+			}
+
+		case tok == "in" || strings.HasPrefix(tok, "+") || strings.HasPrefix(tok, "-"):
+			sign := 1
+			rest := tokens[i:]
+			if tok == "in" {
+				rest = rest[1:]
+				i++
+			} else {
+				if tok[0] == '-' {
+					sign = -1
+				}
+				rest = append([]string{tok[1:]}, rest[1:]...)
+			}
+			o, used, err := parseAmount(rest)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%v in %q", err, input)
+			}
+			if day != nil || shift != nil {
+				return time.Time{}, fmt.Errorf("more than one date in %q", input)
+			}
+			if sign < 0 {
+				o = offset{months: -o.months, days: -o.days, duration: -o.duration}
+			}
+			shift = &o
+			i += used - 1
+
+		case isoDateRe.MatchString(tok):
+			m := isoDateRe.FindStringSubmatch(tok)
+			d, err := makeDate(atoi(m[1]), atoi(m[2]), atoi(m[3]), loc)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if err := setDay(d); err != nil {
+				return time.Time{}, err
+			}
+
+		case isMonth(tok):
+			// dec 25 [2026]
+			if !isNumber(next) {
+				return time.Time{}, fmt.Errorf("expected a day after %q in %q", tok, input)
+			}
+			i++
+			d, used, err := monthDay(months[tok], atoi(next), tokens[i+1:], today)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if err := setDay(d); err != nil {
+				return time.Time{}, err
+			}
+			i += used
+
+		case isNumber(tok) && isMonth(next):
+			// 25 dec [2026]
+			i++
+			d, used, err := monthDay(months[next], atoi(tok), tokens[i+1:], today)
+			if err != nil {
+				return time.Time{}, err
+			}
+			if err := setDay(d); err != nil {
+				return time.Time{}, err
+			}
+			i += used
+
+		case isNumber(tok) && (next == "am" || next == "pm"):
+			// 3 pm
+			c, ok := parseClock(tok + next)
+			if !ok {
+				return time.Time{}, fmt.Errorf("can't read time %q", tok+" "+next)
+			}
+			if err := setClock(c); err != nil {
+				return time.Time{}, err
+			}
+			i++
+
+		default:
+			c, ok := parseClock(tok)
+			if !ok {
+				return time.Time{}, fmt.Errorf("don't know what %q means", tok)
+			}
+			if err := setClock(c); err != nil {
+				return time.Time{}, err
+			}
+		}
+	}
+
+	if tonight && clock == nil {
+		c := 20 * time.Hour
+		clock = &c
+	}
+
+	switch {
+	case shift != nil && clock != nil:
+		// "+3d 9am": move by the offset, then pin the time of day
+		return at(shift.apply(now), *clock), nil
+	case shift != nil:
+		return shift.apply(now), nil
+	case day != nil && clock != nil:
+		return at(*day, *clock), nil
+	case day != nil:
+		return at(*day, dayTime), nil
+	case clock != nil:
+		// A bare time means the next time the clock shows it
+		t := at(today, *clock)
+		if t.Before(now) {
+			t = at(today.AddDate(0, 0, 1), *clock)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("can't read %q as a date", input)
+}
+
+// ParseEnd resolves the end of something that starts at start. A duration
+// ("1h30m", "90m", "+45m", "2 hours") is added to start; a bare time such as
+// "16:00" lands on start's day (or the day after if that would be before
+// start); anything else is parsed like Parse.
+func ParseEnd(input string, start time.Time) (time.Time, error) {
+	s := normalize(input)
+	if s == "" {
+		return time.Time{}, ErrEmpty
+	}
+
+	if d, ok := ParseDuration(s); ok {
+		return start.Add(d), nil
+	}
+
+	if c, ok := parseClock(strings.ReplaceAll(s, " ", "")); ok {
+		t := at(start, c)
+		if t.Before(start) {
+			t = at(start.AddDate(0, 0, 1), c)
+		}
+		return t, nil
+	}
+
+	return Parse(s, start, 0)
+}
+
+// ParseDuration reads lengths like "1h30m", "90m", "+2h", "for 2 hours"
+func ParseDuration(input string) (time.Duration, bool) {
+	s := strings.TrimPrefix(normalize(input), "for ")
+	s = strings.TrimPrefix(s, "+")
+	if compoundRe.MatchString(s) {
+		d, err := time.ParseDuration(s)
+		return d, err == nil && d > 0
+	}
+
+	o, used, err := parseAmount(strings.Fields(s))
+	if err != nil || used != len(strings.Fields(s)) || o.months != 0 {
+		return 0, false
+	}
+	d := time.Duration(o.days)*24*time.Hour + o.duration
+	return d, d > 0
+}
+
+// Describe renders t for the live preview under date inputs,
+// e.g. "Fri, Dec 26 at 3:00 PM (tomorrow)".
+func Describe(t, now time.Time) string {
+	layout := "Mon, Jan 2 at 3:04 PM"
+	if t.Year() != now.Year() {
+		layout = "Mon, Jan 2 2006 at 3:04 PM"
+	}
+	return fmt.Sprintf("%s (%s)", t.Format(layout), relativeDay(t, now))
+}
+
+// FormatDuration renders a duration the way people write it: 1h30m, 45m, 2d
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+
+	var b strings.Builder
+	if days > 0 {
+		fmt.Fprintf(&b, "%dd", days)
+	}
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dh", hours)
+	}
+	if minutes > 0 || b.Len() == 0 {
+		fmt.Fprintf(&b, "%dm", minutes)
+	}
+	return b.String()
+}
+
+func relativeDay(t, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+	days := int(day.Sub(today).Round(24*time.Hour) / (24 * time.Hour))
+
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 1:
+		return fmt.Sprintf("in %d days", days)
+	default:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+}
+
+// parseAmount reads "2h", "1h30m", "3 days", "2 weeks" from the start of
+// tokens and reports how many tokens it used.
+func parseAmount(tokens []string) (offset, int, error) {
+	if len(tokens) == 0 || tokens[0] == "" {
+		return offset{}, 0, errors.New("missing amount")
+	}
+
+	if compoundRe.MatchString(tokens[0]) {
+		d, err := time.ParseDuration(tokens[0])
+		if err != nil {
+			return offset{}, 0, err
+		}
+		return offset{duration: d}, 1, nil
+	}
+
+	m := amountRe.FindStringSubmatch(tokens[0])
+	if m == nil {
+		return offset{}, 0, fmt.Errorf("can't read amount %q", tokens[0])
+	}
+	n := atoi(m[1])
+	unit := m[2]
+	used := 1
+	if unit == "" {
+		if len(tokens) < 2 {
+			return offset{}, 0, fmt.Errorf("missing unit after %d", n)
+		}
+		unit = tokens[1]
+		used = 2
+	}
+
+	switch unit {
+	case "m", "min", "mins", "minute", "minutes":
+		return offset{duration: time.Duration(n) * time.Minute}, used, nil
+	case "h", "hr", "hrs", "hour", "hours":
+		return offset{duration: time.Duration(n) * time.Hour}, used, nil
+	case "d", "day", "days":
+		return offset{days: n}, used, nil
+	case "w", "wk", "wks", "week", "weeks":
+		return offset{days: 7 * n}, used, nil
+	case "mo", "month", "months":
+		return offset{months: n}, used, nil
+	}
+	return offset{}, 0, fmt.Errorf("unknown unit %q", unit)
+}
+
+// parseClock reads 3pm, 3:30pm, 9a, 15:04 as an offset from midnight. A bare
+// number is not a time (it is too easy to confuse with a day of the month).
+func parseClock(s string) (time.Duration, bool) {
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return 0, false
+	}
+
+	hour := atoi(m[1])
+	minute := 0
+	if m[2] != "" {
+		minute = atoi(m[2])
+	}
+	if minute > 59 {
+		return 0, false
+	}
+
+	switch m[3] {
+	case "am", "a":
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm", "p":
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		if hour != 12 {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, false
+		}
+	}
+
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+// at sets the wall clock of day to clock, an offset from midnight. Adding
+// clock to midnight instead would be an hour off on the days DST changes.
+func at(day time.Time, clock time.Duration) time.Time {
+	hour := int(clock / time.Hour)
+	minute := int(clock % time.Hour / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// nextWeekday finds the next wd on or after today. With includeToday false
+// the search starts tomorrow, so "next fri" on a Friday means a week later.
+func nextWeekday(today time.Time, wd time.Weekday, includeToday bool) time.Time {
+	days := (int(wd) - int(today.Weekday()) + 7) % 7
+	if days == 0 && !includeToday {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// monthDay builds a date from a month and day, taking an optional year from
+// rest. Without a year the date rolls over to next year once it has passed.
+func monthDay(month time.Month, dayOfMonth int, rest []string, today time.Time) (time.Time, int, error) {
+	if len(rest) > 0 && isNumber(rest[0]) && len(rest[0]) == 4 {
+		d, err := makeDate(atoi(rest[0]), int(month), dayOfMonth, today.Location())
+		return d, 1, err
+	}
+
+	d, err := makeDate(today.Year(), int(month), dayOfMonth, today.Location())
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	if d.Before(today) {
+		d = d.AddDate(1, 0, 0)
+	}
+	return d, 0, nil
+}
+
+func makeDate(year, month, dayOfMonth int, loc *time.Location) (time.Time, error) {
+	d := time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, loc)
+	// time.Date normalizes Feb 30 into March, which is never what was meant
+	if d.Month() != time.Month(month) || d.Day() != dayOfMonth {
+		return time.Time{}, fmt.Errorf("%04d-%02d-%02d is not a real date", year, month, dayOfMonth)
+	}
+	return d, nil
+}
+
+func normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, ",", " ")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func isWeekday(s string) bool {
+	_, ok := weekdays[s]
+	return ok
+}
+
+func isMonth(s string) bool {
+	_, ok := months[s]
+	return ok
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
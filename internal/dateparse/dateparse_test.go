@@ -0,0 +1,225 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // America/New_York without relying on the system zoneinfo
+)
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestParse(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+	dayTime := 9 * time.Hour
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		// Absolute dates
+		{"2026-12-25 14:30", date(12, 25, 14, 30)},
+		{"2026/12/25", date(12, 25, 9, 0)},
+		{"dec 25", date(12, 25, 9, 0)},
+		{"25 dec 2027", time.Date(2027, 12, 25, 9, 0, 0, 0, time.UTC)},
+		{"jan 3", time.Date(2027, 1, 3, 9, 0, 0, 0, time.UTC)}, // passed, rolls over
+		{"Oct 14, 5pm", date(10, 14, 17, 0)},
+
+		// Relative days
+		{"now", now},
+		{"today", date(10, 14, 9, 0)},
+		{"tonight", date(10, 14, 20, 0)},
+		{"tonight 9pm", date(10, 14, 21, 0)},
+		{"9pm tonight", date(10, 14, 21, 0)},
+		{"tonight at 10:30pm", date(10, 14, 22, 30)},
+		{"tomorrow", date(10, 15, 9, 0)},
+		{"tmr 3pm", date(10, 15, 15, 0)},
+		{"yesterday noon", date(10, 13, 12, 0)},
+		{"next week", date(10, 21, 9, 0)},
+		{"next month", date(11, 14, 9, 0)},
+
+		// Weekdays
+		{"wed", date(10, 14, 9, 0)},
+		{"next wed", date(10, 21, 9, 0)},
+		{"fri", date(10, 16, 9, 0)},
+		{"next fri", date(10, 16, 9, 0)},
+		{"mon 09:30", date(10, 19, 9, 30)},
+		{"on tuesday at 7pm", date(10, 20, 19, 0)},
+
+		// Times
+		{"3pm", date(10, 14, 15, 0)},
+		{"3 pm", date(10, 14, 15, 0)},
+		{"9:30am", date(10, 15, 9, 30)}, // already passed today
+		{"15:04", date(10, 14, 15, 4)},
+		{"12am", date(10, 15, 0, 0)},
+		{"midnight", date(10, 15, 0, 0)},
+
+		// Offsets
+		{"in 2h", date(10, 14, 12, 0)},
+		{"in 1h30m", date(10, 14, 11, 30)},
+		{"in 3 days", date(10, 17, 10, 0)},
+		{"+3d", date(10, 17, 10, 0)},
+		{"-1w", date(10, 7, 10, 0)},
+		{"+3d 9am", date(10, 17, 9, 0)},
+		{"in 2 months", date(12, 14, 10, 0)},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input, now, dayTime)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+
+	for _, input := range []string{
+		"",
+		"   ",
+		"soon",
+		"feb 30",
+		"2026-13-01",
+		"tomorrow today",
+		"3pm 4pm",
+		"next",
+		"next year",
+		"25",
+		"13pm",
+		"in 3 fortnights",
+	} {
+		if got, err := Parse(input, now, 0); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", input, got)
+		}
+	}
+}
+
+// On the day DST starts the clock jumps from 2:00 to 3:00, so the day is 23
+// hours long. Times of day must still land on the wall clock asked for.
+func TestParseDST(t *testing.T) {
+	loc := newYork(t)
+	springBefore := time.Date(2026, 3, 7, 10, 0, 0, 0, loc) // the day before DST starts
+	fallBefore := time.Date(2026, 10, 31, 10, 0, 0, 0, loc) // the day before DST ends
+
+	tests := []struct {
+		input string
+		now   time.Time
+		want  time.Time
+	}{
+		{"tomorrow 9am", springBefore, time.Date(2026, 3, 8, 9, 0, 0, 0, loc)},
+		{"tomorrow 3pm", springBefore, time.Date(2026, 3, 8, 15, 0, 0, 0, loc)},
+		{"tomorrow", springBefore, time.Date(2026, 3, 8, 9, 0, 0, 0, loc)},
+		{"sun 23:30", springBefore, time.Date(2026, 3, 8, 23, 30, 0, 0, loc)},
+		{"+1d 8pm", springBefore, time.Date(2026, 3, 8, 20, 0, 0, 0, loc)},
+		{"+1d", springBefore, time.Date(2026, 3, 8, 10, 0, 0, 0, loc)},
+		{"in 24h", springBefore, time.Date(2026, 3, 8, 11, 0, 0, 0, loc)},
+		// 2:30 doesn't exist that night, it resolves the way time.Date does
+		{"march 8 2:30am", springBefore, time.Date(2026, 3, 8, 2, 30, 0, 0, loc)},
+		{"march 8 1:30am", springBefore, time.Date(2026, 3, 8, 1, 30, 0, 0, loc)},
+
+		{"tomorrow 9am", fallBefore, time.Date(2026, 11, 1, 9, 0, 0, 0, loc)},
+		{"tomorrow 3pm", fallBefore, time.Date(2026, 11, 1, 15, 0, 0, 0, loc)},
+		{"nov 1 23:00", fallBefore, time.Date(2026, 11, 1, 23, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input, tt.now, 9*time.Hour)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseEnd(t *testing.T) {
+	loc := newYork(t)
+	start := time.Date(2026, 10, 14, 10, 0, 0, 0, loc)
+
+	tests := []struct {
+		input string
+		start time.Time
+		want  time.Time
+	}{
+		{"1h30m", start, time.Date(2026, 10, 14, 11, 30, 0, 0, loc)},
+		{"90m", start, time.Date(2026, 10, 14, 11, 30, 0, 0, loc)},
+		{"+45m", start, time.Date(2026, 10, 14, 10, 45, 0, 0, loc)},
+		{"2 hours", start, time.Date(2026, 10, 14, 12, 0, 0, 0, loc)},
+		{"16:00", start, time.Date(2026, 10, 14, 16, 0, 0, 0, loc)},
+		{"9am", start, time.Date(2026, 10, 15, 9, 0, 0, 0, loc)}, // before start, next day
+		{"tomorrow 5pm", start, time.Date(2026, 10, 15, 17, 0, 0, 0, loc)},
+
+		// Across the night DST starts
+		{"16:00", time.Date(2026, 3, 7, 23, 0, 0, 0, loc), time.Date(2026, 3, 8, 16, 0, 0, 0, loc)},
+		{"4am", time.Date(2026, 3, 7, 23, 0, 0, 0, loc), time.Date(2026, 3, 8, 4, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseEnd(tt.input, tt.start)
+		if err != nil {
+			t.Errorf("ParseEnd(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseEnd(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+		ok    bool
+	}{
+		{"1h30m", 90 * time.Minute, true},
+		{"90m", 90 * time.Minute, true},
+		{"+2h", 2 * time.Hour, true},
+		{"for 2 hours", 2 * time.Hour, true},
+		{"2 days", 48 * time.Hour, true},
+		{"0m", 0, false},
+		{"1 month", 0, false},
+		{"3pm", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseDuration(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                               "0m",
+		45 * time.Minute:                "45m",
+		90 * time.Minute:                "1h30m",
+		2 * time.Hour:                   "2h",
+		50*time.Hour + 5*time.Minute:    "2d2h5m",
+		29*time.Second + time.Minute:    "1m",
+		31*time.Second + 59*time.Minute: "1h",
+	}
+	for d, want := range tests {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
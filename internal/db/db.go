@@ -18,20 +18,31 @@ func Init(dbPath string) error {
 		return fmt.Errorf("failed to create db directory: %w", err)
 	}
 	var err error
-	DB, err = sql.Open("sqlite3", dbPath)
+	// Foreign keys are off by default in SQLite, join tables rely on ON DELETE CASCADE
+	DB, err = sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
 	if err != nil {
 		return fmt.Errorf("failed to open db: %w", err)
 	}
 	if err := DB.Ping(); err != nil {
 		return fmt.Errorf("failed to connect to db: %w", err)
 	}
-	if err := createTables(); err != nil {
-		return fmt.Errorf("failed to create tables: %w", err)
+	if err := migrate(DB, dbPath); err != nil {
+		DB.Close()
+		DB = nil
+		return fmt.Errorf("failed to migrate db: %w", err)
+	}
+	if err := setupSearch(DB); err != nil {
+		DB.Close()
+		DB = nil
+		return err
 	}
 	return nil
 }
 
-func createTables() error {
+// createTables is migration 1, the schema as it existed before versioning.
+// It keeps IF NOT EXISTS so databases created by older builds (which are at
+// user_version 0 but already have these tables) migrate cleanly.
+func createTables(tx *sql.Tx) error {
 	// Create Notes table
 	notesTable := `
 	CREATE TABLE IF NOT EXISTS notes (
@@ -71,7 +82,7 @@ func createTables() error {
 	// Execute table creation statements
 	tables := []string{notesTable, todosTable, eventsTable}
 	for _, table := range tables {
-		if _, err := DB.Exec(table); err != nil {
+		if _, err := tx.Exec(table); err != nil {
 			return fmt.Errorf("failed to create table: %w", err)
 		}
 	}
@@ -91,3 +102,25 @@ func Get() *sql.DB {
 	}
 	return nil
 }
+
+// inTx runs fn inside a transaction, rolling back if it returns an error
+func inTx(conn *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// nullID stores an unset (zero) id reference as NULL
+func nullID(id int) any {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
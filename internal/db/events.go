@@ -0,0 +1,217 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// EventStore is the SQLite implementation of models.EventStore
+type EventStore struct {
+	db *sql.DB
+}
+
+func NewEventStore(conn *sql.DB) *EventStore {
+	return &EventStore{db: conn}
+}
+
+func (s *EventStore) LoadEvents() ([]*models.Event, error) {
+	return s.queryEvents("deleted_at IS NULL ORDER BY start_time")
+}
+
+func (s *EventStore) LoadDeletedEvents() ([]*models.Event, error) {
+	return s.queryEvents("deleted_at IS NOT NULL ORDER BY deleted_at DESC, id")
+}
+
+// queryEvents loads the events matching where, which also orders them
+func (s *EventStore) queryEvents(where string) ([]*models.Event, error) {
+	query := "SELECT id, title, description, location, start_time, end_time, timezone, recurrence, deleted_at, pinned FROM events WHERE " + where
+
+	tags, err := loadTags(s.db, eventTags)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := loadExceptions(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.Event{}
+	for rows.Next() {
+		var id int
+		var title, description, location, timezone, recurrence string
+		var startTime, endTime time.Time
+		var deletedAt sql.NullTime
+		var pinned bool
+
+		if err := rows.Scan(&id, &title, &description, &location, &startTime, &endTime, &timezone, &recurrence, &deletedAt, &pinned); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+
+		event := &models.Event{
+			ID:         id,
+			Title:      title,
+			Content:    description,
+			Location:   location,
+			StartTime:  fromDBTime(startTime),
+			EndTime:    fromDBTime(endTime),
+			Timezone:   timezone,
+			Tags:       tags[id],
+			Recurrence: recurrence,
+			Exceptions: exceptions[id],
+			Pinned:     pinned,
+		}
+		if deletedAt.Valid {
+			event.DeletedAt = fromDBTime(deletedAt.Time)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating events: %w", err)
+	}
+
+	return events, nil
+}
+
+func (s *EventStore) InsertEvent(event *models.Event) (int, error) {
+	query := `INSERT INTO events (title, description, location, start_time, end_time, timezone, recurrence, created_at, pinned)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	var id int
+	err := inTx(s.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, event.Title, event.Content, event.Location,
+			toDBTime(event.StartTime), toDBTime(event.EndTime), event.Timezone, event.Recurrence, toDBTime(time.Now()), event.Pinned)
+		if err != nil {
+			return fmt.Errorf("failed to add event to database: %w", err)
+		}
+
+		lastID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		id = int(lastID)
+
+		if err := setExceptions(tx, id, event.Exceptions); err != nil {
+			return err
+		}
+		return setTags(tx, eventTags, id, event.Tags)
+	})
+	return id, err
+}
+
+func (s *EventStore) UpdateEvent(event *models.Event) error {
+	query := `UPDATE events SET title=?, description=?, location=?, start_time=?, end_time=?, timezone=?, recurrence=?, pinned=?, updated_at=CURRENT_TIMESTAMP
+	          WHERE id=?`
+
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(query, event.Title, event.Content, event.Location,
+			toDBTime(event.StartTime), toDBTime(event.EndTime), event.Timezone, event.Recurrence, event.Pinned, event.ID); err != nil {
+			return fmt.Errorf("failed to update event in database: %w", err)
+		}
+		if err := setExceptions(tx, event.ID, event.Exceptions); err != nil {
+			return err
+		}
+		return setTags(tx, eventTags, event.ID, event.Tags)
+	})
+}
+
+// PutEvent writes the event as given, recreating it under its own id if it
+// was deleted for good
+func (s *EventStore) PutEvent(event *models.Event) error {
+	query := `INSERT INTO events (id, title, description, location, start_time, end_time, timezone, recurrence, created_at, deleted_at, pinned)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(id) DO UPDATE SET title=excluded.title, description=excluded.description, location=excluded.location,
+	          start_time=excluded.start_time, end_time=excluded.end_time, timezone=excluded.timezone, recurrence=excluded.recurrence,
+	          deleted_at=excluded.deleted_at, pinned=excluded.pinned, updated_at=CURRENT_TIMESTAMP`
+
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(query, event.ID, event.Title, event.Content, event.Location, toDBTime(event.StartTime),
+			toDBTime(event.EndTime), event.Timezone, event.Recurrence, toDBTime(time.Now()),
+			toDBTimeOrNull(event.DeletedAt), event.Pinned); err != nil {
+			return fmt.Errorf("failed to write event to database: %w", err)
+		}
+		if err := setExceptions(tx, event.ID, event.Exceptions); err != nil {
+			return err
+		}
+		return setTags(tx, eventTags, event.ID, event.Tags)
+	})
+}
+
+// DeleteEvent moves the event, with all its occurrences, to the trash
+func (s *EventStore) DeleteEvent(id int) error {
+	if _, err := s.db.Exec(`UPDATE events SET deleted_at=? WHERE id=?`, toDBTime(time.Now()), id); err != nil {
+		return fmt.Errorf("failed to move event to trash: %w", err)
+	}
+	return nil
+}
+
+func (s *EventStore) RestoreEvent(id int) error {
+	if _, err := s.db.Exec(`UPDATE events SET deleted_at=NULL WHERE id=?`, id); err != nil {
+		return fmt.Errorf("failed to restore event: %w", err)
+	}
+	return nil
+}
+
+// PurgeEvent deletes the event for good
+func (s *EventStore) PurgeEvent(id int) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM events WHERE id=?`, id); err != nil {
+			return fmt.Errorf("failed to delete event from database: %w", err)
+		}
+		if err := pruneLinks(tx); err != nil {
+			return err
+		}
+		return pruneTags(tx)
+	})
+}
+
+// loadExceptions returns the skipped occurrences of every recurring event,
+// keyed by event id
+func loadExceptions(q querier) (map[int][]time.Time, error) {
+	rows, err := q.Query(`SELECT event_id, occurrence FROM event_exceptions ORDER BY occurrence`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	exceptions := map[int][]time.Time{}
+	for rows.Next() {
+		var id int
+		var occurrence time.Time
+		if err := rows.Scan(&id, &occurrence); err != nil {
+			return nil, fmt.Errorf("failed to scan event exception: %w", err)
+		}
+		exceptions[id] = append(exceptions[id], fromDBTime(occurrence))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating event exceptions: %w", err)
+	}
+
+	return exceptions, nil
+}
+
+// setExceptions replaces the skipped occurrences of an event
+func setExceptions(q querier, id int, exceptions []time.Time) error {
+	if _, err := q.Exec(`DELETE FROM event_exceptions WHERE event_id=?`, id); err != nil {
+		return fmt.Errorf("failed to clear event exceptions: %w", err)
+	}
+	for _, occurrence := range exceptions {
+		if _, err := q.Exec(`INSERT OR IGNORE INTO event_exceptions (event_id, occurrence) VALUES (?, ?)`,
+			id, toDBTime(occurrence)); err != nil {
+			return fmt.Errorf("failed to save event exception: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// HistoryStore is the SQLite implementation of models.HistoryStore.
+// The changes of a command are stored as one JSON document.
+type HistoryStore struct {
+	db *sql.DB
+}
+
+func NewHistoryStore(conn *sql.DB) *HistoryStore {
+	return &HistoryStore{db: conn}
+}
+
+func (s *HistoryStore) LoadCommands() ([]*models.Command, error) {
+	rows, err := s.db.Query(`SELECT id, label, changes, undone, created_at FROM undo_history ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query undo history: %w", err)
+	}
+	defer rows.Close()
+
+	commands := []*models.Command{}
+	for rows.Next() {
+		var id int
+		var label, changes string
+		var undone bool
+		var createdAt time.Time
+
+		if err := rows.Scan(&id, &label, &changes, &undone, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan undo history: %w", err)
+		}
+
+		cmd := &models.Command{
+			ID:        id,
+			Label:     label,
+			Undone:    undone,
+			CreatedAt: fromDBTime(createdAt),
+		}
+		if err := json.Unmarshal([]byte(changes), &cmd.Changes); err != nil {
+			return nil, fmt.Errorf("failed to read undo history entry %d: %w", id, err)
+		}
+
+		commands = append(commands, cmd)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating undo history: %w", err)
+	}
+
+	return commands, nil
+}
+
+func (s *HistoryStore) InsertCommand(cmd *models.Command) (int, error) {
+	changes, err := json.Marshal(cmd.Changes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode undo history: %w", err)
+	}
+
+	result, err := s.db.Exec(`INSERT INTO undo_history (label, changes, undone, created_at) VALUES (?, ?, ?, ?)`,
+		cmd.Label, string(changes), cmd.Undone, toDBTime(cmd.CreatedAt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to save undo history: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return int(id), nil
+}
+
+func (s *HistoryStore) SetCommandUndone(id int, undone bool) error {
+	if _, err := s.db.Exec(`UPDATE undo_history SET undone=? WHERE id=?`, undone, id); err != nil {
+		return fmt.Errorf("failed to update undo history: %w", err)
+	}
+	return nil
+}
+
+func (s *HistoryStore) DeleteCommands(ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	query := `DELETE FROM undo_history WHERE id IN (?` + strings.Repeat(", ?", len(ids)-1) + `)`
+	if _, err := s.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to trim undo history: %w", err)
+	}
+	return nil
+}
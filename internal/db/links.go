@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// LinkStore is the SQLite implementation of models.LinkStore
+type LinkStore struct {
+	db *sql.DB
+}
+
+func NewLinkStore(conn *sql.DB) *LinkStore {
+	return &LinkStore{db: conn}
+}
+
+func (s *LinkStore) LoadLinks() ([]*models.Link, error) {
+	rows, err := s.db.Query(`SELECT id, from_page, from_id, to_page, to_id, wiki, created_at FROM links ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query links: %w", err)
+	}
+	defer rows.Close()
+
+	links := []*models.Link{}
+	for rows.Next() {
+		var link models.Link
+		var createdAt time.Time
+
+		if err := rows.Scan(&link.ID, &link.From.Page, &link.From.ID, &link.To.Page, &link.To.ID, &link.Wiki, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan link: %w", err)
+		}
+		link.CreatedAt = fromDBTime(createdAt)
+
+		links = append(links, &link)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating links: %w", err)
+	}
+
+	return links, nil
+}
+
+func (s *LinkStore) InsertLink(link *models.Link) (int, error) {
+	result, err := s.db.Exec(`INSERT INTO links (from_page, from_id, to_page, to_id, wiki, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		link.From.Page, link.From.ID, link.To.Page, link.To.ID, link.Wiki, toDBTime(link.CreatedAt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to save link: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return int(id), nil
+}
+
+// PutLink writes the link as given, recreating it under its own id if it
+// was deleted
+func (s *LinkStore) PutLink(link *models.Link) error {
+	query := `INSERT INTO links (id, from_page, from_id, to_page, to_id, wiki, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(id) DO UPDATE SET from_page=excluded.from_page, from_id=excluded.from_id,
+	          to_page=excluded.to_page, to_id=excluded.to_id, wiki=excluded.wiki`
+
+	if _, err := s.db.Exec(query, link.ID, link.From.Page, link.From.ID, link.To.Page, link.To.ID, link.Wiki, toDBTime(link.CreatedAt)); err != nil {
+		return fmt.Errorf("failed to write link: %w", err)
+	}
+	return nil
+}
+
+func (s *LinkStore) DeleteLink(id int) error {
+	if _, err := s.db.Exec(`DELETE FROM links WHERE id=?`, id); err != nil {
+		return fmt.Errorf("failed to delete link: %w", err)
+	}
+	return nil
+}
+
+// linkTables maps the page a linked item lives on to its table
+var linkTables = map[models.PageType]string{
+	models.PageTodos:    "todos",
+	models.PageNotes:    "notes",
+	models.PageCalendar: "events",
+}
+
+// pruneLinks drops the links of items that were deleted for good.
+// Trashed items keep theirs for when they are restored.
+func pruneLinks(q querier) error {
+	for page, table := range linkTables {
+		query := fmt.Sprintf(`DELETE FROM links WHERE
+			(from_page = %[1]d AND from_id NOT IN (SELECT id FROM %[2]s)) OR
+			(to_page = %[1]d AND to_id NOT IN (SELECT id FROM %[2]s))`, page, table)
+		if _, err := q.Exec(query); err != nil {
+			return fmt.Errorf("failed to prune links: %w", err)
+		}
+	}
+	return nil
+}
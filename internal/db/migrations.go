@@ -0,0 +1,295 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when the database was written by a newer
+// version of the app than the one currently running.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary supports")
+
+// migration is a single, ordered schema change. The schema version is kept
+// in PRAGMA user_version, so migrations are append-only: never edit or
+// reorder one that has shipped, add a new one at the end instead.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+var migrations = []migration{
+	{1, "create notes, todos and events tables", createTables},
+	{2, "store timestamps as UTC and add events.timezone", canonicalTimestamps},
+	{3, "add tags", execAll(
+		`CREATE TABLE tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE COLLATE NOCASE
+		)`,
+		`CREATE TABLE todo_tags (
+			todo_id INTEGER NOT NULL REFERENCES todos(id) ON DELETE CASCADE,
+			tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (todo_id, tag_id)
+		)`,
+		`CREATE TABLE note_tags (
+			note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (note_id, tag_id)
+		)`,
+		`CREATE TABLE event_tags (
+			event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+			PRIMARY KEY (event_id, tag_id)
+		)`,
+	)},
+	{4, "add recurring todos", execAll(
+		`ALTER TABLE todos ADD COLUMN recurrence TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE todos ADD COLUMN series_id INTEGER`,
+		`CREATE TABLE todo_completions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			series_id INTEGER NOT NULL,
+			todo_id INTEGER REFERENCES todos(id) ON DELETE SET NULL,
+			due_date DATETIME,
+			completed_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX idx_todo_completions_series ON todo_completions(series_id)`,
+	)},
+	{5, "add recurring events", execAll(
+		`ALTER TABLE events ADD COLUMN recurrence TEXT NOT NULL DEFAULT ''`,
+		`CREATE TABLE event_exceptions (
+			event_id INTEGER NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+			occurrence DATETIME NOT NULL,
+			PRIMARY KEY (event_id, occurrence)
+		)`,
+	)},
+	{6, "add subtasks", execAll(
+		`ALTER TABLE todos ADD COLUMN parent_id INTEGER REFERENCES todos(id) ON DELETE CASCADE`,
+		`CREATE INDEX idx_todos_parent ON todos(parent_id)`,
+	)},
+	{7, "add trash", execAll(
+		`ALTER TABLE todos ADD COLUMN deleted_at DATETIME`,
+		`ALTER TABLE notes ADD COLUMN deleted_at DATETIME`,
+		`ALTER TABLE events ADD COLUMN deleted_at DATETIME`,
+	)},
+	{8, "add undo history", execAll(
+		`CREATE TABLE undo_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			label TEXT NOT NULL,
+			changes TEXT NOT NULL,
+			undone BOOLEAN NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL
+		)`,
+	)},
+	{9, "add saved views", execAll(
+		`CREATE TABLE saved_views (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			page INTEGER NOT NULL,
+			query TEXT NOT NULL DEFAULT '',
+			filter INTEGER NOT NULL DEFAULT 0,
+			sort TEXT NOT NULL DEFAULT '',
+			on_dashboard BOOLEAN NOT NULL DEFAULT 0,
+			created_at DATETIME NOT NULL
+		)`,
+	)},
+	{10, "add links", execAll(
+		`CREATE TABLE links (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			from_page INTEGER NOT NULL,
+			from_id INTEGER NOT NULL,
+			to_page INTEGER NOT NULL,
+			to_id INTEGER NOT NULL,
+			created_at DATETIME NOT NULL,
+			UNIQUE(from_page, from_id, to_page, to_id)
+		)`,
+		`CREATE INDEX idx_links_to ON links(to_page, to_id)`,
+	)},
+	{11, "add wiki links", execAll(
+		`ALTER TABLE links ADD COLUMN wiki BOOLEAN NOT NULL DEFAULT 0`,
+	)},
+	{12, "add journal notes", execAll(
+		`ALTER TABLE notes ADD COLUMN journal_date TEXT NOT NULL DEFAULT ''`,
+		`CREATE INDEX idx_notes_journal ON notes(journal_date) WHERE journal_date != ''`,
+	)},
+	{13, "add notebooks", execAll(
+		`CREATE TABLE notebooks (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			parent_id INTEGER REFERENCES notebooks(id),
+			created_at DATETIME NOT NULL
+		)`,
+		// No foreign key: undo may put a note back into a notebook deleted
+		// since, the note then counts as unfiled
+		`ALTER TABLE notes ADD COLUMN notebook_id INTEGER`,
+		`CREATE INDEX idx_notes_notebook ON notes(notebook_id)`,
+	)},
+	{14, "add pinned items", execAll(
+		`ALTER TABLE todos ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT 0`,
+		`ALTER TABLE notes ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT 0`,
+		`ALTER TABLE events ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT 0`,
+	)},
+	{15, "add note revisions", execAll(
+		`CREATE TABLE note_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+			title TEXT NOT NULL,
+			content TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		)`,
+		`CREATE INDEX idx_note_revisions_note ON note_revisions(note_id)`,
+	)},
+	// Filters used to be one list shared by every page, now each page
+	// numbers its own. Todos kept their numbers. Notes never filtered. The
+	// calendar read today, overdue and pending as today, past and upcoming
+	// and ignored the rest.
+	{16, "number saved view filters per page", execAll(
+		`UPDATE saved_views SET filter = 0 WHERE page = 2`,
+		`UPDATE saved_views SET filter = CASE filter
+			WHEN 6 THEN 1
+			WHEN 7 THEN 3
+			WHEN 1 THEN 4
+			ELSE 0
+		END WHERE page = 3`,
+	)},
+}
+
+// SchemaVersion returns the latest schema version this binary knows about
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate brings the database at dbPath up to SchemaVersion. Every pending
+// migration runs in its own transaction together with the version bump, so a
+// failure leaves the database at the last successfully applied version.
+func migrate(conn *sql.DB, dbPath string) error {
+	current, err := userVersion(conn)
+	if err != nil {
+		return err
+	}
+
+	latest := SchemaVersion()
+	if current > latest {
+		return fmt.Errorf("%w (database is v%d, binary supports up to v%d)", ErrSchemaTooNew, current, latest)
+	}
+	if current == latest {
+		return nil
+	}
+
+	if err := backup(conn, dbPath, current); err != nil {
+		return fmt.Errorf("failed to back up database before migrating: %w", err)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := apply(conn, m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
+
+func apply(conn *sql.DB, m migration) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return err
+	}
+	// PRAGMA does not accept bound parameters, version is an int we control
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// execAll builds a migration out of plain SQL statements
+func execAll(stmts ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func userVersion(conn *sql.DB) (int, error) {
+	var version int
+	if err := conn.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// backup snapshots an existing database next to the original file, e.g.
+// data.db.v1-20251225-143000.bak. Fresh databases have nothing to lose and
+// are not backed up.
+func backup(conn *sql.DB, dbPath string, version int) error {
+	if dbPath == "" || dbPath == ":memory:" {
+		return nil
+	}
+
+	var tables int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table'").Scan(&tables); err != nil {
+		return err
+	}
+	if tables == 0 {
+		return nil
+	}
+
+	backupPath := fmt.Sprintf("%s.v%d-%s.bak", dbPath, version, time.Now().Format("20060102-150405"))
+	if _, err := os.Stat(backupPath); err == nil {
+		return fmt.Errorf("backup %s already exists", backupPath)
+	}
+	if _, err := conn.Exec("VACUUM INTO ?", backupPath); err != nil {
+		return err
+	}
+	return nil
+}
+
+// canonicalTimestamps rewrites every timestamp into the UTC form described in
+// time.go. Older builds stored whatever offset the time.Time carried, and
+// EventForm parsed with time.Parse, so event times typed as local wall clock
+// were saved as +00:00. Those are reinterpreted as local time here.
+func canonicalTimestamps(tx *sql.Tx) error {
+	stmts := []string{
+		`UPDATE events SET
+			start_time = datetime(substr(start_time, 1, 19), 'utc')
+		WHERE start_time LIKE '%+00:00' AND start_time NOT LIKE '0001-01-01%'`,
+		`UPDATE events SET
+			end_time = datetime(substr(end_time, 1, 19), 'utc')
+		WHERE end_time LIKE '%+00:00' AND end_time NOT LIKE '0001-01-01%'`,
+	}
+
+	columns := map[string][]string{
+		"notes":  {"created_at", "updated_at"},
+		"todos":  {"due_date", "created_at", "updated_at"},
+		"events": {"start_time", "end_time", "created_at", "updated_at"},
+	}
+	for _, table := range []string{"notes", "todos", "events"} {
+		for _, col := range columns[table] {
+			// COALESCE keeps anything SQLite can't parse instead of nulling it
+			stmts = append(stmts, fmt.Sprintf(
+				"UPDATE %[1]s SET %[2]s = COALESCE(datetime(%[2]s), %[2]s) WHERE %[2]s IS NOT NULL",
+				table, col))
+		}
+	}
+
+	stmts = append(stmts, `ALTER TABLE events ADD COLUMN timezone TEXT NOT NULL DEFAULT ''`)
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
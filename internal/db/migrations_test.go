@@ -0,0 +1,135 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// openRaw opens the database at path without migrating it
+func openRaw(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// migrateTo builds a database at path as a build at schema version left it
+func migrateTo(t *testing.T, path string, version int) {
+	t.Helper()
+	conn := openRaw(t, path)
+	for _, m := range migrations[:version] {
+		if err := apply(conn, m); err != nil {
+			t.Fatalf("migration %d: %v", m.version, err)
+		}
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(path + ".v*.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestMigrateFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	if err := Init(path); err != nil {
+		t.Fatal(err)
+	}
+	defer Close()
+
+	if version, err := userVersion(Get()); err != nil || version != SchemaVersion() {
+		t.Errorf("user_version = %d, %v, want %d", version, err, SchemaVersion())
+	}
+	if got := backups(t, path); len(got) != 0 {
+		t.Errorf("a fresh database was backed up to %v", got)
+	}
+}
+
+func TestMigrateBacksUp(t *testing.T) {
+	// 0 is a database from before versioning, which has the tables already
+	for _, from := range []int{0, 1, 7} {
+		t.Run(fmt.Sprintf("v%d", from), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.db")
+			conn := openRaw(t, path)
+			tx, err := conn.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := createTables(tx); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tx.Exec(`INSERT INTO notes (title, content) VALUES ('Kept', 'before the upgrade')`); err != nil {
+				t.Fatal(err)
+			}
+			if err := tx.Commit(); err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+			migrateTo(t, path, from)
+
+			if err := Init(path); err != nil {
+				t.Fatal(err)
+			}
+			defer Close()
+
+			if version, err := userVersion(Get()); err != nil || version != SchemaVersion() {
+				t.Errorf("user_version = %d, %v, want %d", version, err, SchemaVersion())
+			}
+			var title string
+			if err := Get().QueryRow(`SELECT title FROM notes`).Scan(&title); err != nil || title != "Kept" {
+				t.Errorf("migrated note = %q, %v, want Kept", title, err)
+			}
+
+			saved := backups(t, path)
+			if len(saved) != 1 {
+				t.Fatalf("backups = %v, want one", saved)
+			}
+			if matched, _ := filepath.Match(fmt.Sprintf("%s.v%d-*.bak", path, from), saved[0]); !matched {
+				t.Errorf("backup %s isn't named after v%d", saved[0], from)
+			}
+			old := openRaw(t, saved[0])
+			if version, err := userVersion(old); err != nil || version != from {
+				t.Errorf("backup user_version = %d, %v, want %d", version, err, from)
+			}
+			if err := old.QueryRow(`SELECT title FROM notes`).Scan(&title); err != nil || title != "Kept" {
+				t.Errorf("backed up note = %q, %v, want Kept", title, err)
+			}
+		})
+	}
+}
+
+func TestSchemaTooNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	migrateTo(t, path, len(migrations))
+	conn := openRaw(t, path)
+	if _, err := conn.Exec(fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion()+1)); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	err := Init(path)
+	if err == nil {
+		Close()
+	}
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Init error = %v, want ErrSchemaTooNew", err)
+	}
+	if DB != nil {
+		t.Error("DB is left open after a failed Init")
+	}
+	if got := backups(t, path); len(got) != 0 {
+		t.Errorf("a database that wasn't migrated was backed up to %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// NotebookStore is the SQLite implementation of models.NotebookStore
+type NotebookStore struct {
+	db *sql.DB
+}
+
+func NewNotebookStore(conn *sql.DB) *NotebookStore {
+	return &NotebookStore{db: conn}
+}
+
+func (s *NotebookStore) LoadNotebooks() ([]*models.Notebook, error) {
+	rows, err := s.db.Query(`SELECT id, name, parent_id, created_at FROM notebooks ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notebooks: %w", err)
+	}
+	defer rows.Close()
+
+	notebooks := []*models.Notebook{}
+	for rows.Next() {
+		var notebook models.Notebook
+		var parentID sql.NullInt64
+		var createdAt time.Time
+
+		if err := rows.Scan(&notebook.ID, &notebook.Name, &parentID, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan notebook: %w", err)
+		}
+		notebook.ParentID = int(parentID.Int64)
+		notebook.CreatedAt = fromDBTime(createdAt)
+
+		notebooks = append(notebooks, &notebook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notebooks: %w", err)
+	}
+
+	return notebooks, nil
+}
+
+func (s *NotebookStore) InsertNotebook(notebook *models.Notebook) (int, error) {
+	result, err := s.db.Exec(`INSERT INTO notebooks (name, parent_id, created_at) VALUES (?, ?, ?)`,
+		notebook.Name, nullID(notebook.ParentID), toDBTime(notebook.CreatedAt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to add notebook: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return int(id), nil
+}
+
+func (s *NotebookStore) UpdateNotebook(notebook *models.Notebook) error {
+	_, err := s.db.Exec(`UPDATE notebooks SET name=?, parent_id=? WHERE id=?`,
+		notebook.Name, nullID(notebook.ParentID), notebook.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update notebook: %w", err)
+	}
+	return nil
+}
+
+// PutNotebook writes the notebook as given, recreating it under its own id
+// if it was deleted
+func (s *NotebookStore) PutNotebook(notebook *models.Notebook) error {
+	query := `INSERT INTO notebooks (id, name, parent_id, created_at) VALUES (?, ?, ?, ?)
+	          ON CONFLICT(id) DO UPDATE SET name=excluded.name, parent_id=excluded.parent_id`
+
+	if _, err := s.db.Exec(query, notebook.ID, notebook.Name, nullID(notebook.ParentID), toDBTime(notebook.CreatedAt)); err != nil {
+		return fmt.Errorf("failed to write notebook: %w", err)
+	}
+	return nil
+}
+
+func (s *NotebookStore) DeleteNotebook(id int) error {
+	if _, err := s.db.Exec(`DELETE FROM notebooks WHERE id=?`, id); err != nil {
+		return fmt.Errorf("failed to delete notebook: %w", err)
+	}
+	return nil
+}
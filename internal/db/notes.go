@@ -0,0 +1,217 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// NoteStore is the SQLite implementation of models.NoteStore
+type NoteStore struct {
+	db *sql.DB
+}
+
+func NewNoteStore(conn *sql.DB) *NoteStore {
+	return &NoteStore{db: conn}
+}
+
+func (s *NoteStore) LoadNotes() ([]*models.Note, error) {
+	return s.queryNotes("deleted_at IS NULL ORDER BY id")
+}
+
+func (s *NoteStore) LoadDeletedNotes() ([]*models.Note, error) {
+	return s.queryNotes("deleted_at IS NOT NULL ORDER BY deleted_at DESC, id")
+}
+
+// queryNotes loads the notes matching where, which also orders them
+func (s *NoteStore) queryNotes(where string) ([]*models.Note, error) {
+	query := "SELECT id, title, content, created_at, deleted_at, journal_date, notebook_id, pinned FROM notes WHERE " + where
+
+	tags, err := loadTags(s.db, noteTags)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []*models.Note{}
+	for rows.Next() {
+		var id int
+		var title, content, journalDate string
+		var createdAt time.Time
+		var deletedAt sql.NullTime
+		var notebookID sql.NullInt64
+		var pinned bool
+
+		if err := rows.Scan(&id, &title, &content, &createdAt, &deletedAt, &journalDate, &notebookID, &pinned); err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+
+		note := &models.Note{
+			ID:          id,
+			Title:       title,
+			Content:     content,
+			CreatedAt:   fromDBTime(createdAt),
+			Tags:        tags[id],
+			JournalDate: journalDate,
+			NotebookID:  int(notebookID.Int64),
+			Pinned:      pinned,
+		}
+		if deletedAt.Valid {
+			note.DeletedAt = fromDBTime(deletedAt.Time)
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+func (s *NoteStore) InsertNote(note *models.Note) (int, error) {
+	query := `INSERT INTO notes (title, content, created_at, journal_date, notebook_id, pinned) VALUES (?, ?, ?, ?, ?, ?)`
+
+	var id int
+	err := inTx(s.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, note.Title, note.Content, toDBTime(note.CreatedAt), note.JournalDate, nullID(note.NotebookID), note.Pinned)
+		if err != nil {
+			return fmt.Errorf("failed to add note to database: %w", err)
+		}
+
+		lastID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		id = int(lastID)
+
+		return setTags(tx, noteTags, id, note.Tags)
+	})
+	return id, err
+}
+
+func (s *NoteStore) UpdateNote(note *models.Note) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		return updateNote(tx, note)
+	})
+}
+
+func updateNote(tx *sql.Tx, note *models.Note) error {
+	query := `UPDATE notes SET title=?, content=?, notebook_id=?, pinned=?, updated_at=CURRENT_TIMESTAMP WHERE id=?`
+
+	if _, err := tx.Exec(query, note.Title, note.Content, nullID(note.NotebookID), note.Pinned, note.ID); err != nil {
+		return fmt.Errorf("failed to update note in database: %w", err)
+	}
+	return setTags(tx, noteTags, note.ID, note.Tags)
+}
+
+// PutNote writes the note as given, recreating it under its own id if it
+// was deleted for good
+func (s *NoteStore) PutNote(note *models.Note) error {
+	query := `INSERT INTO notes (id, title, content, created_at, deleted_at, journal_date, notebook_id, pinned) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(id) DO UPDATE SET title=excluded.title, content=excluded.content,
+	          deleted_at=excluded.deleted_at, journal_date=excluded.journal_date,
+	          notebook_id=excluded.notebook_id, pinned=excluded.pinned, updated_at=CURRENT_TIMESTAMP`
+
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(query, note.ID, note.Title, note.Content, toDBTime(note.CreatedAt),
+			toDBTimeOrNull(note.DeletedAt), note.JournalDate, nullID(note.NotebookID), note.Pinned); err != nil {
+			return fmt.Errorf("failed to write note to database: %w", err)
+		}
+		return setTags(tx, noteTags, note.ID, note.Tags)
+	})
+}
+
+// DeleteNote moves the note to the trash
+func (s *NoteStore) DeleteNote(id int) error {
+	if _, err := s.db.Exec(`UPDATE notes SET deleted_at=? WHERE id=?`, toDBTime(time.Now()), id); err != nil {
+		return fmt.Errorf("failed to move note to trash: %w", err)
+	}
+	return nil
+}
+
+func (s *NoteStore) RestoreNote(id int) error {
+	if _, err := s.db.Exec(`UPDATE notes SET deleted_at=NULL WHERE id=?`, id); err != nil {
+		return fmt.Errorf("failed to restore note: %w", err)
+	}
+	return nil
+}
+
+// PurgeNote deletes the note for good
+func (s *NoteStore) PurgeNote(id int) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM notes WHERE id=?`, id); err != nil {
+			return fmt.Errorf("failed to delete note from database: %w", err)
+		}
+		if err := pruneLinks(tx); err != nil {
+			return err
+		}
+		return pruneTags(tx)
+	})
+}
+
+// LoadRevisions returns the saved versions of a note, newest first
+func (s *NoteStore) LoadRevisions(noteID int) ([]models.NoteRevision, error) {
+	query := `SELECT id, note_id, title, content, created_at FROM note_revisions
+	          WHERE note_id=? ORDER BY created_at DESC, id DESC`
+	rows, err := s.db.Query(query, noteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query note revisions: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.NoteRevision{}
+	for rows.Next() {
+		var revision models.NoteRevision
+		var createdAt time.Time
+
+		if err := rows.Scan(&revision.ID, &revision.NoteID, &revision.Title, &revision.Content, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan note revision: %w", err)
+		}
+		revision.CreatedAt = fromDBTime(createdAt)
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating note revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// ReviseNote updates the note and saves its revisions in one transaction,
+// so an edit never lands without its history
+func (s *NoteStore) ReviseNote(note *models.Note, revisions []models.NoteRevision) error {
+	query := `INSERT INTO note_revisions (note_id, title, content, created_at) VALUES (?, ?, ?, ?)`
+
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if err := updateNote(tx, note); err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			if _, err := tx.Exec(query, revision.NoteID, revision.Title, revision.Content, toDBTime(revision.CreatedAt)); err != nil {
+				return fmt.Errorf("failed to save note revision: %w", err)
+			}
+		}
+		return pruneRevisions(tx, note.ID)
+	})
+}
+
+// pruneRevisions drops all but the newest RevisionLimit versions of a note,
+// in the order LoadRevisions lists them
+func pruneRevisions(q querier, noteID int) error {
+	query := `DELETE FROM note_revisions WHERE note_id=? AND id NOT IN (
+	          SELECT id FROM note_revisions WHERE note_id=? ORDER BY created_at DESC, id DESC LIMIT ?)`
+	if _, err := q.Exec(query, noteID, noteID, models.RevisionLimit); err != nil {
+		return fmt.Errorf("failed to prune note revisions: %w", err)
+	}
+	return nil
+}
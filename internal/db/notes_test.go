@@ -0,0 +1,62 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"prodBooster/internal/models"
+)
+
+// openTestDB sets up a fresh database in a temp file, closed when the test
+// ends
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	if err := Init(filepath.Join(t.TempDir(), "data.db")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Close() })
+	return Get()
+}
+
+func TestRevisionLimit(t *testing.T) {
+	nl := models.NewNoteList(NewNoteStore(openTestDB(t)))
+	note, err := nl.Create("Draft", "edit 0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edits := models.RevisionLimit + 2
+	for i := 1; i <= edits; i++ {
+		if err := nl.Update(note.ID, "Draft", fmt.Sprintf("edit %d", i), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	revisions, err := nl.Revisions(note.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != models.RevisionLimit {
+		t.Fatalf("got %d revisions, want %d", len(revisions), models.RevisionLimit)
+	}
+	// The undated original goes first, then the oldest edits
+	if got, want := revisions[0].Content, fmt.Sprintf("edit %d", edits); got != want {
+		t.Errorf("newest revision = %q, want %q", got, want)
+	}
+	if got, want := revisions[len(revisions)-1].Content, fmt.Sprintf("edit %d", edits-models.RevisionLimit+1); got != want {
+		t.Errorf("oldest revision = %q, want %q", got, want)
+	}
+
+	// Deleting the note for good takes its revisions along
+	if err := nl.Remove(note.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := nl.Purge(note.ID); err != nil {
+		t.Fatal(err)
+	}
+	if revisions, err := nl.Revisions(note.ID); err != nil || len(revisions) != 0 {
+		t.Errorf("after purging got %d revisions (%v), want none", len(revisions), err)
+	}
+}
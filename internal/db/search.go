@@ -0,0 +1,242 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"prodBooster/internal/models"
+)
+
+// Full-text search uses FTS5, which go-sqlite3 only compiles in with the
+// sqlite_fts5 build tag:
+//
+//	go build -tags sqlite_fts5
+//
+// The Makefile passes it; FullTextSearch tells the UI when a build lacks it.
+//
+// The index is not a migration because the same database may be opened by
+// builds with and without FTS5. setupSearch runs on every start instead:
+// with FTS5 it creates the index tables and the triggers that keep them in
+// sync, rebuilding the index whenever the triggers had to be (re)created.
+// Without FTS5 it drops the triggers, which would otherwise fail every
+// write, and searches fall back to LIKE.
+
+// searchTable describes the FTS5 index over one table
+type searchTable struct {
+	table   string
+	columns []string // indexed columns, the title first
+}
+
+var (
+	todoSearch  = searchTable{"todos", []string{"title", "description"}}
+	noteSearch  = searchTable{"notes", []string{"title", "content"}}
+	eventSearch = searchTable{"events", []string{"title", "description", "location"}}
+)
+
+// fts5 reports whether this binary has FTS5, set by setupSearch
+var fts5 bool
+
+// FullTextSearch reports whether searches use the FTS5 index
+func FullTextSearch() bool {
+	return fts5
+}
+
+func (t searchTable) index() string {
+	return t.table + "_fts"
+}
+
+func (t searchTable) triggers() []string {
+	return []string{t.index() + "_insert", t.index() + "_delete", t.index() + "_update"}
+}
+
+func setupSearch(conn *sql.DB) error {
+	tables := []searchTable{todoSearch, noteSearch, eventSearch}
+
+	if err := conn.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&fts5); err != nil {
+		return fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	if !fts5 {
+		for _, t := range tables {
+			for _, trigger := range t.triggers() {
+				if _, err := conn.Exec("DROP TRIGGER IF EXISTS " + trigger); err != nil {
+					return fmt.Errorf("failed to drop search trigger: %w", err)
+				}
+			}
+		}
+		return nil
+	}
+
+	return inTx(conn, func(tx *sql.Tx) error {
+		for _, t := range tables {
+			if err := t.setup(tx); err != nil {
+				return fmt.Errorf("failed to set up search for %s: %w", t.table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// setup creates the index and its triggers, rebuilding the index from the
+// table unless all triggers were already in place
+func (t searchTable) setup(tx *sql.Tx) error {
+	var existing int
+	query := `SELECT COUNT(*) FROM sqlite_master WHERE type='trigger' AND name IN (?, ?, ?)`
+	triggers := t.triggers()
+	if err := tx.QueryRow(query, triggers[0], triggers[1], triggers[2]).Scan(&existing); err != nil {
+		return err
+	}
+
+	cols := strings.Join(t.columns, ", ")
+	newCols := "new." + strings.Join(t.columns, ", new.")
+	oldCols := "old." + strings.Join(t.columns, ", old.")
+	stmts := []string{
+		fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content='%s', content_rowid='id', tokenize='unicode61 remove_diacritics 2')`,
+			t.index(), cols, t.table),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s BEGIN
+			INSERT INTO %s(rowid, %s) VALUES (new.id, %s);
+		END`, triggers[0], t.table, t.index(), cols, newCols),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s BEGIN
+			INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.id, %s);
+		END`, triggers[1], t.table, t.index(), t.index(), cols, oldCols),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE OF %s ON %s BEGIN
+			INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.id, %s);
+			INSERT INTO %s(rowid, %s) VALUES (new.id, %s);
+		END`, triggers[2], cols, t.table, t.index(), t.index(), cols, oldCols, t.index(), cols, newCols),
+	}
+	if existing < len(triggers) {
+		stmts = append(stmts, fmt.Sprintf(`INSERT INTO %s(%s) VALUES ('rebuild')`, t.index(), t.index()))
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// search returns the live rows of t matching query, best match first
+func (t searchTable) search(conn *sql.DB, query string) ([]models.SearchHit, error) {
+	terms := models.ParseSearch(query)
+	if len(terms) == 0 {
+		return []models.SearchHit{}, nil
+	}
+	if fts5 {
+		return t.searchIndex(conn, terms)
+	}
+	return t.searchLike(conn, terms)
+}
+
+// searchIndex ranks with bm25, weighting title hits ten times higher
+func (t searchTable) searchIndex(conn *sql.DB, terms []models.SearchTerm) ([]models.SearchHit, error) {
+	weights := strings.Repeat(", 1.0", len(t.columns)-1)
+	query := fmt.Sprintf(`SELECT t.id, bm25(%[1]s, 10.0%[2]s), snippet(%[1]s, -1, ?, ?, '…', 12)
+		FROM %[1]s JOIN %[3]s t ON t.id = %[1]s.rowid
+		WHERE %[1]s MATCH ? AND t.deleted_at IS NULL
+		ORDER BY 2, t.id`, t.index(), weights, t.table)
+
+	rows, err := conn.Query(query, models.SnippetStart, models.SnippetEnd, ftsQuery(terms))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", t.table, err)
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	for rows.Next() {
+		var hit models.SearchHit
+		if err := rows.Scan(&hit.ID, &hit.Rank, &hit.Snippet); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		hit.Snippet = strings.Join(strings.Fields(hit.Snippet), " ")
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	return hits, nil
+}
+
+// ftsQuery turns terms into an FTS5 query: every word as a quoted prefix,
+// every phrase quoted as is. Quoting keeps FTS5 operators typed by the user
+// (AND, NEAR, column:...) from being interpreted.
+func ftsQuery(terms []models.SearchTerm) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		quoted := `"` + strings.ReplaceAll(term.Text, `"`, `""`) + `"`
+		if !term.Phrase {
+			quoted += "*"
+		}
+		parts[i] = quoted
+	}
+	return strings.Join(parts, " ")
+}
+
+// searchLike narrows down with LIKE, then ranks and cuts snippets with
+// models.MatchSearch
+func (t searchTable) searchLike(conn *sql.DB, terms []models.SearchTerm) ([]models.SearchHit, error) {
+	var where []string
+	var args []any
+	for _, term := range terms {
+		pattern := "%" + escapeLike(term.Text) + "%"
+		var either []string
+		for _, col := range t.columns {
+			either = append(either, col+` LIKE ? ESCAPE '\'`)
+			args = append(args, pattern)
+		}
+		where = append(where, "("+strings.Join(either, " OR ")+")")
+	}
+
+	cols := "COALESCE(" + strings.Join(t.columns, ", ''), COALESCE(") + ", '')"
+	query := fmt.Sprintf(`SELECT id, %s FROM %s WHERE deleted_at IS NULL AND %s`,
+		cols, t.table, strings.Join(where, " AND "))
+
+	rows, err := conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", t.table, err)
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	for rows.Next() {
+		var id int
+		fields := make([]string, len(t.columns))
+		dest := []any{&id}
+		for i := range fields {
+			dest = append(dest, &fields[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+
+		if hit, ok := models.MatchSearch(terms, fields...); ok {
+			hit.ID = id
+			hits = append(hits, hit)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search results: %w", err)
+	}
+
+	models.SortHits(hits)
+	return hits, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (s *TodoStore) SearchTodos(query string) ([]models.SearchHit, error) {
+	return todoSearch.search(s.db, query)
+}
+
+func (s *NoteStore) SearchNotes(query string) ([]models.SearchHit, error) {
+	return noteSearch.search(s.db, query)
+}
+
+func (s *EventStore) SearchEvents(query string) ([]models.SearchHit, error) {
+	return eventSearch.search(s.db, query)
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// querier is the part of *sql.DB and *sql.Tx the tag helpers need
+type querier interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// tagJoin describes one of the item/tag join tables
+type tagJoin struct {
+	table  string // e.g. todo_tags
+	column string // e.g. todo_id
+}
+
+var (
+	todoTags  = tagJoin{"todo_tags", "todo_id"}
+	noteTags  = tagJoin{"note_tags", "note_id"}
+	eventTags = tagJoin{"event_tags", "event_id"}
+)
+
+// loadTags returns the tag names of every item in the join table, keyed by
+// item id and sorted by name
+func loadTags(q querier, join tagJoin) (map[int][]string, error) {
+	query := fmt.Sprintf(`SELECT j.%s, t.name FROM %s j JOIN tags t ON t.id = j.tag_id ORDER BY t.name`,
+		join.column, join.table)
+	rows, err := q.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := map[int][]string{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags[id] = append(tags[id], name)
+	}
+	return tags, rows.Err()
+}
+
+// setTags replaces the tags of one item and drops tags nothing uses anymore
+func setTags(q querier, join tagJoin, id int, tags []string) error {
+	if _, err := q.Exec(fmt.Sprintf(`DELETE FROM %s WHERE %s=?`, join.table, join.column), id); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+
+	for _, name := range tags {
+		if _, err := q.Exec(`INSERT OR IGNORE INTO tags (name) VALUES (?)`, name); err != nil {
+			return fmt.Errorf("failed to save tag %q: %w", name, err)
+		}
+		query := fmt.Sprintf(`INSERT OR IGNORE INTO %s (%s, tag_id) SELECT ?, id FROM tags WHERE name=?`,
+			join.table, join.column)
+		if _, err := q.Exec(query, id, name); err != nil {
+			return fmt.Errorf("failed to tag item with %q: %w", name, err)
+		}
+	}
+
+	return pruneTags(q)
+}
+
+func pruneTags(q querier) error {
+	query := `DELETE FROM tags WHERE
+		id NOT IN (SELECT tag_id FROM todo_tags) AND
+		id NOT IN (SELECT tag_id FROM note_tags) AND
+		id NOT IN (SELECT tag_id FROM event_tags)`
+	if _, err := q.Exec(query); err != nil {
+		return fmt.Errorf("failed to prune tags: %w", err)
+	}
+	return nil
+}
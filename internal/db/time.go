@@ -0,0 +1,36 @@
+package db
+
+import "time"
+
+// Timestamps are stored as UTC text in SQLite's own "YYYY-MM-DD HH:MM:SS"
+// form (what CURRENT_TIMESTAMP produces), so they sort correctly and work
+// with SQLite's date functions. They are converted back to local time on
+// load; nothing outside this package should see a UTC time.Time.
+const timestampLayout = "2006-01-02 15:04:05"
+
+func toDBTime(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+func toDBNullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return toDBTime(*t)
+}
+
+// toDBTimeOrNull stores the zero time as NULL, e.g. deleted_at of an item
+// that isn't in the trash
+func toDBTimeOrNull(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return toDBTime(t)
+}
+
+func fromDBTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return time.Time{}
+	}
+	return t.Local()
+}
@@ -0,0 +1,334 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// TodoStore is the SQLite implementation of models.TodoStore
+type TodoStore struct {
+	db *sql.DB
+}
+
+func NewTodoStore(conn *sql.DB) *TodoStore {
+	return &TodoStore{db: conn}
+}
+
+func (s *TodoStore) LoadTodos() ([]*models.Todo, error) {
+	return s.queryTodos("deleted_at IS NULL ORDER BY id")
+}
+
+func (s *TodoStore) LoadDeletedTodos() ([]*models.Todo, error) {
+	return s.queryTodos("deleted_at IS NOT NULL ORDER BY deleted_at DESC, id")
+}
+
+// queryTodos loads the todos matching where, which also orders them
+func (s *TodoStore) queryTodos(where string) ([]*models.Todo, error) {
+	query := "SELECT id, title, description, completed, priority, created_at, due_date, recurrence, series_id, parent_id, deleted_at, pinned FROM todos WHERE " + where
+
+	tags, err := loadTags(s.db, todoTags)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query todos: %w", err)
+	}
+	defer rows.Close()
+
+	todos := []*models.Todo{}
+	for rows.Next() {
+		var id int
+		var title, description, recurrence string
+		var completed, pinned bool
+		var priority int
+		var createdAt time.Time
+		var dueDate, deletedAt sql.NullTime
+		var seriesID, parentID sql.NullInt64
+
+		if err := rows.Scan(&id, &title, &description, &completed, &priority, &createdAt, &dueDate, &recurrence, &seriesID, &parentID, &deletedAt, &pinned); err != nil {
+			return nil, fmt.Errorf("failed to scan todo: %w", err)
+		}
+
+		todo := &models.Todo{
+			ID:          id,
+			Title:       title,
+			Description: description,
+			Completed:   completed,
+			Priority:    models.Priority(priority),
+			CreatedAt:   fromDBTime(createdAt),
+			Tags:        tags[id],
+			Recurrence:  recurrence,
+			SeriesID:    int(seriesID.Int64),
+			ParentID:    int(parentID.Int64),
+			Pinned:      pinned,
+		}
+
+		if dueDate.Valid {
+			due := fromDBTime(dueDate.Time)
+			todo.DueTime = &due
+		}
+		if deletedAt.Valid {
+			todo.DeletedAt = fromDBTime(deletedAt.Time)
+		}
+
+		todos = append(todos, todo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating todos: %w", err)
+	}
+
+	return todos, nil
+}
+
+func (s *TodoStore) InsertTodo(todo *models.Todo) (int, error) {
+	var id int
+	err := inTx(s.db, func(tx *sql.Tx) error {
+		var err error
+		id, err = insertTodo(tx, todo)
+		return err
+	})
+	return id, err
+}
+
+func insertTodo(q querier, todo *models.Todo) (int, error) {
+	query := `INSERT INTO todos (title, description, completed, priority, due_date, created_at, recurrence, series_id, parent_id, pinned)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := q.Exec(query, todo.Title, todo.Description, todo.Completed, int(todo.Priority),
+		toDBNullTime(todo.DueTime), toDBTime(todo.CreatedAt), todo.Recurrence, nullID(todo.SeriesID), nullID(todo.ParentID), todo.Pinned)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add todo to database: %w", err)
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	id := int(lastID)
+
+	return id, setTags(q, todoTags, id, todo.Tags)
+}
+
+func (s *TodoStore) UpdateTodo(todo *models.Todo) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		return updateTodo(tx, todo)
+	})
+}
+
+func updateTodo(q querier, todo *models.Todo) error {
+	query := `UPDATE todos SET title=?, description=?, priority=?, due_date=?, recurrence=?, series_id=?, parent_id=?, pinned=?, updated_at=CURRENT_TIMESTAMP
+	          WHERE id=?`
+
+	if _, err := q.Exec(query, todo.Title, todo.Description, int(todo.Priority), toDBNullTime(todo.DueTime),
+		todo.Recurrence, nullID(todo.SeriesID), nullID(todo.ParentID), todo.Pinned, todo.ID); err != nil {
+		return fmt.Errorf("failed to update todo in database: %w", err)
+	}
+	return setTags(q, todoTags, todo.ID, todo.Tags)
+}
+
+// DeleteTodo moves the todo and its subtasks to the trash
+func (s *TodoStore) DeleteTodo(id int) error {
+	query := `UPDATE todos SET deleted_at=? WHERE (id=? OR parent_id=?) AND deleted_at IS NULL`
+	if _, err := s.db.Exec(query, toDBTime(time.Now()), id, id); err != nil {
+		return fmt.Errorf("failed to move todo to trash: %w", err)
+	}
+	return nil
+}
+
+// RestoreTodo takes the todo out of the trash together with the subtasks
+// that were trashed along with it. A subtask brings its trashed parent back
+// the same way, since it can't be live under a trashed parent.
+func (s *TodoStore) RestoreTodo(id int) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		var parentID sql.NullInt64
+		if err := tx.QueryRow(`SELECT parent_id FROM todos WHERE id=?`, id).Scan(&parentID); err != nil {
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+		if parentID.Valid {
+			if err := restoreTodo(tx, int(parentID.Int64)); err != nil {
+				return err
+			}
+		}
+		return restoreTodo(tx, id)
+	})
+}
+
+// restoreTodo takes the todo and the subtasks trashed along with it out of
+// the trash. A todo that isn't trashed is left as is.
+func restoreTodo(tx *sql.Tx, id int) error {
+	stmts := []string{
+		`UPDATE todos SET deleted_at=NULL
+		 WHERE parent_id=?1 AND deleted_at=(SELECT deleted_at FROM todos WHERE id=?1)`,
+		`UPDATE todos SET deleted_at=NULL WHERE id=?1`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt, id); err != nil {
+			return fmt.Errorf("failed to restore todo: %w", err)
+		}
+	}
+	return nil
+}
+
+// PurgeTodo deletes the todo and its subtasks for good
+func (s *TodoStore) PurgeTodo(id int) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM todos WHERE id=?`, id); err != nil {
+			return fmt.Errorf("failed to delete todo from database: %w", err)
+		}
+		if err := pruneLinks(tx); err != nil {
+			return err
+		}
+		return pruneTags(tx)
+	})
+}
+
+// PutTodo writes the todo as given, recreating it under its own id if it
+// was deleted for good
+func (s *TodoStore) PutTodo(todo *models.Todo) error {
+	query := `INSERT INTO todos (id, title, description, completed, priority, due_date, created_at, recurrence, series_id, parent_id, deleted_at, pinned)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(id) DO UPDATE SET title=excluded.title, description=excluded.description, completed=excluded.completed,
+	          priority=excluded.priority, due_date=excluded.due_date, recurrence=excluded.recurrence, series_id=excluded.series_id,
+	          parent_id=excluded.parent_id, deleted_at=excluded.deleted_at, pinned=excluded.pinned, updated_at=CURRENT_TIMESTAMP`
+
+	return inTx(s.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(query, todo.ID, todo.Title, todo.Description, todo.Completed, int(todo.Priority),
+			toDBNullTime(todo.DueTime), toDBTime(todo.CreatedAt), todo.Recurrence, nullID(todo.SeriesID),
+			nullID(todo.ParentID), toDBTimeOrNull(todo.DeletedAt), todo.Pinned); err != nil {
+			return fmt.Errorf("failed to write todo to database: %w", err)
+		}
+		return setTags(tx, todoTags, todo.ID, todo.Tags)
+	})
+}
+
+// ToggleTodo writes a completed or reopened todo and everything that came
+// with it in one transaction
+func (s *TodoStore) ToggleTodo(toggle *models.TodoToggle) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		for _, todo := range toggle.Todos {
+			if err := updateTodo(tx, todo); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`UPDATE todos SET completed=? WHERE id=?`, todo.Completed, todo.ID); err != nil {
+				return fmt.Errorf("failed to toggle todo in database: %w", err)
+			}
+		}
+
+		for _, completion := range toggle.Completions {
+			if err := recordCompletion(tx, completion); err != nil {
+				return err
+			}
+		}
+		for _, id := range toggle.Reopened {
+			if err := removeCompletion(tx, id); err != nil {
+				return err
+			}
+		}
+
+		if len(toggle.Purged) > 0 {
+			for _, id := range toggle.Purged {
+				if _, err := tx.Exec(`DELETE FROM todos WHERE id=?`, id); err != nil {
+					return fmt.Errorf("failed to delete todo from database: %w", err)
+				}
+			}
+			if err := pruneLinks(tx); err != nil {
+				return err
+			}
+			if err := pruneTags(tx); err != nil {
+				return err
+			}
+		}
+
+		for _, spawn := range toggle.Spawned {
+			id, err := insertTodo(tx, spawn.Todo)
+			if err != nil {
+				return err
+			}
+			spawn.Todo.ID = id
+			for _, subtask := range spawn.Subtasks {
+				subtask.ParentID = id
+				if subtask.ID, err = insertTodo(tx, subtask); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *TodoStore) LoadCompletions() ([]models.TodoCompletion, error) {
+	query := "SELECT series_id, todo_id, due_date, completed_at FROM todo_completions ORDER BY completed_at DESC, id DESC"
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query todo completions: %w", err)
+	}
+	defer rows.Close()
+
+	completions := []models.TodoCompletion{}
+	for rows.Next() {
+		var seriesID int
+		var todoID sql.NullInt64
+		var dueDate sql.NullTime
+		var completedAt time.Time
+
+		if err := rows.Scan(&seriesID, &todoID, &dueDate, &completedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan todo completion: %w", err)
+		}
+
+		completion := models.TodoCompletion{
+			SeriesID:    seriesID,
+			TodoID:      int(todoID.Int64),
+			CompletedAt: fromDBTime(completedAt),
+		}
+		if dueDate.Valid {
+			due := fromDBTime(dueDate.Time)
+			completion.DueTime = &due
+		}
+		completions = append(completions, completion)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating todo completions: %w", err)
+	}
+
+	return completions, nil
+}
+
+// RecordCompletion adds a completion. Recording one again after its todo
+// was purged, as undo does, replaces the copy the purge left behind.
+func (s *TodoStore) RecordCompletion(completion models.TodoCompletion) error {
+	return inTx(s.db, func(tx *sql.Tx) error {
+		return recordCompletion(tx, completion)
+	})
+}
+
+func recordCompletion(q querier, completion models.TodoCompletion) error {
+	query := `INSERT INTO todo_completions (series_id, todo_id, due_date, completed_at) VALUES (?, ?, ?, ?)`
+
+	if _, err := q.Exec(`DELETE FROM todo_completions WHERE todo_id IS NULL AND series_id=? AND completed_at=?`,
+		completion.SeriesID, toDBTime(completion.CompletedAt)); err != nil {
+		return fmt.Errorf("failed to record todo completion: %w", err)
+	}
+	if _, err := q.Exec(query, completion.SeriesID, completion.TodoID,
+		toDBNullTime(completion.DueTime), toDBTime(completion.CompletedAt)); err != nil {
+		return fmt.Errorf("failed to record todo completion: %w", err)
+	}
+	return nil
+}
+
+func (s *TodoStore) RemoveCompletion(todoID int) error {
+	return removeCompletion(s.db, todoID)
+}
+
+func removeCompletion(q querier, todoID int) error {
+	if _, err := q.Exec(`DELETE FROM todo_completions WHERE todo_id=?`, todoID); err != nil {
+		return fmt.Errorf("failed to remove todo completion: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// ViewStore is the SQLite implementation of models.ViewStore
+type ViewStore struct {
+	db *sql.DB
+}
+
+func NewViewStore(conn *sql.DB) *ViewStore {
+	return &ViewStore{db: conn}
+}
+
+func (s *ViewStore) LoadViews() ([]*models.SavedView, error) {
+	rows, err := s.db.Query(`SELECT id, name, page, query, filter, sort, on_dashboard, created_at FROM saved_views ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved views: %w", err)
+	}
+	defer rows.Close()
+
+	views := []*models.SavedView{}
+	for rows.Next() {
+		var view models.SavedView
+		var createdAt time.Time
+
+		if err := rows.Scan(&view.ID, &view.Name, &view.Page, &view.Query, &view.Filter, &view.Sort, &view.OnDashboard, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan saved view: %w", err)
+		}
+		view.CreatedAt = fromDBTime(createdAt)
+
+		views = append(views, &view)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating saved views: %w", err)
+	}
+
+	return views, nil
+}
+
+func (s *ViewStore) InsertView(view *models.SavedView) (int, error) {
+	result, err := s.db.Exec(`INSERT INTO saved_views (name, page, query, filter, sort, on_dashboard, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		view.Name, view.Page, view.Query, view.Filter, view.Sort, view.OnDashboard, toDBTime(view.CreatedAt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to save view: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	return int(id), nil
+}
+
+func (s *ViewStore) UpdateView(view *models.SavedView) error {
+	_, err := s.db.Exec(`UPDATE saved_views SET name=?, query=?, filter=?, sort=?, on_dashboard=? WHERE id=?`,
+		view.Name, view.Query, view.Filter, view.Sort, view.OnDashboard, view.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update saved view: %w", err)
+	}
+	return nil
+}
+
+// PutView writes the view as given, recreating it under its own id if it
+// was deleted
+func (s *ViewStore) PutView(view *models.SavedView) error {
+	query := `INSERT INTO saved_views (id, name, page, query, filter, sort, on_dashboard, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	          ON CONFLICT(id) DO UPDATE SET name=excluded.name, page=excluded.page, query=excluded.query,
+	          filter=excluded.filter, sort=excluded.sort, on_dashboard=excluded.on_dashboard`
+
+	if _, err := s.db.Exec(query, view.ID, view.Name, view.Page, view.Query, view.Filter, view.Sort, view.OnDashboard, toDBTime(view.CreatedAt)); err != nil {
+		return fmt.Errorf("failed to write saved view: %w", err)
+	}
+	return nil
+}
+
+func (s *ViewStore) DeleteView(id int) error {
+	if _, err := s.db.Exec(`DELETE FROM saved_views WHERE id=?`, id); err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+	return nil
+}
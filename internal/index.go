@@ -2,13 +2,17 @@
 package index
 
 import (
-	// "fmt"
+	"fmt"
+	"time"
 
+	"prodBooster/internal/config"
 	"prodBooster/internal/db"
 	"prodBooster/internal/models"
+	"prodBooster/internal/ui/components"
 	"prodBooster/internal/ui/pages"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type Instance struct {
@@ -16,6 +20,12 @@ type Instance struct {
 	todoList  *models.TodoList
 	noteList  *models.NoteList
 	eventList *models.EventList
+	history   *models.History  // undo/redo shared by every page
+	links     *models.LinkList // links between todos, notes and events
+
+	globalSearch *components.GlobalSearch // ctrl+f overlay searching every page
+	linkFrom     *models.ItemRef          // item being linked while the search picks the target
+	linkMenu     *components.LinkMenu     // o overlay listing an item's links
 
 	currentPage models.PageType
 	pages       map[models.PageType]pages.Page // Map of page type to page instance
@@ -23,30 +33,61 @@ type Instance struct {
 	// Dimensions of the terminal window
 	width  int
 	height int
+
+	status string // result of the last undo/redo, cleared on the next key
 }
 
 func NewInstance() *Instance {
 	database := db.Get()
 
 	// Use database-backed models
-	todoList_ := models.NewTodoList(database)
-	noteList_ := models.NewNoteList(database)
-	eventList_ := models.NewEventList(database)
+	todoList_ := models.NewTodoList(db.NewTodoStore(database))
+	todoList_.AutoCompleteParents = config.Get().AutoCompleteParents
+	noteList_ := models.NewNoteList(db.NewNoteStore(database))
+	eventList_ := models.NewEventList(db.NewEventStore(database))
+
+	// Empty out trash older than the retention period (0 keeps it forever)
+	retentionDays := config.Get().TrashRetentionDays
+	if retentionDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -retentionDays)
+		for _, purge := range []func(time.Time) error{todoList_.PurgeTrash, noteList_.PurgeTrash, eventList_.PurgeTrash} {
+			if err := purge(cutoff); err != nil {
+				fmt.Printf("Warning: failed to purge trash: %v\n", err)
+			}
+		}
+	}
+
+	viewList_ := models.NewViewList(db.NewViewStore(database))
+	linkList_ := models.NewLinkList(db.NewLinkStore(database), todoList_, noteList_, eventList_)
+	notebookList_ := models.NewNotebookList(db.NewNotebookStore(database), noteList_)
+
+	// Record every change from here on, startup purges can't be undone
+	history := models.NewHistory(db.NewHistoryStore(database), todoList_, noteList_, eventList_, linkList_, notebookList_, viewList_)
 
 	pageMap := make(map[models.PageType]pages.Page)
-	pageMap[models.PageDashboard] = pages.NewDashboardPage(todoList_, noteList_, eventList_)
-	pageMap[models.PageTodos] = pages.NewTodosPage(todoList_)
-	pageMap[models.PageNotes] = pages.NewNotesPage(noteList_)
-	pageMap[models.PageCalendar] = pages.NewCalendarPage(eventList_)
+	pageMap[models.PageDashboard] = pages.NewDashboardPage(todoList_, noteList_, eventList_, viewList_)
+	pageMap[models.PageTodos] = pages.NewTodosPage(todoList_, viewList_, linkList_)
+	notesPage := pages.NewNotesPage(noteList_, viewList_, linkList_, notebookList_)
+	notesPage.SetMarkdownStyle(config.Get().MarkdownStyle)
+	pageMap[models.PageNotes] = notesPage
+	pageMap[models.PageCalendar] = pages.NewCalendarPage(eventList_, viewList_, linkList_)
+	pageMap[models.PageTrash] = pages.NewTrashPage(todoList_, noteList_, eventList_, history, retentionDays)
+
+	globalSearch := components.NewGlobalSearch(todoList_, noteList_, eventList_)
+	globalSearch.SetFullText(db.FullTextSearch())
 
 	return &Instance{
-		todoList:    todoList_,
-		noteList:    noteList_,
-		eventList:   eventList_,
-		currentPage: models.PageDashboard,
-		pages:       pageMap,
-		width:       80,
-		height:      24,
+		todoList:     todoList_,
+		noteList:     noteList_,
+		eventList:    eventList_,
+		history:      history,
+		links:        linkList_,
+		globalSearch: globalSearch,
+		linkMenu:     components.NewLinkMenu(linkList_),
+		currentPage:  models.PageDashboard,
+		pages:        pageMap,
+		width:        80,
+		height:       24,
 	}
 }
 
@@ -55,6 +96,15 @@ func (i *Instance) Init() tea.Cmd {
 }
 
 func (i *Instance) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := i.update(msg)
+	// A change the history couldn't save is made but can't be undone
+	if err := i.history.Err(); err != nil {
+		i.status = "⚠️  " + err.Error()
+	}
+	return model, cmd
+}
+
+func (i *Instance) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		i.width = msg.Width
@@ -63,9 +113,42 @@ func (i *Instance) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for _, page := range i.pages {
 			page.SetSize(msg.Width, msg.Height)
 		}
+		i.globalSearch.SetWidth(min(msg.Width, 100))
+		i.linkMenu.SetWidth(min(msg.Width, 80))
 		return i, nil
 
 	case tea.KeyMsg:
+		// Global search takes every key while open
+		if i.globalSearch.IsActive() {
+			updatedSearch, cmd := i.globalSearch.Update(msg)
+			i.globalSearch = updatedSearch
+			result, ok := i.globalSearch.Chosen()
+			switch {
+			case i.linkFrom != nil && ok:
+				i.link(*i.linkFrom, models.ItemRef{Page: result.Page, ID: result.ID})
+				i.linkFrom = nil
+			case i.linkFrom != nil && !i.globalSearch.IsActive():
+				// Closed without picking anything
+				i.linkFrom = nil
+			case ok:
+				i.focus(models.ItemRef{Page: result.Page, ID: result.ID})
+			}
+			return i, cmd
+		}
+
+		// So does the link menu
+		if i.linkMenu.IsActive() {
+			updatedMenu, cmd := i.linkMenu.Update(msg)
+			i.linkMenu = updatedMenu
+			if ref, ok := i.linkMenu.Chosen(); ok {
+				i.focus(ref)
+			} else if !i.linkMenu.IsActive() {
+				// Links may have been removed
+				i.switchPage(i.currentPage)
+			}
+			return i, cmd
+		}
+
 		// Check if current page has an active form
 		currentPage := i.pages[i.currentPage]
 		if currentPage.IsFormActive() {
@@ -79,35 +162,148 @@ func (i *Instance) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		// Normal navigation when no form is active
+		i.status = ""
 		switch msg.String() {
 		case "q":
 			return i, tea.Quit
+		case "u":
+			i.undo()
+			return i, nil
+		case "ctrl+r":
+			i.redo()
+			return i, nil
+		case "ctrl+f":
+			i.globalSearch.Activate()
+			return i, nil
+		case "T":
+			// Today's journal, started on the first open of the day
+			return i.update(pages.JournalMsg{})
+		case "l", "o":
+			// Link the selected item to another one, or list its links
+			if linker, ok := currentPage.(pages.Linker); ok {
+				if ref, ok := linker.Selected(); ok {
+					if msg.String() == "l" {
+						i.linkFrom = &ref
+						i.globalSearch.Pick("🔗 Link to...")
+					} else {
+						i.linkMenu.Open(ref)
+					}
+					return i, nil
+				}
+			}
+			updatedPage, cmd := currentPage.Update(msg)
+			i.pages[i.currentPage] = updatedPage
+			return i, cmd
 		case "UP":
 			// Handle up key
 		case "DOWN":
 			// Handle down key
 		case "1":
-			i.currentPage = models.PageDashboard
+			i.switchPage(models.PageDashboard)
 			return i, nil
 		case "2":
-			i.currentPage = models.PageTodos
+			i.switchPage(models.PageTodos)
 			return i, nil
 		case "3":
-			i.currentPage = models.PageNotes
+			i.switchPage(models.PageNotes)
 			return i, nil
 		case "4":
-			i.currentPage = models.PageCalendar
+			i.switchPage(models.PageCalendar)
+			return i, nil
+		case "5":
+			i.switchPage(models.PageTrash)
 			return i, nil
 		default:
 			updatedPage, cmd := currentPage.Update(msg)
 			i.pages[i.currentPage] = updatedPage
 			return i, cmd
 		}
+
+	case pages.NewNoteMsg, pages.JournalMsg:
+		// A page wants a new note written, e.g. for an event, or the journal
+		i.switchPage(models.PageNotes)
+		updatedPage, cmd := i.pages[models.PageNotes].Update(msg)
+		i.pages[models.PageNotes] = updatedPage
+		return i, cmd
+
+	default:
+		// Anything else, e.g. an external editor exiting, is for the page
+		updatedPage, cmd := i.pages[i.currentPage].Update(msg)
+		i.pages[i.currentPage] = updatedPage
+		return i, cmd
 	}
 	return i, nil
 }
 
+// focus jumps to an item on its page
+func (i *Instance) focus(ref models.ItemRef) {
+	i.switchPage(ref.Page)
+	if focuser, ok := i.pages[ref.Page].(pages.Focuser); ok {
+		focuser.Focus(ref.ID)
+	}
+}
+
+// link links from to the item picked in the search
+func (i *Instance) link(from, to models.ItemRef) {
+	if err := i.links.Add(from, to); err != nil {
+		i.status = "⚠️  " + err.Error()
+	} else {
+		title, _ := i.links.Title(to)
+		i.status = "🔗 Linked to " + title + " • o to see all links"
+	}
+	i.switchPage(i.currentPage)
+}
+
+// undo reverts the last change, whichever page it was made on
+func (i *Instance) undo() {
+	cmd, err := i.history.Undo()
+	switch {
+	case err != nil:
+		i.status = "⚠️  Undo failed: " + err.Error()
+	case cmd == nil:
+		i.status = "Nothing to undo"
+	default:
+		i.status = "↩️  Undid: " + cmd.Label + " • ctrl+r to redo"
+	}
+	i.switchPage(i.currentPage)
+}
+
+// redo replays the last undone change
+func (i *Instance) redo() {
+	cmd, err := i.history.Redo()
+	switch {
+	case err != nil:
+		i.status = "⚠️  Redo failed: " + err.Error()
+	case cmd == nil:
+		i.status = "Nothing to redo"
+	default:
+		i.status = "↪️  Redid: " + cmd.Label
+	}
+	i.switchPage(i.currentPage)
+}
+
+// switchPage shows another page, refreshing it first since its data may
+// have changed while it was hidden
+func (i *Instance) switchPage(page models.PageType) {
+	i.currentPage = page
+	if refresher, ok := i.pages[page].(pages.Refresher); ok {
+		refresher.Refresh()
+	}
+}
+
 func (i *Instance) View() string {
+	if i.globalSearch.IsActive() {
+		return lipgloss.Place(i.width, i.height, lipgloss.Center, lipgloss.Top, i.globalSearch.View())
+	}
+	if i.linkMenu.IsActive() {
+		return lipgloss.Place(i.width, i.height, lipgloss.Center, lipgloss.Top, i.linkMenu.View())
+	}
+
 	currentPage := i.pages[i.currentPage]
+	if i.status != "" {
+		return currentPage.View() + "\n" + statusStyle.Render(i.status)
+	}
 	return currentPage.View()
 }
+
+var statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("213"))
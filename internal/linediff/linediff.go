@@ -0,0 +1,125 @@
+// Package linediff compares two texts line by line, the way the note history
+// shows what an edit changed: unchanged lines, lines removed and lines added.
+package linediff
+
+import "strings"
+
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+// Line is one line of a diff
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// maxCells caps the table Diff fills in to find the longest common run of
+// lines. Past it the changed middle of the texts comes back as removed then
+// added, which is still right, just not minimal.
+const maxCells = 4_000_000
+
+// Diff returns the lines of a and b in order, marking the ones only in a as
+// Delete and the ones only in b as Insert. Where lines change, the removed
+// ones come before the added ones.
+func Diff(a, b string) []Line {
+	as, bs := split(a), split(b)
+
+	// Lines the texts start and end with alike need no table
+	prefix := 0
+	for prefix < len(as) && prefix < len(bs) && as[prefix] == bs[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(as)-prefix && suffix < len(bs)-prefix && as[len(as)-1-suffix] == bs[len(bs)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]Line, 0, len(as)+len(bs))
+	for _, text := range as[:prefix] {
+		lines = append(lines, Line{Equal, text})
+	}
+	lines = append(lines, middle(as[prefix:len(as)-suffix], bs[prefix:len(bs)-suffix])...)
+	for _, text := range as[len(as)-suffix:] {
+		lines = append(lines, Line{Equal, text})
+	}
+	return lines
+}
+
+// middle diffs the part of the texts that differs, by the longest common
+// subsequence of their lines
+func middle(as, bs []string) []Line {
+	n, m := len(as), len(bs)
+	var lines []Line
+	if n*m > maxCells {
+		for _, text := range as {
+			lines = append(lines, Line{Delete, text})
+		}
+		for _, text := range bs {
+			lines = append(lines, Line{Insert, text})
+		}
+		return lines
+	}
+
+	// lcs[i*(m+1)+j] is the longest common subsequence of as[i:] and bs[j:]
+	lcs := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if as[i] == bs[j] {
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+			} else {
+				lcs[i*(m+1)+j] = max(lcs[(i+1)*(m+1)+j], lcs[i*(m+1)+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case as[i] == bs[j]:
+			lines = append(lines, Line{Equal, as[i]})
+			i++
+			j++
+		case lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]:
+			lines = append(lines, Line{Delete, as[i]})
+			i++
+		default:
+			lines = append(lines, Line{Insert, bs[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, Line{Delete, as[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, Line{Insert, bs[j]})
+	}
+	return lines
+}
+
+// Count returns how many lines a diff adds and removes
+func Count(lines []Line) (added, removed int) {
+	for _, line := range lines {
+		switch line.Op {
+		case Insert:
+			added++
+		case Delete:
+			removed++
+		}
+	}
+	return added, removed
+}
+
+// split cuts text into lines. An empty text has none, and a trailing
+// newline doesn't make an empty last line.
+func split(text string) []string {
+	text = strings.TrimSuffix(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
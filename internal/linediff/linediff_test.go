@@ -0,0 +1,109 @@
+package linediff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// render writes a diff the way a patch does: " " for kept lines, "-" for
+// removed and "+" for added ones
+func render(lines []Line) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString([]string{" ", "-", "+"}[line.Op] + line.Text + "\n")
+	}
+	return b.String()
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"both empty", "", "", ""},
+		{"same", "a\nb", "a\nb", " a\n b\n"},
+		{"from empty", "", "a\nb", "+a\n+b\n"},
+		{"to empty", "a\nb", "", "-a\n-b\n"},
+		{"trailing newline ignored", "a\nb\n", "a\nb", " a\n b\n"},
+		{"crlf", "a\r\nb", "a\nb", " a\n b\n"},
+		{"line added", "a\nc", "a\nb\nc", " a\n+b\n c\n"},
+		{"line removed", "a\nb\nc", "a\nc", " a\n-b\n c\n"},
+		{"line changed", "a\nb\nc", "a\nB\nc", " a\n-b\n+B\n c\n"},
+		{"prefix and suffix kept", "x\na\nb\ny", "x\nb\nc\ny", " x\n-a\n b\n+c\n y\n"},
+		{"moved line", "a\nb\nc", "b\nc\na", "-a\n b\n c\n+a\n"},
+		{"repeated lines", "a\na\nb", "a\nb\nb", " a\n-a\n+b\n b\n"},
+	}
+
+	for _, tt := range tests {
+		if got := render(Diff(tt.a, tt.b)); got != tt.want {
+			t.Errorf("%s: Diff(%q, %q) =\n%s\nwant\n%s", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// Every line of both texts shows up once, in order, whichever way Diff gets
+// there
+func checkComplete(t *testing.T, a, b string, lines []Line) {
+	t.Helper()
+	var before, after []string
+	for _, line := range lines {
+		if line.Op != Insert {
+			before = append(before, line.Text)
+		}
+		if line.Op != Delete {
+			after = append(after, line.Text)
+		}
+	}
+	if got := strings.Join(before, "\n"); got != a {
+		t.Errorf("the old side of the diff doesn't read back as a")
+	}
+	if got := strings.Join(after, "\n"); got != b {
+		t.Errorf("the new side of the diff doesn't read back as b")
+	}
+}
+
+func numbered(from, to int, prefix string) string {
+	lines := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		lines = append(lines, fmt.Sprintf("%s%d", prefix, i))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiffLarge(t *testing.T) {
+	// Small enough for the table: the one changed line is all that differs
+	a := numbered(0, 1000, "line ")
+	b := strings.Replace(a, "line 500\n", "changed\n", 1)
+	lines := Diff(a, b)
+	checkComplete(t, a, b, lines)
+	if added, removed := Count(lines); added != 1 || removed != 1 {
+		t.Errorf("one changed line counted as +%d -%d", added, removed)
+	}
+
+	// Past maxCells the changed middle comes back whole, removed then added.
+	// The lines kept at either end still are.
+	a = "top\n" + numbered(0, 2500, "a") + "\nbottom"
+	b = "top\n" + numbered(0, 2500, "b") + "\nbottom"
+	lines = Diff(a, b)
+	checkComplete(t, a, b, lines)
+	if added, removed := Count(lines); added != 2500 || removed != 2500 {
+		t.Errorf("got +%d -%d, want +2500 -2500", added, removed)
+	}
+	if lines[0] != (Line{Equal, "top"}) || lines[len(lines)-1] != (Line{Equal, "bottom"}) {
+		t.Errorf("the lines both texts start and end with aren't kept")
+	}
+	if lines[1].Op != Delete || lines[2501].Op != Insert {
+		t.Errorf("the middle isn't all removed lines then all added ones")
+	}
+
+	// With a common line in the middle past the cap, it is not found
+	a = numbered(0, 2500, "a") + "\nshared\n" + numbered(0, 2500, "c")
+	b = numbered(0, 2500, "b") + "\nshared\n" + numbered(0, 2500, "d")
+	lines = Diff(a, b)
+	checkComplete(t, a, b, lines)
+	if added, removed := Count(lines); added != 5001 || removed != 5001 {
+		t.Errorf("got +%d -%d past the cap, want +5001 -5001", added, removed)
+	}
+}
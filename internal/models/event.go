@@ -1,9 +1,12 @@
 package models
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
+
+	"prodBooster/internal/recur"
 )
 
 type Event struct {
@@ -13,67 +16,176 @@ type Event struct {
 	Location  string
 	StartTime time.Time
 	EndTime   time.Time
+	Timezone  string // IANA name the event was scheduled in, empty means local
+	Tags      []string
+
+	Recurrence string      // RRULE, empty untuk event sekali jalan
+	Exceptions []time.Time // occurrences of a recurring event that were skipped, by original start
+	Occurrence time.Time   // original start of an expanded occurrence, zero on stored events
+	DeletedAt  time.Time   // kapan dibuang ke trash, zero untuk event aktif
+	Pinned     bool        // stays at the top of the list and on the dashboard, with every occurrence
 }
 
-type EventList struct {
-	db       *sql.DB
-	Events   []*Event
-	Selected int
-	NextID   int
+// How far around now the calendar and dashboard expand recurring events,
+// and how far ahead GetUpcomingEvents looks
+const (
+	TimelinePast    = 30 * 24 * time.Hour
+	TimelineFuture  = 90 * 24 * time.Hour
+	upcomingHorizon = 366 * 24 * time.Hour
+)
+
+// IsRecurring reports whether the event repeats
+func (e *Event) IsRecurring() bool {
+	return e.Recurrence != ""
 }
 
-// Load - Load semua events dari database ke memory
-func (el *EventList) Load() error {
-	query := "SELECT id, title, description, location, start_time, end_time FROM events ORDER BY start_time"
-	rows, err := el.db.Query(query)
-	if err != nil {
-		return fmt.Errorf("failed to query events: %w", err)
+// Rule parses the event's recurrence. ok is false for one-off events and
+// for rules that no longer parse.
+func (e *Event) Rule() (rule recur.Rule, ok bool) {
+	if e.Recurrence == "" {
+		return recur.Rule{}, false
 	}
-	defer rows.Close()
+	rule, err := recur.Parse(e.Recurrence)
+	return rule, err == nil
+}
 
-	el.Events = []*Event{} // Clear existing
+// RuleError reports why the occurrences of a repeating event around now
+// can't be worked out, nil if they can: its rule no longer parses, then
+// only its first occurrence is shown, or now is too far into the series
+func (e *Event) RuleError(now time.Time) error {
+	if !e.IsRecurring() {
+		return nil
+	}
+	rule, err := recur.Parse(e.Recurrence)
+	if err != nil {
+		return fmt.Errorf("repeat rule %q: %w", e.Recurrence, err)
+	}
+	_, _, err = rule.Next(e.StartTime.In(e.Zone()), now)
+	return err
+}
 
-	for rows.Next() {
-		var id int
-		var title, description, location string
-		var startTime, endTime time.Time
+// IsOccurrence reports whether the event is one expanded occurrence of a
+// recurring event rather than the stored event itself
+func (e *Event) IsOccurrence() bool {
+	return !e.Occurrence.IsZero()
+}
 
-		if err := rows.Scan(&id, &title, &description, &location, &startTime, &endTime); err != nil {
-			return fmt.Errorf("failed to scan event: %w", err)
+// IsSkipped reports whether the occurrence starting at t was skipped
+func (e *Event) IsSkipped(t time.Time) bool {
+	t = t.Truncate(time.Second)
+	for _, skipped := range e.Exceptions {
+		if skipped.Equal(t) {
+			return true
 		}
+	}
+	return false
+}
 
-		event := &Event{
-			ID:        id,
-			Title:     title,
-			Content:   description,
-			Location:  location,
-			StartTime: startTime,
-			EndTime:   endTime,
+// Occurrences expands the event into the occurrences starting within
+// [from, to). Recurring events are expanded in their own time zone so a
+// 9:00 standup stays at 9:00 there across daylight saving changes; one-off
+// events come back as themselves, and so do recurring ones whose rule no
+// longer parses (RuleError says why).
+func (e *Event) Occurrences(from, to time.Time) []*Event {
+	rule, ok := e.Rule()
+	if !ok {
+		if e.StartTime.Before(from) || !e.StartTime.Before(to) {
+			return nil
 		}
+		return []*Event{e}
+	}
 
-		el.Events = append(el.Events, event)
+	var length time.Duration
+	if !e.EndTime.IsZero() {
+		length = e.EndTime.Sub(e.StartTime)
+	}
 
-		// Update NextID
-		if id >= el.NextID {
-			el.NextID = id + 1
+	// Too far into the series for expansion there are none, RuleError says why
+	starts, _ := rule.Between(e.StartTime.In(e.Zone()), from, to)
+
+	var occurrences []*Event
+	for _, start := range starts {
+		if e.IsSkipped(start) {
+			continue
+		}
+		occurrence := *e
+		occurrence.StartTime = start.Local()
+		if length > 0 {
+			occurrence.EndTime = occurrence.StartTime.Add(length)
 		}
+		occurrence.Occurrence = occurrence.StartTime
+		occurrences = append(occurrences, &occurrence)
+	}
+	return occurrences
+}
+
+// Zone returns the location the event was scheduled in. Unknown or empty
+// timezones fall back to local time.
+func (e *Event) Zone() *time.Location {
+	if e.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(e.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// HasForeignZone reports whether the event was scheduled in a timezone
+// other than the local one, so views know to show both
+func (e *Event) HasForeignZone() bool {
+	if e.Timezone == "" {
+		return false
+	}
+	zone := e.Zone()
+	_, localOffset := e.StartTime.In(time.Local).Zone()
+	_, zoneOffset := e.StartTime.In(zone).Zone()
+	return localOffset != zoneOffset
+}
+
+type EventList struct {
+	store    EventStore
+	Events   []*Event
+	Deleted  []*Event // trash, terbaru dulu
+	Selected int
+	NextID   int
+	history  *History  // records mutations for undo, nil if none
+	links    *LinkList // links carried over when a series is split, nil if none
+}
+
+// Load - Load semua events dari store ke memory
+func (el *EventList) Load() error {
+	events, err := el.store.LoadEvents()
+	if err != nil {
+		return err
+	}
+
+	deleted, err := el.store.LoadDeletedEvents()
+	if err != nil {
+		return err
 	}
 
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error iterating events: %w", err)
+	el.Events = events
+	el.Deleted = deleted
+	for _, event := range append(events, deleted...) {
+		// Update NextID
+		if event.ID >= el.NextID {
+			el.NextID = event.ID + 1
+		}
 	}
 
 	return nil
 }
 
-func NewEventList(db_ *sql.DB) *EventList {
+func NewEventList(store EventStore) *EventList {
 	el := &EventList{
-		db:       db_,
+		store:    store,
 		Events:   []*Event{},
 		Selected: 0,
 		NextID:   1,
 	}
-	// Auto-load dari database saat inisialisasi
+	// Auto-load dari store saat inisialisasi
 	if err := el.Load(); err != nil {
 		// Log error tapi tetap return instance kosong
 		fmt.Printf("Warning: failed to load events: %v\n", err)
@@ -108,20 +220,13 @@ func (el *EventList) GetTodayEvents() []*Event {
 	now := time.Now()
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	tomorrow := today.AddDate(0, 0, 1)
-	var todayEvents []*Event
-
-	for _, event := range el.Events {
-		if event.StartTime.After(today) && event.StartTime.Before(tomorrow) {
-			todayEvents = append(todayEvents, event)
-		}
-	}
-	return todayEvents
+	return el.Occurrences(today, tomorrow)
 }
 
 func (el *EventList) GetUpcomingEvents(count int) []*Event {
 	now := time.Now()
 	var upcomingEvents []*Event
-	for _, event := range el.Events {
+	for _, event := range el.Occurrences(now, now.Add(upcomingHorizon)) {
 		if event.StartTime.After(now) {
 			upcomingEvents = append(upcomingEvents, event)
 		}
@@ -132,75 +237,299 @@ func (el *EventList) GetUpcomingEvents(count int) []*Event {
 	return upcomingEvents
 }
 
-// Add - Tambah event ke database DAN memory sekaligus
-func (el *EventList) Add(title, content, location string, startTime, endTime time.Time) error {
-	query := `INSERT INTO events (title, description, location, start_time, end_time, created_at)
-	          VALUES (?, ?, ?, ?, ?, ?)`
+// Occurrences - Semua kejadian yang mulai dalam [from, to), urut waktu (hanya memory)
+func (el *EventList) Occurrences(from, to time.Time) []*Event {
+	var occurrences []*Event
+	for _, event := range el.Events {
+		occurrences = append(occurrences, event.Occurrences(from, to)...)
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartTime.Before(occurrences[j].StartTime)
+	})
+	return occurrences
+}
 
-	now := time.Now()
-	result, err := el.db.Exec(query, title, content, location, startTime, endTime, now)
-	if err != nil {
-		return fmt.Errorf("failed to add event to database: %w", err)
+// Timeline - Semua event sekali jalan plus kejadian event berulang di
+// sekitar now, untuk list di calendar dan dashboard (hanya memory)
+func (el *EventList) Timeline(now time.Time) []*Event {
+	from, to := now.Add(-TimelinePast), now.Add(TimelineFuture)
+
+	var events []*Event
+	for _, event := range el.Events {
+		if !event.IsRecurring() {
+			events = append(events, event)
+			continue
+		}
+		events = append(events, event.Occurrences(from, to)...)
 	}
+	return events
+}
+
+// Pinned - Event yang di-pin, urut waktu (hanya memory). Event berulang
+// muncul sekali, sebagai kejadian berikutnya atau yang terakhir kalau
+// semuanya sudah lewat.
+func (el *EventList) Pinned(now time.Time) []*Event {
+	var pinned []*Event
+	for _, event := range el.Events {
+		if !event.Pinned {
+			continue
+		}
+		if !event.IsRecurring() {
+			pinned = append(pinned, event)
+			continue
+		}
+		if next := event.Occurrences(now, now.Add(upcomingHorizon)); len(next) > 0 {
+			pinned = append(pinned, next[0])
+		} else if past := event.Occurrences(now.Add(-TimelinePast), now); len(past) > 0 {
+			pinned = append(pinned, past[len(past)-1])
+		}
+	}
+	sort.SliceStable(pinned, func(i, j int) bool {
+		return pinned[i].StartTime.Before(pinned[j].StartTime)
+	})
+	return pinned
+}
 
-	id, err := result.LastInsertId()
+// Get returns the stored event with the given id, or nil
+func (el *EventList) Get(id int) *Event {
+	return el.find(id)
+}
+
+// Add - Tambah event ke store DAN memory sekaligus
+func (el *EventList) Add(title, content, location, timezone string, startTime, endTime time.Time, tags []string, recurrence string) error {
+	defer el.history.track(describe("Add", "event", title))()
+
+	return el.insert(&Event{
+		Title:      title,
+		Content:    content,
+		Location:   location,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Timezone:   timezone,
+		Tags:       tags,
+		Recurrence: recurrence,
+	})
+}
+
+func (el *EventList) insert(event *Event) error {
+	id, err := el.store.InsertEvent(event)
 	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
+		return err
 	}
 
 	// Tambah ke memory
-	event := &Event{
-		ID:        int(id),
+	event.ID = id
+	el.Events = append(el.Events, event)
+	el.NextID = id + 1
+
+	return nil
+}
+
+// Update - Update event di store DAN memory sekaligus
+func (el *EventList) Update(id int, title, content, location, timezone string, startTime, endTime time.Time, tags []string, recurrence string) error {
+	defer el.history.track(describe("Edit", "event", title))()
+
+	event := el.find(id)
+	if event == nil {
+		return fmt.Errorf("event with id %d not found", id)
+	}
+
+	updated := *event
+	updated.Title = title
+	updated.Content = content
+	updated.Location = location
+	updated.StartTime = startTime
+	updated.EndTime = endTime
+	updated.Timezone = timezone
+	updated.Tags = tags
+	updated.Recurrence = recurrence
+
+	return el.save(event, updated)
+}
+
+// save writes updated to the store, then over event in memory
+func (el *EventList) save(event *Event, updated Event) error {
+	if err := el.store.UpdateEvent(&updated); err != nil {
+		return err
+	}
+
+	// Update di memory
+	*event = updated
+
+	return nil
+}
+
+// TogglePinned - Pin atau lepas pin event (seluruh series kalau berulang)
+// di store DAN memory sekaligus
+func (el *EventList) TogglePinned(id int) error {
+	event := el.find(id)
+	if event == nil {
+		return fmt.Errorf("event with id %d not found", id)
+	}
+
+	action := "Pin"
+	if event.Pinned {
+		action = "Unpin"
+	}
+	defer el.history.track(describe(action, "event", event.Title))()
+
+	updated := *event
+	updated.Pinned = !event.Pinned
+	return el.save(event, updated)
+}
+
+// SkipOccurrence - Lewati satu kejadian event berulang (exception di store)
+func (el *EventList) SkipOccurrence(id int, occurrence time.Time) error {
+	defer el.history.track(describe("Skip", "occurrence of", el.titleOf(id)))()
+
+	event := el.find(id)
+	if event == nil {
+		return fmt.Errorf("event with id %d not found", id)
+	}
+	if !event.IsRecurring() {
+		return errors.New("only repeating events have occurrences to skip")
+	}
+
+	updated := *event
+	updated.Exceptions = append(cloneTimes(event.Exceptions), occurrence.Truncate(time.Second))
+
+	return el.save(event, updated)
+}
+
+// UpdateOccurrence - Edit satu kejadian saja: kejadian itu dilewati di
+// series dan diganti event sekali jalan dengan isi baru
+func (el *EventList) UpdateOccurrence(id int, occurrence time.Time, title, content, location, timezone string, startTime, endTime time.Time, tags []string) error {
+	defer el.history.track(describe("Edit", "occurrence of", el.titleOf(id)))()
+
+	event := el.find(id)
+	if event == nil {
+		return fmt.Errorf("event with id %d not found", id)
+	}
+	if err := el.SkipOccurrence(id, occurrence); err != nil {
+		return err
+	}
+
+	single := &Event{
 		Title:     title,
 		Content:   content,
 		Location:  location,
 		StartTime: startTime,
 		EndTime:   endTime,
+		Timezone:  timezone,
+		Tags:      tags,
+		Pinned:    event.Pinned,
 	}
-	el.Events = append(el.Events, event)
-	el.NextID = int(id) + 1
+	if err := el.insert(single); err != nil {
+		return err
+	}
+	return el.links.copyLinks(ItemRef{Page: PageCalendar, ID: id}, ItemRef{Page: PageCalendar, ID: single.ID})
+}
 
-	return nil
+// UpdateFuture - Edit kejadian ini dan semua sesudahnya: series lama
+// berhenti sebelum occurrence dan series baru mulai dari startTime
+func (el *EventList) UpdateFuture(id int, occurrence time.Time, title, content, location, timezone string, startTime, endTime time.Time, tags []string, recurrence string) error {
+	defer el.history.track(describe("Edit", "future occurrences of", el.titleOf(id)))()
+
+	event := el.find(id)
+	if event == nil {
+		return fmt.Errorf("event with id %d not found", id)
+	}
+	if !occurrence.After(event.StartTime) {
+		// Dari kejadian pertama berarti seluruh series
+		return el.Update(id, title, content, location, timezone, startTime, endTime, tags, recurrence)
+	}
+
+	// Skipped dates keep their place relative to the moved occurrences
+	shift := startTime.Sub(occurrence)
+	var exceptions []time.Time
+	for _, skipped := range event.Exceptions {
+		if !skipped.Before(occurrence) {
+			exceptions = append(exceptions, skipped.Add(shift))
+		}
+	}
+
+	// An unchanged COUNT covers the whole series, so the new half only gets
+	// what the old half hasn't used
+	if rule, ok := event.Rule(); ok && rule.Count > 0 && recurrence == event.Recurrence {
+		used, err := rule.Between(event.StartTime.In(event.Zone()), event.StartTime, occurrence)
+		if err != nil {
+			return err
+		}
+		rule.Count = max(rule.Count-len(used), 1)
+		recurrence = rule.String()
+	}
+
+	if err := el.EndSeriesBefore(id, occurrence); err != nil {
+		return err
+	}
+
+	future := &Event{
+		Title:      title,
+		Content:    content,
+		Location:   location,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Timezone:   timezone,
+		Tags:       tags,
+		Recurrence: recurrence,
+		Exceptions: exceptions,
+		Pinned:     event.Pinned,
+	}
+	if err := el.insert(future); err != nil {
+		return err
+	}
+	return el.links.copyLinks(ItemRef{Page: PageCalendar, ID: id}, ItemRef{Page: PageCalendar, ID: future.ID})
 }
 
-// Update - Update event di database DAN memory sekaligus
-func (el *EventList) Update(id int, title, content, location string, startTime, endTime time.Time) error {
-	query := `UPDATE events SET title=?, description=?, location=?, start_time=?, end_time=?, updated_at=CURRENT_TIMESTAMP
-	          WHERE id=?`
+// EndSeriesBefore - Hentikan event berulang sebelum occurrence; dari
+// kejadian pertama berarti hapus seluruh series
+func (el *EventList) EndSeriesBefore(id int, occurrence time.Time) error {
+	defer el.history.track(describe("Delete", "future occurrences of", el.titleOf(id)))()
 
-	_, err := el.db.Exec(query, title, content, location, startTime, endTime, id)
-	if err != nil {
-		return fmt.Errorf("failed to update event in database: %w", err)
+	event := el.find(id)
+	if event == nil {
+		return fmt.Errorf("event with id %d not found", id)
+	}
+	rule, ok := event.Rule()
+	if !ok {
+		return errors.New("only repeating events can be ended early")
+	}
+	if !occurrence.After(event.StartTime) {
+		return el.Remove(id)
 	}
 
-	// Update di memory
-	for _, event := range el.Events {
-		if event.ID == id {
-			event.Title = title
-			event.Content = content
-			event.Location = location
-			event.StartTime = startTime
-			event.EndTime = endTime
-			break
+	until := occurrence.Add(-time.Second)
+	if end := rule.End(event.Zone()); end.IsZero() || until.Before(end) {
+		rule.Until, rule.UntilDate = until, false
+	}
+	rule.Count = 0
+
+	updated := *event
+	updated.Recurrence = rule.String()
+	updated.Exceptions = nil
+	for _, skipped := range event.Exceptions {
+		if skipped.Before(occurrence) {
+			updated.Exceptions = append(updated.Exceptions, skipped)
 		}
 	}
 
-	return nil
+	return el.save(event, updated)
 }
 
-// Remove - Hapus event dari database DAN memory sekaligus
+// Remove - Pindahkan event (semua kejadiannya) ke trash di store DAN memory sekaligus
 func (el *EventList) Remove(id int) error {
-	query := `DELETE FROM events WHERE id=?`
+	defer el.history.track(describe("Delete", "event", el.titleOf(id)))()
 
-	_, err := el.db.Exec(query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete event from database: %w", err)
+	if err := el.store.DeleteEvent(id); err != nil {
+		return err
 	}
 
-	// Hapus dari memory
+	// Pindah ke trash di memory
 	for i, event := range el.Events {
 		if event.ID == id {
 			el.Events = append(el.Events[:i], el.Events[i+1:]...)
+			event.DeletedAt = time.Now()
+			el.Deleted = append([]*Event{event}, el.Deleted...)
 			// Adjust selected index
 			if el.Selected >= len(el.Events) && el.Selected > 0 {
 				el.Selected--
@@ -211,3 +540,87 @@ func (el *EventList) Remove(id int) error {
 
 	return nil
 }
+
+// Restore - Kembalikan event dari trash di store DAN memory sekaligus
+func (el *EventList) Restore(id int) error {
+	defer el.history.track(describe("Restore", "event", el.titleOf(id)))()
+
+	if err := el.store.RestoreEvent(id); err != nil {
+		return err
+	}
+
+	for i, event := range el.Deleted {
+		if event.ID == id {
+			el.Deleted = append(el.Deleted[:i], el.Deleted[i+1:]...)
+			event.DeletedAt = time.Time{}
+			el.Events = append(el.Events, event)
+			break
+		}
+	}
+
+	return nil
+}
+
+// Purge - Hapus event dari trash untuk selamanya
+func (el *EventList) Purge(id int) error {
+	defer el.history.track(describe("Delete forever", "event", el.titleOf(id)))()
+
+	if err := el.store.PurgeEvent(id); err != nil {
+		return err
+	}
+
+	for i, event := range el.Deleted {
+		if event.ID == id {
+			el.Deleted = append(el.Deleted[:i], el.Deleted[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// PurgeTrash - Hapus selamanya semua event yang dibuang sebelum cutoff
+func (el *EventList) PurgeTrash(cutoff time.Time) error {
+	for _, event := range append([]*Event{}, el.Deleted...) {
+		if event.DeletedAt.Before(cutoff) {
+			if err := el.Purge(event.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AllTags - Semua tag yang dipakai events (hanya memory)
+func (el *EventList) AllTags() []string {
+	lists := make([][]string, len(el.Events))
+	for i, event := range el.Events {
+		lists[i] = event.Tags
+	}
+	return collectTags(lists...)
+}
+
+// Search - Cari event aktif lewat store (full-text), hasil terbaik dulu
+func (el *EventList) Search(query string) ([]SearchHit, error) {
+	return el.store.SearchEvents(query)
+}
+
+// titleOf returns the title of an event, trashed or not
+func (el *EventList) titleOf(id int) string {
+	for _, event := range append(append([]*Event{}, el.Events...), el.Deleted...) {
+		if event.ID == id {
+			return event.Title
+		}
+	}
+	return ""
+}
+
+// find returns the in-memory event with the given id, or nil
+func (el *EventList) find(id int) *Event {
+	for _, event := range el.Events {
+		if event.ID == id {
+			return event
+		}
+	}
+	return nil
+}
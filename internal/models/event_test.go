@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrashEvent(t *testing.T) {
+	store := NewMemoryEventStore()
+	el := NewEventList(store)
+	start := tomorrow()
+	if err := el.Add("Standup", "", "", "", start, start.Add(15*time.Minute), nil, "FREQ=DAILY"); err != nil {
+		t.Fatal(err)
+	}
+	event := el.Events[0]
+
+	if err := el.Remove(event.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := el.Occurrences(start, start.AddDate(0, 0, 7)); len(got) != 0 {
+		t.Errorf("a trashed series still has %d occurrences", len(got))
+	}
+	if err := el.Restore(event.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := el.Occurrences(start, start.AddDate(0, 0, 7)); len(got) != 7 {
+		t.Errorf("the restored series has %d occurrences in a week, want 7", len(got))
+	}
+
+	if err := el.Remove(event.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := el.Purge(event.ID); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewEventList(store)
+	if len(reloaded.Events) != 0 || len(reloaded.Deleted) != 0 {
+		t.Errorf("reloaded %d live and %d trashed events after purging, want none", len(reloaded.Events), len(reloaded.Deleted))
+	}
+}
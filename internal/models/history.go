@@ -0,0 +1,495 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// === Undo/redo ===
+// A History is shared by the lists it is attached to: todos, notes, events,
+// links, notebooks and saved views. Every mutation through those lists is
+// recorded as a Command holding the state of each item it touched before and
+// after the change. Undo writes the before states back through the stores,
+// redo the after states, so a Command can be replayed in either direction
+// without knowing which method made it.
+//
+// Which items a mutation touches is noted by the stores themselves, see
+// recorder.go. Commands are persisted through a HistoryStore, so undo keeps
+// working after a restart.
+
+// HistoryLimit is how many commands are kept for undo
+const HistoryLimit = 100
+
+// Kinds of items a Change can hold
+const (
+	ChangeTodo       = "todo"
+	ChangeNote       = "note"
+	ChangeEvent      = "event"
+	ChangeCompletion = "completion" // keyed by todo id
+	ChangeLink       = "link"
+	ChangeNotebook   = "notebook"
+	ChangeView       = "view"
+)
+
+// changeOrder is the order items are written back in, so what an item
+// points at exists first: notes sit in notebooks, completions belong to
+// todos and links join items. Deletes go the other way round.
+var changeOrder = map[string]int{
+	ChangeNotebook:   0,
+	ChangeTodo:       1,
+	ChangeNote:       1,
+	ChangeEvent:      1,
+	ChangeCompletion: 2,
+	ChangeLink:       3,
+	ChangeView:       3,
+}
+
+// Change is the state of one item before and after a command, as JSON.
+// Before is empty for items the command created, After for items it deleted
+// for good.
+type Change struct {
+	Kind   string          `json:"kind"`
+	ID     int             `json:"id"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Command is one undoable user action
+type Command struct {
+	ID        int
+	Label     string // e.g. Delete todo “Buy milk”
+	Changes   []Change
+	Undone    bool // true while the command sits on the redo stack
+	CreatedAt time.Time
+}
+
+// HistoryStore persists undo history
+type HistoryStore interface {
+	// LoadCommands returns every stored command ordered by id
+	LoadCommands() ([]*Command, error)
+	// InsertCommand saves a new command and returns its id
+	InsertCommand(cmd *Command) (int, error)
+	SetCommandUndone(id int, undone bool) error
+	DeleteCommands(ids []int) error
+}
+
+type History struct {
+	store     HistoryStore
+	todos     *TodoList
+	notes     *NoteList
+	events    *EventList
+	links     *LinkList
+	notebooks *NotebookList
+	views     *ViewList
+
+	done   []*Command // undo stack, most recent last
+	undone []*Command // redo stack, next to redo last
+
+	depth    int  // nesting of tracked mutations, only the outermost records
+	applying bool // set while undo/redo writes states back
+
+	// State of each item the current command touched, from before it did.
+	// nil for items it created.
+	touched map[snapshotKey]json.RawMessage
+
+	err error // why the last command couldn't be saved, see Err
+}
+
+// NewHistory loads the stored history and starts recording every mutation
+// of the given lists
+func NewHistory(store HistoryStore, todos *TodoList, notes *NoteList, events *EventList, links *LinkList, notebooks *NotebookList, views *ViewList) *History {
+	h := &History{store: store, todos: todos, notes: notes, events: events, links: links, notebooks: notebooks, views: views}
+	todos.history = h
+	notes.history = h
+	events.history = h
+	links.history = h
+	notebooks.history = h
+	views.history = h
+
+	todos.store = todoRecorder{todos.store, h}
+	notes.store = noteRecorder{notes.store, h}
+	events.store = eventRecorder{events.store, h}
+	links.store = linkRecorder{links.store, h}
+	notebooks.store = notebookRecorder{notebooks.store, h}
+	views.store = viewRecorder{views.store, h}
+
+	if err := h.Load(); err != nil {
+		// Log error tapi tetap jalan dengan history kosong
+		fmt.Printf("Warning: failed to load undo history: %v\n", err)
+	}
+	return h
+}
+
+// Load - Load undo dan redo stack dari store
+func (h *History) Load() error {
+	commands, err := h.store.LoadCommands()
+	if err != nil {
+		return err
+	}
+
+	h.done, h.undone = nil, nil
+	for _, cmd := range commands {
+		if cmd.Undone {
+			// The oldest undone command is the next one to redo
+			h.undone = append([]*Command{cmd}, h.undone...)
+		} else {
+			h.done = append(h.done, cmd)
+		}
+	}
+	return nil
+}
+
+// Err returns, once, why the history couldn't save the last change. That
+// change is made but can't be undone.
+func (h *History) Err() error {
+	err := h.err
+	h.err = nil
+	return err
+}
+
+// CanUndo reports whether there is anything to undo
+func (h *History) CanUndo() bool {
+	return len(h.done) > 0
+}
+
+// CanRedo reports whether there is anything to redo
+func (h *History) CanRedo() bool {
+	return len(h.undone) > 0
+}
+
+// Undo reverts the most recent command and returns it, or nil when there is
+// nothing to undo
+func (h *History) Undo() (*Command, error) {
+	if len(h.done) == 0 {
+		return nil, nil
+	}
+	cmd := h.done[len(h.done)-1]
+
+	if err := h.apply(cmd, true); err != nil {
+		return nil, err
+	}
+	if err := h.store.SetCommandUndone(cmd.ID, true); err != nil {
+		return nil, err
+	}
+
+	cmd.Undone = true
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, cmd)
+	return cmd, nil
+}
+
+// Redo replays the most recently undone command and returns it, or nil when
+// there is nothing to redo
+func (h *History) Redo() (*Command, error) {
+	if len(h.undone) == 0 {
+		return nil, nil
+	}
+	cmd := h.undone[len(h.undone)-1]
+
+	if err := h.apply(cmd, false); err != nil {
+		return nil, err
+	}
+	if err := h.store.SetCommandUndone(cmd.ID, false); err != nil {
+		return nil, err
+	}
+
+	cmd.Undone = false
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, cmd)
+	return cmd, nil
+}
+
+// Group runs fn as a single command, so e.g. emptying the trash is undone
+// in one step
+func (h *History) Group(label string, fn func() error) error {
+	defer h.track(label)()
+	return fn()
+}
+
+// track starts recording a mutation. The returned func records what the
+// items it touched look like now, and must be called once the mutation is
+// done:
+//
+//	defer tl.history.track("Add todo")()
+//
+// Mutations made by another tracked mutation (ToggleCompleted completing the
+// parent, say) end up in the outermost command. A nil History tracks nothing.
+func (h *History) track(label string) func() {
+	if h == nil || h.applying {
+		return func() {}
+	}
+
+	h.depth++
+	if h.depth > 1 {
+		return func() { h.depth-- }
+	}
+
+	h.touched = map[snapshotKey]json.RawMessage{}
+	return func() {
+		changes := h.changes()
+		h.depth--
+		h.touched = nil
+		if len(changes) == 0 {
+			return
+		}
+		h.push(&Command{Label: label, Changes: changes, CreatedAt: time.Now()})
+	}
+}
+
+// push adds a new command to the undo stack, which drops the redo stack.
+// A command that can't be saved isn't kept either, so the stacks never
+// hold anything the store doesn't; Err reports it.
+func (h *History) push(cmd *Command) {
+	stale := []int{}
+	for _, undone := range h.undone {
+		stale = append(stale, undone.ID)
+	}
+	h.undone = nil
+
+	id, err := h.store.InsertCommand(cmd)
+	if err != nil {
+		h.err = fmt.Errorf("this change can't be undone: %w", err)
+	} else {
+		cmd.ID = id
+		h.done = append(h.done, cmd)
+	}
+	for len(h.done) > HistoryLimit {
+		stale = append(stale, h.done[0].ID)
+		h.done = h.done[1:]
+	}
+
+	if len(stale) > 0 {
+		if err := h.store.DeleteCommands(stale); err != nil && h.err == nil {
+			// Left in the store they would come back as redo steps after a restart
+			h.err = err
+		}
+	}
+}
+
+// snapshotKey identifies an item across snapshots
+type snapshotKey struct {
+	kind string
+	id   int
+}
+
+// touch notes the state of an item the current command is about to change,
+// the first time it does. Outside a command, and while undo or redo writes
+// states back, nothing is recorded.
+func (h *History) touch(kind string, id int) {
+	if h.depth == 0 || h.applying {
+		return
+	}
+	key := snapshotKey{kind, id}
+	if _, ok := h.touched[key]; !ok {
+		h.touched[key] = h.state(kind, id)
+	}
+}
+
+// state returns an item as it is in memory, nil when there is no such item
+func (h *History) state(kind string, id int) json.RawMessage {
+	switch kind {
+	case ChangeTodo:
+		if todo := findTodo(append(append([]*Todo{}, h.todos.Todos...), h.todos.Deleted...), id); todo != nil {
+			return marshal(todo)
+		}
+	case ChangeCompletion:
+		for _, completion := range h.todos.completions {
+			if completion.TodoID == id {
+				return marshal(completion)
+			}
+		}
+	case ChangeNote:
+		for _, note := range append(append([]*Note{}, h.notes.Notes...), h.notes.Deleted...) {
+			if note.ID == id {
+				return marshal(note)
+			}
+		}
+	case ChangeEvent:
+		for _, event := range append(append([]*Event{}, h.events.Events...), h.events.Deleted...) {
+			if event.ID == id {
+				return marshal(event)
+			}
+		}
+	case ChangeLink:
+		if link := h.links.get(id); link != nil {
+			return marshal(link)
+		}
+	case ChangeNotebook:
+		if notebook := h.notebooks.Get(id); notebook != nil {
+			return marshal(notebook)
+		}
+	case ChangeView:
+		if view := h.views.find(id); view != nil {
+			return marshal(view)
+		}
+	}
+	return nil
+}
+
+func marshal(item any) json.RawMessage {
+	data, err := json.Marshal(item)
+	if err != nil {
+		// Models only hold plain data, this can't happen
+		panic(err)
+	}
+	return data
+}
+
+// changes lists the touched items that differ from how they were before
+func (h *History) changes() []Change {
+	changes := []Change{}
+	for key, before := range h.touched {
+		after := h.state(key.kind, key.id)
+		if string(before) != string(after) {
+			changes = append(changes, Change{Kind: key.kind, ID: key.id, Before: before, After: after})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].ID < changes[j].ID
+	})
+	return changes
+}
+
+// apply writes the before (undo) or after (redo) state of every change back
+// through the stores, then reloads the lists
+func (h *History) apply(cmd *Command, undo bool) error {
+	h.applying = true
+	defer func() { h.applying = false }()
+
+	state := func(change Change) json.RawMessage {
+		if undo {
+			return change.Before
+		}
+		return change.After
+	}
+
+	// Delete first, newest items first so subtasks go before their parent,
+	// then write back oldest first so parents exist before their subtasks.
+	// Across kinds changeOrder decides, e.g. completions point at todos, so
+	// they are deleted before and written after them.
+	var deletes, puts []Change
+	for _, change := range cmd.Changes {
+		if len(state(change)) == 0 {
+			deletes = append(deletes, change)
+		} else {
+			puts = append(puts, change)
+		}
+	}
+	sort.SliceStable(deletes, func(i, j int) bool {
+		if order := changeOrder[deletes[i].Kind] - changeOrder[deletes[j].Kind]; order != 0 {
+			return order > 0
+		}
+		return deletes[i].ID > deletes[j].ID
+	})
+	sort.SliceStable(puts, func(i, j int) bool {
+		if order := changeOrder[puts[i].Kind] - changeOrder[puts[j].Kind]; order != 0 {
+			return order < 0
+		}
+		return puts[i].ID < puts[j].ID
+	})
+
+	err := h.write(deletes, puts, state)
+
+	// Reload even after a failure so the lists show what actually got
+	// written. Links go before notes, loading notes re-indexes wiki links.
+	loads := []func() error{h.notebooks.Load, h.todos.Load, h.events.Load, h.links.Load, h.notes.Load, h.views.Load}
+	for _, load := range loads {
+		if loadErr := load(); err == nil {
+			err = loadErr
+		}
+	}
+	return err
+}
+
+func (h *History) write(deletes, puts []Change, state func(Change) json.RawMessage) error {
+	var err error
+	for _, change := range deletes {
+		switch change.Kind {
+		case ChangeTodo:
+			err = h.todos.store.PurgeTodo(change.ID)
+		case ChangeCompletion:
+			err = h.todos.store.RemoveCompletion(change.ID)
+		case ChangeNote:
+			err = h.notes.store.PurgeNote(change.ID)
+		case ChangeEvent:
+			err = h.events.store.PurgeEvent(change.ID)
+		case ChangeLink:
+			err = h.links.store.DeleteLink(change.ID)
+		case ChangeNotebook:
+			err = h.notebooks.store.DeleteNotebook(change.ID)
+		case ChangeView:
+			err = h.views.store.DeleteView(change.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for _, change := range puts {
+		if err := h.put(change.Kind, state(change)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put writes one item back as it was recorded
+func (h *History) put(kind string, data json.RawMessage) error {
+	switch kind {
+	case ChangeTodo:
+		var todo Todo
+		if err := json.Unmarshal(data, &todo); err != nil {
+			return fmt.Errorf("failed to read todo from history: %w", err)
+		}
+		return h.todos.store.PutTodo(&todo)
+	case ChangeCompletion:
+		var completion TodoCompletion
+		if err := json.Unmarshal(data, &completion); err != nil {
+			return fmt.Errorf("failed to read completion from history: %w", err)
+		}
+		if err := h.todos.store.RemoveCompletion(completion.TodoID); err != nil {
+			return err
+		}
+		return h.todos.store.RecordCompletion(completion)
+	case ChangeNote:
+		var note Note
+		if err := json.Unmarshal(data, &note); err != nil {
+			return fmt.Errorf("failed to read note from history: %w", err)
+		}
+		return h.notes.store.PutNote(&note)
+	case ChangeEvent:
+		var event Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to read event from history: %w", err)
+		}
+		return h.events.store.PutEvent(&event)
+	case ChangeLink:
+		var link Link
+		if err := json.Unmarshal(data, &link); err != nil {
+			return fmt.Errorf("failed to read link from history: %w", err)
+		}
+		return h.links.store.PutLink(&link)
+	case ChangeNotebook:
+		var notebook Notebook
+		if err := json.Unmarshal(data, &notebook); err != nil {
+			return fmt.Errorf("failed to read notebook from history: %w", err)
+		}
+		return h.notebooks.store.PutNotebook(&notebook)
+	case ChangeView:
+		var view SavedView
+		if err := json.Unmarshal(data, &view); err != nil {
+			return fmt.Errorf("failed to read saved view from history: %w", err)
+		}
+		return h.views.store.PutView(&view)
+	}
+	return fmt.Errorf("unknown history item %q", kind)
+}
+
+// describe builds a command label like Delete todo “Buy milk”
+func describe(action, kind, title string) string {
+	return fmt.Sprintf("%s %s “%s”", action, kind, title)
+}
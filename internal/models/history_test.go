@@ -0,0 +1,294 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+// lists is every list a History records, on memory stores
+type lists struct {
+	todoStore     *MemoryTodoStore
+	noteStore     *MemoryNoteStore
+	eventStore    *MemoryEventStore
+	linkStore     *MemoryLinkStore
+	notebookStore *MemoryNotebookStore
+	viewStore     *MemoryViewStore
+
+	todos     *TodoList
+	notes     *NoteList
+	events    *EventList
+	links     *LinkList
+	notebooks *NotebookList
+	views     *ViewList
+	history   *History
+}
+
+func newLists(history HistoryStore) *lists {
+	l := &lists{
+		todoStore:     NewMemoryTodoStore(),
+		noteStore:     NewMemoryNoteStore(),
+		eventStore:    NewMemoryEventStore(),
+		linkStore:     NewMemoryLinkStore(),
+		notebookStore: NewMemoryNotebookStore(),
+		viewStore:     NewMemoryViewStore(),
+	}
+	l.load()
+	l.history = NewHistory(history, l.todos, l.notes, l.events, l.links, l.notebooks, l.views)
+	return l
+}
+
+// load reads the lists back from their stores
+func (l *lists) load() {
+	l.todos = NewTodoList(l.todoStore)
+	l.notes = NewNoteList(l.noteStore)
+	l.events = NewEventList(l.eventStore)
+	l.links = NewLinkList(l.linkStore, l.todos, l.notes, l.events)
+	l.notebooks = NewNotebookList(l.notebookStore, l.notes)
+	l.views = NewViewList(l.viewStore)
+}
+
+// snapshot renders everything the lists hold, ordered by id so lists read
+// back from the stores compare equal
+func (l *lists) snapshot(t *testing.T) string {
+	t.Helper()
+	byID := func(items any, id func(i int) int) {
+		sort.SliceStable(items, func(i, j int) bool { return id(i) < id(j) })
+	}
+
+	todos := append(append([]*Todo{}, l.todos.Todos...), l.todos.Deleted...)
+	byID(todos, func(i int) int { return todos[i].ID })
+	completions := append([]TodoCompletion{}, l.todos.completions...)
+	sort.SliceStable(completions, func(i, j int) bool {
+		return completions[i].CompletedAt.Before(completions[j].CompletedAt)
+	})
+	notes := append(append([]*Note{}, l.notes.Notes...), l.notes.Deleted...)
+	byID(notes, func(i int) int { return notes[i].ID })
+	events := append(append([]*Event{}, l.events.Events...), l.events.Deleted...)
+	byID(events, func(i int) int { return events[i].ID })
+	links := append([]*Link{}, l.links.Links...)
+	byID(links, func(i int) int { return links[i].ID })
+
+	data, err := json.Marshal(map[string]any{
+		"todos":       todos,
+		"completions": completions,
+		"notes":       notes,
+		"events":      events,
+		"links":       links,
+		"notebooks":   l.notebooks.Notebooks,
+		"views":       l.views.Views,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// stored is the snapshot of the lists as their stores have them
+func (l *lists) stored(t *testing.T) string {
+	t.Helper()
+	reloaded := &lists{
+		todoStore:     l.todoStore,
+		noteStore:     l.noteStore,
+		eventStore:    l.eventStore,
+		linkStore:     l.linkStore,
+		notebookStore: l.notebookStore,
+		viewStore:     l.viewStore,
+	}
+	reloaded.load()
+	return reloaded.snapshot(t)
+}
+
+func (l *lists) todo(t *testing.T, title string) *Todo {
+	t.Helper()
+	for _, todo := range append(append([]*Todo{}, l.todos.Todos...), l.todos.Deleted...) {
+		if todo.Title == title && !todo.Completed {
+			return todo
+		}
+	}
+	t.Fatalf("no open todo %q", title)
+	return nil
+}
+
+func (l *lists) note(t *testing.T, title string) *Note {
+	t.Helper()
+	for _, note := range append(append([]*Note{}, l.notes.Notes...), l.notes.Deleted...) {
+		if note.Title == title {
+			return note
+		}
+	}
+	t.Fatalf("no note %q", title)
+	return nil
+}
+
+func TestUndoRedo(t *testing.T) {
+	l := newLists(NewMemoryHistoryStore())
+	due := tomorrow()
+	start := due.Add(time.Hour)
+
+	steps := []struct {
+		name string
+		do   func(t *testing.T) error
+	}{
+		{"add todo", func(t *testing.T) error {
+			return l.todos.Add("Buy milk", "", PriorityLow, nil, []string{"errand"}, "")
+		}},
+		{"add recurring todo", func(t *testing.T) error {
+			return l.todos.Add("Water plants", "", PriorityMedium, &due, nil, "FREQ=DAILY")
+		}},
+		{"add subtask", func(t *testing.T) error {
+			return l.todos.AddSubtask(l.todo(t, "Water plants").ID, "Fill the can", "", PriorityMedium, nil, nil)
+		}},
+		{"complete recurring todo", func(t *testing.T) error {
+			return l.todos.ToggleCompleted(l.todo(t, "Water plants").ID)
+		}},
+		{"complete last subtask", func(t *testing.T) error {
+			return l.todos.ToggleCompleted(l.todo(t, "Fill the can").ID)
+		}},
+		{"trash todo", func(t *testing.T) error {
+			return l.todos.Remove(l.todo(t, "Buy milk").ID)
+		}},
+		{"purge todo", func(t *testing.T) error {
+			return l.todos.Purge(l.todo(t, "Buy milk").ID)
+		}},
+		{"add note", func(t *testing.T) error {
+			return l.notes.Add("Plants", "Ferns like shade, see [[Watering]]", nil)
+		}},
+		{"edit note", func(t *testing.T) error {
+			note := l.note(t, "Plants")
+			return l.notes.Update(note.ID, note.Title, "Ferns like shade", note.Tags)
+		}},
+		{"restore revision", func(t *testing.T) error {
+			note := l.note(t, "Plants")
+			revisions, err := l.notes.Revisions(note.ID)
+			if err != nil {
+				return err
+			}
+			return l.notes.RestoreRevision(note.ID, revisions[len(revisions)-1].ID)
+		}},
+		{"add notebook", func(t *testing.T) error {
+			return l.notebooks.Add("Garden", 0)
+		}},
+		{"move note", func(t *testing.T) error {
+			return l.notes.Move(l.note(t, "Plants").ID, l.notebooks.Notebooks[0].ID)
+		}},
+		{"trash note", func(t *testing.T) error {
+			return l.notes.Remove(l.note(t, "Watering").ID)
+		}},
+		{"add event", func(t *testing.T) error {
+			return l.events.Add("Garden centre", "", "", "", start, start.Add(time.Hour), nil, "FREQ=WEEKLY")
+		}},
+		{"link", func(t *testing.T) error {
+			return l.links.Add(ItemRef{Page: PageCalendar, ID: l.events.Events[0].ID}, ItemRef{Page: PageNotes, ID: l.note(t, "Plants").ID})
+		}},
+		{"change one occurrence", func(t *testing.T) error {
+			event := l.events.Events[0]
+			return l.events.UpdateOccurrence(event.ID, event.StartTime, "Garden centre sale", "", "", "", event.StartTime, event.EndTime, nil)
+		}},
+		{"save view", func(t *testing.T) error {
+			return l.views.Add("Errands", PageTodos, "#errand", 0, "")
+		}},
+	}
+
+	for _, step := range steps {
+		before := l.snapshot(t)
+		if err := step.do(t); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		after := l.snapshot(t)
+		if after == before {
+			t.Fatalf("%s changed nothing", step.name)
+		}
+
+		if _, err := l.history.Undo(); err != nil {
+			t.Fatalf("undo %s: %v", step.name, err)
+		}
+		if got := l.snapshot(t); got != before {
+			t.Errorf("undo %s:\ngot  %s\nwant %s", step.name, got, before)
+		}
+		if got := l.stored(t); got != before {
+			t.Errorf("undo %s, stored:\ngot  %s\nwant %s", step.name, got, before)
+		}
+
+		if _, err := l.history.Redo(); err != nil {
+			t.Fatalf("redo %s: %v", step.name, err)
+		}
+		if got := l.snapshot(t); got != after {
+			t.Errorf("redo %s:\ngot  %s\nwant %s", step.name, got, after)
+		}
+		if got := l.stored(t); got != after {
+			t.Errorf("redo %s, stored:\ngot  %s\nwant %s", step.name, got, after)
+		}
+	}
+
+	// Undoing everything leaves nothing behind
+	for l.history.CanUndo() {
+		if _, err := l.history.Undo(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	empty := newLists(NewMemoryHistoryStore())
+	if got, want := l.snapshot(t), empty.snapshot(t); got != want {
+		t.Errorf("after undoing everything:\ngot  %s\nwant %s", got, want)
+	}
+}
+
+func TestUndoAfterRestart(t *testing.T) {
+	store := NewMemoryHistoryStore()
+	l := newLists(store)
+	if err := l.todos.Add("Buy milk", "", PriorityLow, nil, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.todos.Remove(l.todo(t, "Buy milk").ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same stores, new lists and history, like the next run of the app
+	l.load()
+	l.history = NewHistory(store, l.todos, l.notes, l.events, l.links, l.notebooks, l.views)
+
+	cmd, err := l.history.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd == nil || cmd.Label != "Delete todo “Buy milk”" {
+		t.Fatalf("undid %+v, want the delete", cmd)
+	}
+	if len(l.todos.Todos) != 1 || len(l.todos.Deleted) != 0 {
+		t.Errorf("got %d live and %d trashed todos, want the todo back", len(l.todos.Todos), len(l.todos.Deleted))
+	}
+	if !l.history.CanRedo() {
+		t.Error("nothing to redo after undo")
+	}
+}
+
+// failingHistoryStore can't save new commands
+type failingHistoryStore struct {
+	*MemoryHistoryStore
+}
+
+func (failingHistoryStore) InsertCommand(*Command) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestUnsavedCommand(t *testing.T) {
+	l := newLists(failingHistoryStore{NewMemoryHistoryStore()})
+	if err := l.todos.Add("Buy milk", "", PriorityLow, nil, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(l.todos.Todos) != 1 {
+		t.Error("the change wasn't made")
+	}
+	if l.history.CanUndo() {
+		t.Error("a command that wasn't saved can be undone")
+	}
+	if err := l.history.Err(); err == nil {
+		t.Error("Err didn't report the unsaved command")
+	}
+	if err := l.history.Err(); err != nil {
+		t.Errorf("Err reported %v twice", err)
+	}
+}
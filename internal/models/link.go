@@ -0,0 +1,275 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ItemRef points at a todo, note or event by the page it lives on
+type ItemRef struct {
+	Page PageType // PageTodos, PageNotes or PageCalendar
+	ID   int
+}
+
+// Link connects two items, e.g. meeting notes to the event they were taken
+// at. Links have a direction: From lists To under its linked items, To lists
+// From under referenced by.
+type Link struct {
+	ID        int
+	From      ItemRef
+	To        ItemRef
+	Wiki      bool // from a [[link]] in the note's text, kept in sync with it
+	CreatedAt time.Time
+}
+
+type LinkList struct {
+	store   LinkStore
+	todos   *TodoList
+	notes   *NoteList
+	events  *EventList
+	Links   []*Link  // oldest first
+	history *History // records mutations for undo, nil if none
+}
+
+// Load - Load semua link dari store ke memory
+func (ll *LinkList) Load() error {
+	links, err := ll.store.LoadLinks()
+	if err != nil {
+		return err
+	}
+	ll.Links = links
+	return nil
+}
+
+// NewLinkList loads the stored links between items of the given lists and
+// starts indexing the [[wiki links]] of saved notes
+func NewLinkList(store LinkStore, todos *TodoList, notes *NoteList, events *EventList) *LinkList {
+	ll := &LinkList{
+		store:  store,
+		todos:  todos,
+		notes:  notes,
+		events: events,
+		Links:  []*Link{},
+	}
+	notes.links = ll
+	events.links = ll
+
+	// Auto-load dari store saat inisialisasi
+	if err := ll.Load(); err != nil {
+		// Log error tapi tetap return instance kosong
+		fmt.Printf("Warning: failed to load links: %v\n", err)
+		return ll
+	}
+	// Catch up with notes written before the index existed
+	if err := ll.syncAllWiki(); err != nil {
+		fmt.Printf("Warning: failed to index wiki links: %v\n", err)
+	}
+	return ll
+}
+
+// Add - Simpan link baru dari from ke to, ke store DAN memory sekaligus
+func (ll *LinkList) Add(from, to ItemRef) error {
+	if from == to {
+		return errors.New("an item can't link to itself")
+	}
+	title, _ := ll.Title(to)
+	defer ll.history.track(describe("Link", "to", title))()
+
+	for _, link := range ll.Links {
+		if link.From == from && link.To == to {
+			return errors.New("these items are already linked")
+		}
+	}
+
+	return ll.insert(&Link{From: from, To: to, CreatedAt: time.Now()})
+}
+
+func (ll *LinkList) insert(link *Link) error {
+	id, err := ll.store.InsertLink(link)
+	if err != nil {
+		return err
+	}
+	link.ID = id
+	ll.Links = append(ll.Links, link)
+	return nil
+}
+
+// Remove - Hapus link dari store DAN memory. Wiki links follow the note's
+// text and can only go by editing it.
+func (ll *LinkList) Remove(id int) error {
+	if link := ll.get(id); link != nil {
+		if link.Wiki {
+			return errors.New("remove the [[link]] from the note to unlink it")
+		}
+		title, _ := ll.Title(link.To)
+		defer ll.history.track(describe("Unlink", "from", title))()
+	}
+	return ll.remove(id)
+}
+
+// copyLinks links to as from is linked, for an item split off from another
+// like a changed occurrence of an event. Wiki links only join notes, so
+// there are none to copy.
+func (ll *LinkList) copyLinks(from, to ItemRef) error {
+	if ll == nil {
+		return nil
+	}
+	for _, link := range append([]*Link{}, ll.Links...) {
+		var copied *Link
+		switch {
+		case link.From == from:
+			copied = &Link{From: to, To: link.To, CreatedAt: time.Now()}
+		case link.To == from:
+			copied = &Link{From: link.From, To: to, CreatedAt: time.Now()}
+		default:
+			continue
+		}
+		if err := ll.insert(copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ll *LinkList) get(id int) *Link {
+	for _, link := range ll.Links {
+		if link.ID == id {
+			return link
+		}
+	}
+	return nil
+}
+
+func (ll *LinkList) remove(id int) error {
+	if err := ll.store.DeleteLink(id); err != nil {
+		return err
+	}
+	for i, link := range ll.Links {
+		if link.ID == id {
+			ll.Links = append(ll.Links[:i], ll.Links[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Outgoing returns the links from ref to items that still exist, oldest first
+func (ll *LinkList) Outgoing(ref ItemRef) []*Link {
+	links := []*Link{}
+	for _, link := range ll.Links {
+		if link.From == ref && ll.Exists(link.To) {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// Incoming returns the links to ref from items that still exist, oldest first
+func (ll *LinkList) Incoming(ref ItemRef) []*Link {
+	links := []*Link{}
+	for _, link := range ll.Links {
+		if link.To == ref && ll.Exists(link.From) {
+			links = append(links, link)
+		}
+	}
+	return links
+}
+
+// Exists reports whether ref is a live item. Links to trashed items are
+// kept, so they come back when the item is restored.
+func (ll *LinkList) Exists(ref ItemRef) bool {
+	_, ok := ll.Title(ref)
+	return ok
+}
+
+// Title returns the title of a live item
+func (ll *LinkList) Title(ref ItemRef) (string, bool) {
+	switch ref.Page {
+	case PageTodos:
+		if todo := ll.todos.Get(ref.ID); todo != nil {
+			return todo.Title, true
+		}
+	case PageNotes:
+		if note := ll.notes.Get(ref.ID); note != nil {
+			return note.Title, true
+		}
+	case PageCalendar:
+		if event := ll.events.Get(ref.ID); event != nil {
+			return event.Title, true
+		}
+	}
+	return "", false
+}
+
+// syncWiki makes the wiki links from note from point at targets, note ids
+// in the order they appear in its text
+func (ll *LinkList) syncWiki(from int, targets []int) error {
+	source := ItemRef{Page: PageNotes, ID: from}
+	wanted := map[ItemRef]bool{}
+	for _, id := range targets {
+		wanted[ItemRef{Page: PageNotes, ID: id}] = true
+	}
+
+	// Drop the ones the text no longer has
+	for _, link := range append([]*Link{}, ll.Links...) {
+		if link.From != source {
+			continue
+		}
+		if link.Wiki && !wanted[link.To] {
+			if err := ll.remove(link.ID); err != nil {
+				return err
+			}
+		}
+		// Already linked, by hand or by an earlier save
+		delete(wanted, link.To)
+	}
+
+	for _, id := range targets {
+		to := ItemRef{Page: PageNotes, ID: id}
+		if !wanted[to] || to == source {
+			continue
+		}
+		delete(wanted, to)
+		if err := ll.insert(&Link{From: source, To: to, Wiki: true, CreatedAt: time.Now()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncAllWiki re-indexes the wiki links of every note, linking only to
+// notes that exist (stubs are made when a note is saved)
+func (ll *LinkList) syncAllWiki() error {
+	for _, note := range append(append([]*Note{}, ll.notes.Notes...), ll.notes.Deleted...) {
+		targets := []int{}
+		for _, title := range WikiTargets(note.Content) {
+			if target := ll.notes.FindByTitle(title); target != nil {
+				targets = append(targets, target.ID)
+			} else if target := ll.trashedWikiTarget(note.ID, title); target != nil {
+				targets = append(targets, target.ID)
+			}
+		}
+		if err := ll.syncWiki(note.ID, targets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trashedWikiTarget returns the trashed note titled title that from already
+// links to. Links to a note in the trash stay until it is deleted for good.
+func (ll *LinkList) trashedWikiTarget(from int, title string) *Note {
+	for _, link := range ll.Links {
+		if !link.Wiki || link.From != (ItemRef{Page: PageNotes, ID: from}) {
+			continue
+		}
+		for _, note := range ll.notes.Deleted {
+			if note.ID == link.To.ID && strings.EqualFold(strings.TrimSpace(note.Title), strings.TrimSpace(title)) {
+				return note
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,697 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MemoryTodoStore is a TodoStore that never touches disk. Items are copied
+// on the way in and out so callers can't mutate the store behind its back.
+type MemoryTodoStore struct {
+	todos       map[int]Todo
+	nextID      int
+	completions []TodoCompletion
+}
+
+func NewMemoryTodoStore(todos ...*Todo) *MemoryTodoStore {
+	s := &MemoryTodoStore{todos: map[int]Todo{}, nextID: 1}
+	for _, todo := range todos {
+		s.todos[todo.ID] = *todo
+		if todo.ID >= s.nextID {
+			s.nextID = todo.ID + 1
+		}
+	}
+	return s
+}
+
+func (s *MemoryTodoStore) LoadTodos() ([]*Todo, error) {
+	todos := make([]*Todo, 0, len(s.todos))
+	for _, todo := range s.todos {
+		if !todo.DeletedAt.IsZero() {
+			continue
+		}
+		todo.Tags = cloneTags(todo.Tags)
+		todos = append(todos, &todo)
+	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
+	return todos, nil
+}
+
+func (s *MemoryTodoStore) LoadDeletedTodos() ([]*Todo, error) {
+	todos := []*Todo{}
+	for _, todo := range s.todos {
+		if todo.DeletedAt.IsZero() {
+			continue
+		}
+		todo.Tags = cloneTags(todo.Tags)
+		todos = append(todos, &todo)
+	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].DeletedAt.After(todos[j].DeletedAt) })
+	return todos, nil
+}
+
+func (s *MemoryTodoStore) InsertTodo(todo *Todo) (int, error) {
+	id := s.nextID
+	s.nextID++
+	stored := *todo
+	stored.ID = id
+	stored.Tags = cloneTags(todo.Tags)
+	s.todos[id] = stored
+	return id, nil
+}
+
+func (s *MemoryTodoStore) UpdateTodo(todo *Todo) error {
+	if _, ok := s.todos[todo.ID]; !ok {
+		return fmt.Errorf("todo with id %d not found", todo.ID)
+	}
+	stored := *todo
+	stored.Tags = cloneTags(todo.Tags)
+	s.todos[todo.ID] = stored
+	return nil
+}
+
+func (s *MemoryTodoStore) DeleteTodo(id int) error {
+	now := time.Now()
+	for todoID, todo := range s.todos {
+		if (todoID == id || todo.ParentID == id) && todo.DeletedAt.IsZero() {
+			todo.DeletedAt = now
+			s.todos[todoID] = todo
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTodoStore) RestoreTodo(id int) error {
+	todo, ok := s.todos[id]
+	if !ok {
+		return fmt.Errorf("todo with id %d not found", id)
+	}
+	parent, trashedParent := s.todos[todo.ParentID]
+	trashedParent = trashedParent && !parent.DeletedAt.IsZero()
+	for todoID, other := range s.todos {
+		if other.trashedWith(&todo) || trashedParent && other.trashedWith(&parent) {
+			other.DeletedAt = time.Time{}
+			s.todos[todoID] = other
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTodoStore) PurgeTodo(id int) error {
+	delete(s.todos, id)
+	for i := range s.completions {
+		if s.completions[i].TodoID == id {
+			s.completions[i].TodoID = 0
+		}
+	}
+	// Subtasks go with their parent, like ON DELETE CASCADE
+	for childID, todo := range s.todos {
+		if todo.ParentID == id {
+			s.PurgeTodo(childID)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTodoStore) PutTodo(todo *Todo) error {
+	stored := *todo
+	stored.Tags = cloneTags(todo.Tags)
+	s.todos[todo.ID] = stored
+	if todo.ID >= s.nextID {
+		s.nextID = todo.ID + 1
+	}
+	return nil
+}
+
+func (s *MemoryTodoStore) SearchTodos(query string) ([]SearchHit, error) {
+	todos, _ := s.LoadTodos()
+	hits := []SearchHit{}
+	for _, todo := range todos {
+		if hit, ok := MatchSearch(ParseSearch(query), todo.Title, todo.Description); ok {
+			hit.ID = todo.ID
+			hits = append(hits, hit)
+		}
+	}
+	SortHits(hits)
+	return hits, nil
+}
+
+func (s *MemoryTodoStore) ToggleTodo(toggle *TodoToggle) error {
+	for _, todo := range toggle.Todos {
+		if _, ok := s.todos[todo.ID]; !ok {
+			return fmt.Errorf("todo with id %d not found", todo.ID)
+		}
+	}
+
+	for _, todo := range toggle.Todos {
+		s.UpdateTodo(todo)
+	}
+	for _, completion := range toggle.Completions {
+		s.RecordCompletion(completion)
+	}
+	for _, id := range toggle.Reopened {
+		s.RemoveCompletion(id)
+	}
+	for _, id := range toggle.Purged {
+		s.PurgeTodo(id)
+	}
+	for _, spawn := range toggle.Spawned {
+		spawn.Todo.ID, _ = s.InsertTodo(spawn.Todo)
+		for _, subtask := range spawn.Subtasks {
+			subtask.ParentID = spawn.Todo.ID
+			subtask.ID, _ = s.InsertTodo(subtask)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryTodoStore) LoadCompletions() ([]TodoCompletion, error) {
+	completions := make([]TodoCompletion, len(s.completions))
+	for i, completion := range s.completions {
+		completions[len(s.completions)-1-i] = completion
+	}
+	return completions, nil
+}
+
+func (s *MemoryTodoStore) RecordCompletion(completion TodoCompletion) error {
+	kept := s.completions[:0]
+	for _, c := range s.completions {
+		if c.TodoID != 0 || c.SeriesID != completion.SeriesID || !c.CompletedAt.Equal(completion.CompletedAt) {
+			kept = append(kept, c)
+		}
+	}
+	s.completions = append(kept, completion)
+	return nil
+}
+
+func (s *MemoryTodoStore) RemoveCompletion(todoID int) error {
+	kept := s.completions[:0]
+	for _, completion := range s.completions {
+		if completion.TodoID != todoID {
+			kept = append(kept, completion)
+		}
+	}
+	s.completions = kept
+	return nil
+}
+
+// MemoryNoteStore is a NoteStore that never touches disk
+type MemoryNoteStore struct {
+	notes          map[int]Note
+	nextID         int
+	revisions      []NoteRevision // oldest first
+	nextRevisionID int
+}
+
+func NewMemoryNoteStore(notes ...*Note) *MemoryNoteStore {
+	s := &MemoryNoteStore{notes: map[int]Note{}, nextID: 1, nextRevisionID: 1}
+	for _, note := range notes {
+		s.notes[note.ID] = *note
+		if note.ID >= s.nextID {
+			s.nextID = note.ID + 1
+		}
+	}
+	return s
+}
+
+func (s *MemoryNoteStore) LoadNotes() ([]*Note, error) {
+	notes := make([]*Note, 0, len(s.notes))
+	for _, note := range s.notes {
+		if !note.DeletedAt.IsZero() {
+			continue
+		}
+		note.Tags = cloneTags(note.Tags)
+		notes = append(notes, &note)
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].ID < notes[j].ID })
+	return notes, nil
+}
+
+func (s *MemoryNoteStore) LoadDeletedNotes() ([]*Note, error) {
+	notes := []*Note{}
+	for _, note := range s.notes {
+		if note.DeletedAt.IsZero() {
+			continue
+		}
+		note.Tags = cloneTags(note.Tags)
+		notes = append(notes, &note)
+	}
+	sort.Slice(notes, func(i, j int) bool { return notes[i].DeletedAt.After(notes[j].DeletedAt) })
+	return notes, nil
+}
+
+func (s *MemoryNoteStore) InsertNote(note *Note) (int, error) {
+	id := s.nextID
+	s.nextID++
+	stored := *note
+	stored.ID = id
+	stored.Tags = cloneTags(note.Tags)
+	s.notes[id] = stored
+	return id, nil
+}
+
+func (s *MemoryNoteStore) UpdateNote(note *Note) error {
+	if _, ok := s.notes[note.ID]; !ok {
+		return fmt.Errorf("note with id %d not found", note.ID)
+	}
+	stored := *note
+	stored.Tags = cloneTags(note.Tags)
+	s.notes[note.ID] = stored
+	return nil
+}
+
+func (s *MemoryNoteStore) DeleteNote(id int) error {
+	return s.setNoteDeleted(id, time.Now())
+}
+
+func (s *MemoryNoteStore) RestoreNote(id int) error {
+	return s.setNoteDeleted(id, time.Time{})
+}
+
+func (s *MemoryNoteStore) setNoteDeleted(id int, deletedAt time.Time) error {
+	note, ok := s.notes[id]
+	if !ok {
+		return fmt.Errorf("note with id %d not found", id)
+	}
+	note.DeletedAt = deletedAt
+	s.notes[id] = note
+	return nil
+}
+
+func (s *MemoryNoteStore) PurgeNote(id int) error {
+	delete(s.notes, id)
+	kept := s.revisions[:0]
+	for _, revision := range s.revisions {
+		if revision.NoteID != id {
+			kept = append(kept, revision)
+		}
+	}
+	s.revisions = kept
+	return nil
+}
+
+func (s *MemoryNoteStore) PutNote(note *Note) error {
+	stored := *note
+	stored.Tags = cloneTags(note.Tags)
+	s.notes[note.ID] = stored
+	if note.ID >= s.nextID {
+		s.nextID = note.ID + 1
+	}
+	return nil
+}
+
+func (s *MemoryNoteStore) SearchNotes(query string) ([]SearchHit, error) {
+	notes, _ := s.LoadNotes()
+	hits := []SearchHit{}
+	for _, note := range notes {
+		if hit, ok := MatchSearch(ParseSearch(query), note.Title, note.Content); ok {
+			hit.ID = note.ID
+			hits = append(hits, hit)
+		}
+	}
+	SortHits(hits)
+	return hits, nil
+}
+
+func (s *MemoryNoteStore) LoadRevisions(noteID int) ([]NoteRevision, error) {
+	revisions := []NoteRevision{}
+	for i := len(s.revisions) - 1; i >= 0; i-- {
+		if s.revisions[i].NoteID == noteID {
+			revisions = append(revisions, s.revisions[i])
+		}
+	}
+	return revisions, nil
+}
+
+// ReviseNote writes the note and then its revisions. Nothing here can fail
+// half way, so there is no transaction to keep them together.
+func (s *MemoryNoteStore) ReviseNote(note *Note, revisions []NoteRevision) error {
+	if err := s.UpdateNote(note); err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		revision.ID = s.nextRevisionID
+		s.nextRevisionID++
+		s.revisions = append(s.revisions, revision)
+	}
+
+	// Drop the oldest past RevisionLimit
+	kept := 0
+	for i := len(s.revisions) - 1; i >= 0; i-- {
+		if s.revisions[i].NoteID != note.ID {
+			continue
+		}
+		kept++
+		if kept > RevisionLimit {
+			s.revisions = append(s.revisions[:i], s.revisions[i+1:]...)
+		}
+	}
+	return nil
+}
+
+// MemoryEventStore is an EventStore that never touches disk
+type MemoryEventStore struct {
+	events map[int]Event
+	nextID int
+}
+
+func NewMemoryEventStore(events ...*Event) *MemoryEventStore {
+	s := &MemoryEventStore{events: map[int]Event{}, nextID: 1}
+	for _, event := range events {
+		s.events[event.ID] = *event
+		if event.ID >= s.nextID {
+			s.nextID = event.ID + 1
+		}
+	}
+	return s
+}
+
+func (s *MemoryEventStore) LoadEvents() ([]*Event, error) {
+	events := make([]*Event, 0, len(s.events))
+	for _, event := range s.events {
+		if !event.DeletedAt.IsZero() {
+			continue
+		}
+		event.Tags = cloneTags(event.Tags)
+		event.Exceptions = cloneTimes(event.Exceptions)
+		events = append(events, &event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].StartTime.Before(events[j].StartTime) })
+	return events, nil
+}
+
+func (s *MemoryEventStore) LoadDeletedEvents() ([]*Event, error) {
+	events := []*Event{}
+	for _, event := range s.events {
+		if event.DeletedAt.IsZero() {
+			continue
+		}
+		event.Tags = cloneTags(event.Tags)
+		event.Exceptions = cloneTimes(event.Exceptions)
+		events = append(events, &event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].DeletedAt.After(events[j].DeletedAt) })
+	return events, nil
+}
+
+func (s *MemoryEventStore) InsertEvent(event *Event) (int, error) {
+	id := s.nextID
+	s.nextID++
+	stored := *event
+	stored.ID = id
+	stored.Tags = cloneTags(event.Tags)
+	stored.Exceptions = cloneTimes(event.Exceptions)
+	s.events[id] = stored
+	return id, nil
+}
+
+func (s *MemoryEventStore) UpdateEvent(event *Event) error {
+	if _, ok := s.events[event.ID]; !ok {
+		return fmt.Errorf("event with id %d not found", event.ID)
+	}
+	stored := *event
+	stored.Tags = cloneTags(event.Tags)
+	stored.Exceptions = cloneTimes(event.Exceptions)
+	s.events[event.ID] = stored
+	return nil
+}
+
+func (s *MemoryEventStore) DeleteEvent(id int) error {
+	return s.setEventDeleted(id, time.Now())
+}
+
+func (s *MemoryEventStore) RestoreEvent(id int) error {
+	return s.setEventDeleted(id, time.Time{})
+}
+
+func (s *MemoryEventStore) setEventDeleted(id int, deletedAt time.Time) error {
+	event, ok := s.events[id]
+	if !ok {
+		return fmt.Errorf("event with id %d not found", id)
+	}
+	event.DeletedAt = deletedAt
+	s.events[id] = event
+	return nil
+}
+
+func (s *MemoryEventStore) PurgeEvent(id int) error {
+	delete(s.events, id)
+	return nil
+}
+
+func (s *MemoryEventStore) PutEvent(event *Event) error {
+	stored := *event
+	stored.Tags = cloneTags(event.Tags)
+	stored.Exceptions = cloneTimes(event.Exceptions)
+	s.events[event.ID] = stored
+	if event.ID >= s.nextID {
+		s.nextID = event.ID + 1
+	}
+	return nil
+}
+
+func (s *MemoryEventStore) SearchEvents(query string) ([]SearchHit, error) {
+	events, _ := s.LoadEvents()
+	hits := []SearchHit{}
+	for _, event := range events {
+		if hit, ok := MatchSearch(ParseSearch(query), event.Title, event.Content, event.Location); ok {
+			hit.ID = event.ID
+			hits = append(hits, hit)
+		}
+	}
+	SortHits(hits)
+	return hits, nil
+}
+
+// MemoryHistoryStore is a HistoryStore that never touches disk
+type MemoryHistoryStore struct {
+	commands []*Command
+	nextID   int
+}
+
+func NewMemoryHistoryStore() *MemoryHistoryStore {
+	return &MemoryHistoryStore{nextID: 1}
+}
+
+func (s *MemoryHistoryStore) LoadCommands() ([]*Command, error) {
+	commands := make([]*Command, len(s.commands))
+	for i, cmd := range s.commands {
+		stored := *cmd
+		commands[i] = &stored
+	}
+	return commands, nil
+}
+
+func (s *MemoryHistoryStore) InsertCommand(cmd *Command) (int, error) {
+	stored := *cmd
+	stored.ID = s.nextID
+	s.nextID++
+	s.commands = append(s.commands, &stored)
+	return stored.ID, nil
+}
+
+func (s *MemoryHistoryStore) SetCommandUndone(id int, undone bool) error {
+	for _, cmd := range s.commands {
+		if cmd.ID == id {
+			cmd.Undone = undone
+		}
+	}
+	return nil
+}
+
+func (s *MemoryHistoryStore) DeleteCommands(ids []int) error {
+	drop := map[int]bool{}
+	for _, id := range ids {
+		drop[id] = true
+	}
+	kept := s.commands[:0]
+	for _, cmd := range s.commands {
+		if !drop[cmd.ID] {
+			kept = append(kept, cmd)
+		}
+	}
+	s.commands = kept
+	return nil
+}
+
+// MemoryViewStore is a ViewStore that never touches disk
+type MemoryViewStore struct {
+	views  []SavedView
+	nextID int
+}
+
+func NewMemoryViewStore() *MemoryViewStore {
+	return &MemoryViewStore{nextID: 1}
+}
+
+func (s *MemoryViewStore) LoadViews() ([]*SavedView, error) {
+	views := make([]*SavedView, len(s.views))
+	for i, view := range s.views {
+		views[i] = &view
+	}
+	return views, nil
+}
+
+func (s *MemoryViewStore) InsertView(view *SavedView) (int, error) {
+	stored := *view
+	stored.ID = s.nextID
+	s.nextID++
+	s.views = append(s.views, stored)
+	return stored.ID, nil
+}
+
+func (s *MemoryViewStore) UpdateView(view *SavedView) error {
+	for i := range s.views {
+		if s.views[i].ID == view.ID {
+			s.views[i] = *view
+			return nil
+		}
+	}
+	return fmt.Errorf("saved view with id %d not found", view.ID)
+}
+
+func (s *MemoryViewStore) PutView(view *SavedView) error {
+	if err := s.UpdateView(view); err == nil {
+		return nil
+	}
+	s.views = append(s.views, *view)
+	sort.Slice(s.views, func(i, j int) bool { return s.views[i].ID < s.views[j].ID })
+	if view.ID >= s.nextID {
+		s.nextID = view.ID + 1
+	}
+	return nil
+}
+
+func (s *MemoryViewStore) DeleteView(id int) error {
+	for i := range s.views {
+		if s.views[i].ID == id {
+			s.views = append(s.views[:i], s.views[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// MemoryNotebookStore is a NotebookStore that never touches disk
+type MemoryNotebookStore struct {
+	notebooks []Notebook
+	nextID    int
+}
+
+func NewMemoryNotebookStore() *MemoryNotebookStore {
+	return &MemoryNotebookStore{nextID: 1}
+}
+
+func (s *MemoryNotebookStore) LoadNotebooks() ([]*Notebook, error) {
+	notebooks := make([]*Notebook, len(s.notebooks))
+	for i, notebook := range s.notebooks {
+		notebooks[i] = &notebook
+	}
+	return notebooks, nil
+}
+
+func (s *MemoryNotebookStore) InsertNotebook(notebook *Notebook) (int, error) {
+	stored := *notebook
+	stored.ID = s.nextID
+	s.nextID++
+	s.notebooks = append(s.notebooks, stored)
+	return stored.ID, nil
+}
+
+func (s *MemoryNotebookStore) UpdateNotebook(notebook *Notebook) error {
+	for i := range s.notebooks {
+		if s.notebooks[i].ID == notebook.ID {
+			s.notebooks[i] = *notebook
+			return nil
+		}
+	}
+	return fmt.Errorf("notebook with id %d not found", notebook.ID)
+}
+
+func (s *MemoryNotebookStore) PutNotebook(notebook *Notebook) error {
+	if err := s.UpdateNotebook(notebook); err == nil {
+		return nil
+	}
+	s.notebooks = append(s.notebooks, *notebook)
+	sort.Slice(s.notebooks, func(i, j int) bool { return s.notebooks[i].ID < s.notebooks[j].ID })
+	if notebook.ID >= s.nextID {
+		s.nextID = notebook.ID + 1
+	}
+	return nil
+}
+
+func (s *MemoryNotebookStore) DeleteNotebook(id int) error {
+	for i := range s.notebooks {
+		if s.notebooks[i].ID == id {
+			s.notebooks = append(s.notebooks[:i], s.notebooks[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// MemoryLinkStore is a LinkStore that never touches disk
+type MemoryLinkStore struct {
+	links  []Link
+	nextID int
+}
+
+func NewMemoryLinkStore() *MemoryLinkStore {
+	return &MemoryLinkStore{nextID: 1}
+}
+
+func (s *MemoryLinkStore) LoadLinks() ([]*Link, error) {
+	links := make([]*Link, len(s.links))
+	for i, link := range s.links {
+		links[i] = &link
+	}
+	return links, nil
+}
+
+func (s *MemoryLinkStore) InsertLink(link *Link) (int, error) {
+	stored := *link
+	stored.ID = s.nextID
+	s.nextID++
+	s.links = append(s.links, stored)
+	return stored.ID, nil
+}
+
+func (s *MemoryLinkStore) PutLink(link *Link) error {
+	for i := range s.links {
+		if s.links[i].ID == link.ID {
+			s.links[i] = *link
+			return nil
+		}
+	}
+	s.links = append(s.links, *link)
+	sort.Slice(s.links, func(i, j int) bool { return s.links[i].ID < s.links[j].ID })
+	if link.ID >= s.nextID {
+		s.nextID = link.ID + 1
+	}
+	return nil
+}
+
+func (s *MemoryLinkStore) DeleteLink(id int) error {
+	for i := range s.links {
+		if s.links[i].ID == id {
+			s.links = append(s.links[:i], s.links[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func cloneTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	return append([]string{}, tags...)
+}
+
+func cloneTimes(times []time.Time) []time.Time {
+	if times == nil {
+		return nil
+	}
+	return append([]time.Time{}, times...)
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoresCopy(t *testing.T) {
+	todos := NewMemoryTodoStore()
+	todo := &Todo{Title: "Buy milk", Tags: []string{"errand"}}
+	id, err := todos.InsertTodo(todo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	todo.Title = "changed"
+	todo.Tags[0] = "changed"
+	loaded, _ := todos.LoadTodos()
+	loaded[0].Tags[0] = "changed too"
+	if loaded, _ = todos.LoadTodos(); loaded[0].ID != id || loaded[0].Title != "Buy milk" || loaded[0].Tags[0] != "errand" {
+		t.Errorf("todo store = %+v, changed through the caller's copy", *loaded[0])
+	}
+
+	notes := NewMemoryNoteStore()
+	note := &Note{Title: "Plants", Tags: []string{"garden"}}
+	if _, err := notes.InsertNote(note); err != nil {
+		t.Fatal(err)
+	}
+	note.Tags[0] = "changed"
+	if loaded, _ := notes.LoadNotes(); loaded[0].Tags[0] != "garden" {
+		t.Errorf("note store tags = %v, changed through the caller's copy", loaded[0].Tags)
+	}
+
+	events := NewMemoryEventStore()
+	skipped := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	event := &Event{Title: "Standup", Exceptions: []time.Time{skipped}}
+	if _, err := events.InsertEvent(event); err != nil {
+		t.Fatal(err)
+	}
+	event.Exceptions[0] = time.Time{}
+	if loaded, _ := events.LoadEvents(); !loaded[0].Exceptions[0].Equal(skipped) {
+		t.Errorf("event store exceptions = %v, changed through the caller's copy", loaded[0].Exceptions)
+	}
+}
+
+func TestMemoryStoreIDs(t *testing.T) {
+	// Seeded items keep their ids, new ones come after
+	s := NewMemoryTodoStore(&Todo{ID: 5, Title: "Seeded"})
+	if id, _ := s.InsertTodo(&Todo{Title: "New"}); id != 6 {
+		t.Errorf("first insert after a seeded id 5 got id %d, want 6", id)
+	}
+
+	// Undo puts items back under their own id, and later inserts skip it
+	if err := s.PutTodo(&Todo{ID: 10, Title: "Put back"}); err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := s.InsertTodo(&Todo{Title: "After"}); id != 11 {
+		t.Errorf("insert after putting id 10 got id %d, want 11", id)
+	}
+
+	if err := s.UpdateTodo(&Todo{ID: 99}); err == nil {
+		t.Error("updating a todo that isn't stored didn't fail")
+	}
+}
+
+func TestMemoryTodoStoreTrash(t *testing.T) {
+	s := NewMemoryTodoStore()
+	parent, _ := s.InsertTodo(&Todo{Title: "Pack"})
+	subtask, _ := s.InsertTodo(&Todo{Title: "Passport", ParentID: parent})
+	other, _ := s.InsertTodo(&Todo{Title: "Other"})
+	if err := s.RecordCompletion(TodoCompletion{SeriesID: parent, TodoID: parent, CompletedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.DeleteTodo(other); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteTodo(parent); err != nil {
+		t.Fatal(err)
+	}
+	deleted, _ := s.LoadDeletedTodos()
+	if len(deleted) != 3 || deleted[2].ID != other {
+		t.Fatalf("got %d trashed todos, want 3 with the first deleted last", len(deleted))
+	}
+	if live, _ := s.LoadTodos(); len(live) != 0 {
+		t.Errorf("got %d live todos, want the subtask trashed with its parent", len(live))
+	}
+
+	if err := s.RestoreTodo(subtask); err != nil {
+		t.Fatal(err)
+	}
+	if live, _ := s.LoadTodos(); len(live) != 2 {
+		t.Errorf("restoring the subtask brought back %d todos, want it and its parent", len(live))
+	}
+
+	if err := s.DeleteTodo(parent); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PurgeTodo(parent); err != nil {
+		t.Fatal(err)
+	}
+	if deleted, _ := s.LoadDeletedTodos(); len(deleted) != 1 || deleted[0].ID != other {
+		t.Errorf("purging the parent left %d todos in the trash, want only %q", len(deleted), "Other")
+	}
+	completions, _ := s.LoadCompletions()
+	if len(completions) != 1 || completions[0].TodoID != 0 {
+		t.Errorf("completions = %+v, want the one kept without its todo", completions)
+	}
+}
+
+func TestMemoryNoteStoreSearch(t *testing.T) {
+	s := NewMemoryNoteStore()
+	kept, _ := s.InsertNote(&Note{Title: "Ferns", Content: "like shade"})
+	trashed, _ := s.InsertNote(&Note{Title: "Cactus", Content: "likes sun, not shade"})
+	if err := s.DeleteNote(trashed); err != nil {
+		t.Fatal(err)
+	}
+
+	hits, err := s.SearchNotes("shade")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].ID != kept {
+		t.Errorf("search hits = %+v, want only the live note %d", hits, kept)
+	}
+}
+
+func TestMemoryHistoryStore(t *testing.T) {
+	s := NewMemoryHistoryStore()
+	first, _ := s.InsertCommand(&Command{Label: "first"})
+	second, _ := s.InsertCommand(&Command{Label: "second"})
+	third, _ := s.InsertCommand(&Command{Label: "third"})
+	if first != 1 || second != 2 || third != 3 {
+		t.Fatalf("got ids %d, %d, %d, want 1, 2, 3", first, second, third)
+	}
+
+	if err := s.SetCommandUndone(third, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteCommands([]int{first}); err != nil {
+		t.Fatal(err)
+	}
+
+	commands, _ := s.LoadCommands()
+	if len(commands) != 2 || commands[0].ID != second || commands[1].ID != third || !commands[1].Undone {
+		t.Fatalf("commands = %+v, want second, then third undone", commands)
+	}
+	commands[0].Label = "changed"
+	if commands, _ = s.LoadCommands(); commands[0].Label != "second" {
+		t.Error("history store changed through a loaded command")
+	}
+	if id, _ := s.InsertCommand(&Command{Label: "fourth"}); id != 4 {
+		t.Errorf("got id %d after deleting, want 4: ids are never reused", id)
+	}
+}
@@ -8,6 +8,7 @@ const (
 	PageTodos                     // 1
 	PageNotes                     // 2
 	PageCalendar                  // 3
+	PageTrash                     // 4
 )
 
 // String makes PageType printable for debugging
@@ -21,6 +22,8 @@ func (p PageType) String() string {
 		return "Notes"
 	case PageCalendar:
 		return "Calendar"
+	case PageTrash:
+		return "Trash"
 	default:
 		return "Unknown"
 	}
@@ -41,6 +44,7 @@ func GetPages() []PageInfo {
 		{Type: PageTodos, Title: "To-Dos", Key: "2", Icon: "✓"},
 		{Type: PageNotes, Title: "Notes", Key: "3", Icon: "📝"},
 		{Type: PageCalendar, Title: "Calendar", Key: "4", Icon: "📅"},
+		{Type: PageTrash, Title: "Trash", Key: "5", Icon: "🗑️"},
 	}
 }
 
@@ -59,3 +63,7 @@ func PageTypeNotes() PageType {
 func PageTypeCalendar() PageType {
 	return PageCalendar
 }
+
+func PageTypeTrash() PageType {
+	return PageTrash
+}
@@ -1,8 +1,8 @@
 package models
 
 import (
-	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -11,54 +11,60 @@ type Note struct {
 	Title     string
 	Content   string
 	CreatedAt time.Time
+	Tags      []string
+	DeletedAt time.Time // kapan dibuang ke trash, zero untuk note aktif
+
+	// JournalDate is the day (JournalDateFormat) a daily journal note is
+	// for, empty for other notes
+	JournalDate string
+
+	NotebookID int  // notebook the note is filed in, 0 for none
+	Pinned     bool // stays at the top of the list and on the dashboard
+}
+
+// JournalDateFormat is how journal notes are keyed by day
+const JournalDateFormat = "2006-01-02"
+
+// IsJournal reports whether the note is the daily journal of some day
+func (n *Note) IsJournal() bool {
+	return n.JournalDate != ""
 }
 
 type NoteList struct {
-	db       *sql.DB
+	store    NoteStore
 	Notes    []*Note
+	Deleted  []*Note // trash, terbaru dulu
 	Selected int
 	NextID   int
+	history  *History  // records mutations for undo, nil if none
+	links    *LinkList // indexes [[wiki links]] on save, nil if none
 }
 
-// Load - Load semua notes dari database ke memory
+// Load - Load semua notes dari store ke memory
 func (nl *NoteList) Load() error {
-	query := "SELECT id, title, content, created_at FROM notes ORDER BY id"
-	rows, err := nl.db.Query(query)
+	notes, err := nl.store.LoadNotes()
 	if err != nil {
-		return fmt.Errorf("failed to query notes: %w", err)
+		return err
 	}
-	defer rows.Close()
-
-	nl.Notes = []*Note{} // Clear existing
-
-	for rows.Next() {
-		var id int
-		var title, content string
-		var createdAt time.Time
-
-		if err := rows.Scan(&id, &title, &content, &createdAt); err != nil {
-			return fmt.Errorf("failed to scan note: %w", err)
-		}
 
-		note := &Note{
-			ID:        id,
-			Title:     title,
-			Content:   content,
-			CreatedAt: createdAt,
-		}
-
-		nl.Notes = append(nl.Notes, note)
+	deleted, err := nl.store.LoadDeletedNotes()
+	if err != nil {
+		return err
+	}
 
+	nl.Notes = notes
+	nl.Deleted = deleted
+	for _, note := range append(notes, deleted...) {
 		// Update NextID
-		if id >= nl.NextID {
-			nl.NextID = id + 1
+		if note.ID >= nl.NextID {
+			nl.NextID = note.ID + 1
 		}
 	}
 
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error iterating notes: %w", err)
+	// Notes may have changed under the wiki link index, e.g. by undo
+	if nl.links != nil {
+		return nl.links.syncAllWiki()
 	}
-
 	return nil
 }
 
@@ -67,8 +73,8 @@ func (nl *NoteList) Load() error {
  */
 
 func NewNoteListSample() *NoteList {
-	return &NoteList{
-		Notes: []*Note{
+	return NewNoteList(NewMemoryNoteStore(
+		[]*Note{
 			{
 				ID: 1, Title: "Meeting Notes",
 				Content:   "Discuss project roadmap and milestones.",
@@ -83,18 +89,18 @@ func NewNoteListSample() *NoteList {
 				Title:   "Ideas",
 				Content: "Start a blog about Go programming.",
 			},
-		},
-	}
+		}...,
+	))
 }
 
-func NewNoteList(db_ *sql.DB) *NoteList {
+func NewNoteList(store NoteStore) *NoteList {
 	nl := &NoteList{
-		db:       db_,
+		store:    store,
 		Notes:    []*Note{},
 		Selected: 0,
 		NextID:   1,
 	}
-	// Auto-load dari database saat inisialisasi
+	// Auto-load dari store saat inisialisasi
 	if err := nl.Load(); err != nil {
 		// Log error tapi tetap return instance kosong
 		fmt.Printf("Warning: failed to load notes: %v\n", err)
@@ -139,68 +145,221 @@ func (nl NoteList) Count() int {
 	return len(nl.Notes)
 }
 
-// Add - Tambah note ke database DAN memory sekaligus
-func (nl *NoteList) Add(title, content string) error {
-	query := `INSERT INTO notes (title, content, created_at) VALUES (?, ?, ?)`
+// Add - Tambah note ke store DAN memory sekaligus
+func (nl *NoteList) Add(title, content string, tags []string) error {
+	_, err := nl.Create(title, content, tags)
+	return err
+}
 
-	now := time.Now()
-	result, err := nl.db.Exec(query, title, content, now)
-	if err != nil {
-		return fmt.Errorf("failed to add note to database: %w", err)
+// Create - Add yang juga return note barunya, e.g. to link it right away
+func (nl *NoteList) Create(title, content string, tags []string) (*Note, error) {
+	return nl.CreateIn(0, title, content, tags)
+}
+
+// CreateIn - Create di dalam notebook, 0 untuk tanpa notebook
+func (nl *NoteList) CreateIn(notebookID int, title, content string, tags []string) (*Note, error) {
+	defer nl.history.track(describe("Add", "note", title))()
+
+	return nl.insert(&Note{
+		Title:      title,
+		Content:    content,
+		CreatedAt:  time.Now(),
+		Tags:       tags,
+		NotebookID: notebookID,
+	})
+}
+
+// CreateJournal - Create the journal note of day
+func (nl *NoteList) CreateJournal(day time.Time, title, content string, tags []string) (*Note, error) {
+	defer nl.history.track(describe("Start", "journal", title))()
+
+	if nl.Journal(day) != nil {
+		return nil, fmt.Errorf("the journal of %s is already written", day.Format(JournalDateFormat))
+	}
+	return nl.insert(&Note{
+		Title:       title,
+		Content:     content,
+		CreatedAt:   time.Now(),
+		Tags:        tags,
+		JournalDate: day.Format(JournalDateFormat),
+	})
+}
+
+// Journal returns the journal note of day, nil when it isn't written yet
+func (nl *NoteList) Journal(day time.Time) *Note {
+	key := day.Format(JournalDateFormat)
+	for _, note := range nl.Notes {
+		if note.JournalDate == key {
+			return note
+		}
 	}
+	return nil
+}
 
-	id, err := result.LastInsertId()
+// AdjacentJournal returns the journal note written closest before (step
+// < 0) or after (step > 0) the day of note, skipping days without one. nil
+// if there is none.
+func (nl *NoteList) AdjacentJournal(note *Note, step int) *Note {
+	var found *Note
+	for _, other := range nl.Notes {
+		if !other.IsJournal() {
+			continue
+		}
+		// The keys sort like the days they stand for
+		if step < 0 && other.JournalDate < note.JournalDate && (found == nil || other.JournalDate > found.JournalDate) {
+			found = other
+		}
+		if step > 0 && other.JournalDate > note.JournalDate && (found == nil || other.JournalDate < found.JournalDate) {
+			found = other
+		}
+	}
+	return found
+}
+
+// insert - Tambah note ke store DAN memory sekaligus
+func (nl *NoteList) insert(note *Note) (*Note, error) {
+	id, err := nl.store.InsertNote(note)
 	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
+		return nil, err
 	}
 
 	// Tambah ke memory
-	note := &Note{
-		ID:        int(id),
-		Title:     title,
-		Content:   content,
-		CreatedAt: now,
-	}
+	note.ID = id
 	nl.Notes = append(nl.Notes, note)
-	nl.NextID = int(id) + 1
+	nl.NextID = id + 1
 
-	return nil
+	return note, nl.linkWiki(note)
 }
 
-// Update - Update note di database DAN memory sekaligus
-func (nl *NoteList) Update(id int, title, content string) error {
-	query := `UPDATE notes SET title=?, content=?, updated_at=CURRENT_TIMESTAMP WHERE id=?`
+// Update - Update note di store DAN memory sekaligus
+func (nl *NoteList) Update(id int, title, content string, tags []string) error {
+	defer nl.history.track(describe("Edit", "note", title))()
 
-	_, err := nl.db.Exec(query, title, content, id)
+	note := nl.find(id)
+	if note == nil {
+		return fmt.Errorf("note with id %d not found", id)
+	}
+
+	updated := *note
+	updated.Title = title
+	updated.Content = content
+	updated.Tags = tags
+
+	revisions, err := nl.newRevisions(note, &updated, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to update note in database: %w", err)
+		return err
+	}
+	if err := nl.store.ReviseNote(&updated, revisions); err != nil {
+		return err
 	}
 
 	// Update di memory
-	for _, note := range nl.Notes {
-		if note.ID == id {
-			note.Title = title
-			note.Content = content
-			break
-		}
+	*note = updated
+
+	return nl.linkWiki(note)
+}
+
+// Move - Pindahkan note ke notebook lain, 0 untuk keluar dari notebook
+func (nl *NoteList) Move(id, notebookID int) error {
+	note := nl.find(id)
+	if note == nil {
+		return fmt.Errorf("note with id %d not found", id)
+	}
+	defer nl.history.track(describe("Move", "note", note.Title))()
+
+	updated := *note
+	updated.NotebookID = notebookID
+	if err := nl.store.UpdateNote(&updated); err != nil {
+		return err
+	}
+	*note = updated
+	return nil
+}
+
+// TogglePinned - Pin atau lepas pin note di store DAN memory sekaligus
+func (nl *NoteList) TogglePinned(id int) error {
+	note := nl.find(id)
+	if note == nil {
+		return fmt.Errorf("note with id %d not found", id)
+	}
+
+	action := "Pin"
+	if note.Pinned {
+		action = "Unpin"
 	}
+	defer nl.history.track(describe(action, "note", note.Title))()
 
+	updated := *note
+	updated.Pinned = !note.Pinned
+	if err := nl.store.UpdateNote(&updated); err != nil {
+		return err
+	}
+	*note = updated
 	return nil
 }
 
-// Remove - Hapus note dari database DAN memory sekaligus
+// linkWiki creates stub notes for the [[links]] of note that point nowhere
+// yet and indexes its wiki links
+func (nl *NoteList) linkWiki(note *Note) error {
+	if nl.links == nil {
+		return nil
+	}
+
+	targets := []int{}
+	for _, title := range WikiTargets(note.Content) {
+		target := nl.FindByTitle(title)
+		if target == nil {
+			if err := nl.Add(title, "", nil); err != nil {
+				return err
+			}
+			target = nl.Notes[len(nl.Notes)-1]
+		}
+		targets = append(targets, target.ID)
+	}
+	return nl.links.syncWiki(note.ID, targets)
+}
+
+// WikiTarget returns the note a [[link]] of from points at, making the stub
+// again when it went away (e.g. the stub was undone)
+func (nl *NoteList) WikiTarget(from *Note, title string) (*Note, error) {
+	if target := nl.FindByTitle(title); target != nil {
+		return target, nil
+	}
+	if err := nl.linkWiki(from); err != nil {
+		return nil, err
+	}
+	if target := nl.FindByTitle(title); target != nil {
+		return target, nil
+	}
+	return nil, fmt.Errorf("note %q not found", title)
+}
+
+// FindByTitle returns the note titled title ignoring case, the oldest one
+// when several are, or nil
+func (nl *NoteList) FindByTitle(title string) *Note {
+	var found *Note
+	for _, note := range nl.Notes {
+		if strings.EqualFold(strings.TrimSpace(note.Title), strings.TrimSpace(title)) && (found == nil || note.ID < found.ID) {
+			found = note
+		}
+	}
+	return found
+}
+
+// Remove - Pindahkan note ke trash di store DAN memory sekaligus
 func (nl *NoteList) Remove(id int) error {
-	query := `DELETE FROM notes WHERE id=?`
+	defer nl.history.track(describe("Delete", "note", nl.titleOf(id)))()
 
-	_, err := nl.db.Exec(query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete note from database: %w", err)
+	if err := nl.store.DeleteNote(id); err != nil {
+		return err
 	}
 
-	// Hapus dari memory
+	// Pindah ke trash di memory
 	for i, note := range nl.Notes {
 		if note.ID == id {
 			nl.Notes = append(nl.Notes[:i], nl.Notes[i+1:]...)
+			note.DeletedAt = time.Now()
+			nl.Deleted = append([]*Note{note}, nl.Deleted...)
 			// Adjust selected index
 			if nl.Selected >= len(nl.Notes) && nl.Selected > 0 {
 				nl.Selected--
@@ -211,3 +370,103 @@ func (nl *NoteList) Remove(id int) error {
 
 	return nil
 }
+
+// Restore - Kembalikan note dari trash di store DAN memory sekaligus
+func (nl *NoteList) Restore(id int) error {
+	defer nl.history.track(describe("Restore", "note", nl.titleOf(id)))()
+
+	if err := nl.store.RestoreNote(id); err != nil {
+		return err
+	}
+
+	for i, note := range nl.Deleted {
+		if note.ID == id {
+			nl.Deleted = append(nl.Deleted[:i], nl.Deleted[i+1:]...)
+			note.DeletedAt = time.Time{}
+			nl.Notes = append(nl.Notes, note)
+			break
+		}
+	}
+
+	return nil
+}
+
+// Purge - Hapus note dari trash untuk selamanya
+func (nl *NoteList) Purge(id int) error {
+	defer nl.history.track(describe("Delete forever", "note", nl.titleOf(id)))()
+
+	if err := nl.store.PurgeNote(id); err != nil {
+		return err
+	}
+
+	for i, note := range nl.Deleted {
+		if note.ID == id {
+			nl.Deleted = append(nl.Deleted[:i], nl.Deleted[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// PurgeTrash - Hapus selamanya semua note yang dibuang sebelum cutoff
+func (nl *NoteList) PurgeTrash(cutoff time.Time) error {
+	for _, note := range append([]*Note{}, nl.Deleted...) {
+		if note.DeletedAt.Before(cutoff) {
+			if err := nl.Purge(note.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AllTags - Semua tag yang dipakai notes (hanya memory)
+func (nl *NoteList) AllTags() []string {
+	lists := make([][]string, len(nl.Notes))
+	for i, note := range nl.Notes {
+		lists[i] = note.Tags
+	}
+	return collectTags(lists...)
+}
+
+// Pinned - Note yang di-pin, urut id (hanya memory)
+func (nl *NoteList) Pinned() []*Note {
+	var pinned []*Note
+	for _, note := range nl.Notes {
+		if note.Pinned {
+			pinned = append(pinned, note)
+		}
+	}
+	return pinned
+}
+
+// Search - Cari note aktif lewat store (full-text), hasil terbaik dulu
+func (nl *NoteList) Search(query string) ([]SearchHit, error) {
+	return nl.store.SearchNotes(query)
+}
+
+// titleOf returns the title of a note, trashed or not
+func (nl *NoteList) titleOf(id int) string {
+	for _, note := range append(append([]*Note{}, nl.Notes...), nl.Deleted...) {
+		if note.ID == id {
+			return note.Title
+		}
+	}
+	return ""
+}
+
+// Get returns the note with the given id, or nil
+func (nl *NoteList) Get(id int) *Note {
+	return nl.find(id)
+}
+
+// find returns the in-memory note with the given id, or nil
+func (nl *NoteList) find(id int) *Note {
+	for _, note := range nl.Notes {
+		if note.ID == id {
+			return note
+		}
+	}
+	return nil
+}
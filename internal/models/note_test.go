@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrashNote(t *testing.T) {
+	store := NewMemoryNoteStore()
+	nl := NewNoteList(store)
+	old, err := nl.Create("Old", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recent, err := nl.Create("Recent", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := nl.Remove(old.ID); err != nil {
+		t.Fatal(err)
+	}
+	if nl.Get(old.ID) != nil || len(nl.Deleted) != 1 || old.DeletedAt.IsZero() {
+		t.Fatalf("the deleted note isn't in the trash")
+	}
+	if err := nl.Restore(old.ID); err != nil {
+		t.Fatal(err)
+	}
+	if nl.Get(old.ID) == nil || len(nl.Deleted) != 0 || !old.DeletedAt.IsZero() {
+		t.Fatalf("the restored note isn't back")
+	}
+
+	if err := nl.Remove(old.ID); err != nil {
+		t.Fatal(err)
+	}
+	cutoff := time.Now()
+	if err := nl.Remove(recent.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := nl.PurgeTrash(cutoff); err != nil {
+		t.Fatal(err)
+	}
+	if len(nl.Deleted) != 1 || nl.Deleted[0].ID != recent.ID {
+		t.Errorf("trash = %+v, want only the note deleted after the cutoff", nl.Deleted)
+	}
+
+	reloaded := NewNoteList(store)
+	if len(reloaded.Notes) != 0 || len(reloaded.Deleted) != 1 {
+		t.Errorf("reloaded %d live and %d trashed notes, want 0 and 1", len(reloaded.Notes), len(reloaded.Deleted))
+	}
+}
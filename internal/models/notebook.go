@@ -0,0 +1,222 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Notebook is a folder for notes. Notebooks nest: ParentID is the notebook
+// it sits in, 0 for the top level.
+type Notebook struct {
+	ID        int
+	Name      string
+	ParentID  int
+	CreatedAt time.Time
+}
+
+// Where a notebook id is expected, these stand for groups of notes instead
+const (
+	AllNotes = -1 // every note, filed or not
+	Unfiled  = 0  // the notes in no notebook
+)
+
+type NotebookList struct {
+	store     NotebookStore
+	notes     *NoteList
+	Notebooks []*Notebook // oldest first
+	history   *History    // records mutations for undo, nil if none
+}
+
+// Load - Load semua notebooks dari store ke memory
+func (bl *NotebookList) Load() error {
+	notebooks, err := bl.store.LoadNotebooks()
+	if err != nil {
+		return err
+	}
+	bl.Notebooks = notebooks
+	return nil
+}
+
+func NewNotebookList(store NotebookStore, notes *NoteList) *NotebookList {
+	bl := &NotebookList{
+		store:     store,
+		notes:     notes,
+		Notebooks: []*Notebook{},
+	}
+	// Auto-load dari store saat inisialisasi
+	if err := bl.Load(); err != nil {
+		// Log error tapi tetap return instance kosong
+		fmt.Printf("Warning: failed to load notebooks: %v\n", err)
+	}
+	return bl
+}
+
+// Get returns the notebook with the given id, nil if there is none
+func (bl *NotebookList) Get(id int) *Notebook {
+	for _, notebook := range bl.Notebooks {
+		if notebook.ID == id {
+			return notebook
+		}
+	}
+	return nil
+}
+
+// Children returns the notebooks directly inside parent (0 for the top
+// level), sorted by name
+func (bl *NotebookList) Children(parent int) []*Notebook {
+	children := []*Notebook{}
+	for _, notebook := range bl.Notebooks {
+		if notebook.ParentID == parent {
+			children = append(children, notebook)
+		}
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return strings.ToLower(children[i].Name) < strings.ToLower(children[j].Name)
+	})
+	return children
+}
+
+// Tree returns every notebook depth first, each one followed by the
+// notebooks inside it, with how deep it is nested
+func (bl *NotebookList) Tree() (notebooks []*Notebook, depths []int) {
+	var walk func(parent, depth int)
+	walk = func(parent, depth int) {
+		for _, child := range bl.Children(parent) {
+			notebooks = append(notebooks, child)
+			depths = append(depths, depth)
+			walk(child.ID, depth+1)
+		}
+	}
+	walk(0, 0)
+	return notebooks, depths
+}
+
+// Path returns the names from the top level down to the notebook, e.g.
+// "Work / Meetings". Notes in a notebook that no longer exists are unfiled.
+func (bl *NotebookList) Path(id int) string {
+	names := []string{}
+	for notebook := bl.Get(id); notebook != nil; notebook = bl.Get(notebook.ParentID) {
+		names = append([]string{notebook.Name}, names...)
+	}
+	return strings.Join(names, " / ")
+}
+
+// Contains reports whether note is in the notebook or one nested in it, or
+// in the AllNotes or Unfiled group
+func (bl *NotebookList) Contains(id int, note *Note) bool {
+	if id == AllNotes {
+		return true
+	}
+	filed := note.NotebookID
+	if bl.Get(filed) == nil {
+		filed = Unfiled
+	}
+	if id == Unfiled || filed == Unfiled {
+		return id == filed
+	}
+	for notebook := bl.Get(filed); notebook != nil; notebook = bl.Get(notebook.ParentID) {
+		if notebook.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns how many notes are in the notebook and the ones nested in
+// it, or in the AllNotes or Unfiled group
+func (bl *NotebookList) Count(id int) int {
+	count := 0
+	for _, note := range bl.notes.Notes {
+		if bl.Contains(id, note) {
+			count++
+		}
+	}
+	return count
+}
+
+// Add - Tambah notebook ke store DAN memory sekaligus
+func (bl *NotebookList) Add(name string, parent int) error {
+	name = strings.TrimSpace(name)
+	if err := bl.checkName(name, parent, 0); err != nil {
+		return err
+	}
+	if parent != 0 && bl.Get(parent) == nil {
+		return fmt.Errorf("notebook with id %d not found", parent)
+	}
+	defer bl.history.track(describe("Add", "notebook", name))()
+
+	notebook := &Notebook{
+		Name:      name,
+		ParentID:  parent,
+		CreatedAt: time.Now(),
+	}
+	id, err := bl.store.InsertNotebook(notebook)
+	if err != nil {
+		return err
+	}
+	notebook.ID = id
+	bl.Notebooks = append(bl.Notebooks, notebook)
+	return nil
+}
+
+// Rename - Ganti nama notebook
+func (bl *NotebookList) Rename(id int, name string) error {
+	notebook := bl.Get(id)
+	if notebook == nil {
+		return fmt.Errorf("notebook with id %d not found", id)
+	}
+	name = strings.TrimSpace(name)
+	if err := bl.checkName(name, notebook.ParentID, id); err != nil {
+		return err
+	}
+	defer bl.history.track(describe("Rename", "notebook", notebook.Name))()
+
+	updated := *notebook
+	updated.Name = name
+	if err := bl.store.UpdateNotebook(&updated); err != nil {
+		return err
+	}
+	*notebook = updated
+	return nil
+}
+
+// Remove - Hapus notebook kosong dari store DAN memory. Notebooks with
+// notes or other notebooks in them are kept, so nothing gets lost.
+func (bl *NotebookList) Remove(id int) error {
+	notebook := bl.Get(id)
+	if notebook == nil {
+		return fmt.Errorf("notebook with id %d not found", id)
+	}
+	if len(bl.Children(id)) > 0 || bl.Count(id) > 0 {
+		return errors.New("only empty notebooks can be deleted, move their notes out first")
+	}
+	defer bl.history.track(describe("Delete", "notebook", notebook.Name))()
+
+	if err := bl.store.DeleteNotebook(id); err != nil {
+		return err
+	}
+	for i, notebook := range bl.Notebooks {
+		if notebook.ID == id {
+			bl.Notebooks = append(bl.Notebooks[:i], bl.Notebooks[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// checkName rejects empty names and ones already taken next to the
+// notebook (except by itself)
+func (bl *NotebookList) checkName(name string, parent, self int) error {
+	if name == "" {
+		return errors.New("a notebook needs a name")
+	}
+	for _, sibling := range bl.Children(parent) {
+		if sibling.ID != self && strings.EqualFold(sibling.Name, name) {
+			return fmt.Errorf("there is already a notebook called %q here", sibling.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,306 @@
+package models
+
+// === Recording stores ===
+// History wraps the store of every list it is attached to, so each write
+// first notes the items it is about to change. Lists write the store before
+// themselves, so at that point memory still holds the item as it was, and a
+// command only snapshots the items it touches. Inserted items are noted
+// right after the insert, while they aren't in memory yet, which records
+// them as created.
+
+type todoRecorder struct {
+	TodoStore
+	h *History
+}
+
+func (r todoRecorder) InsertTodo(todo *Todo) (int, error) {
+	id, err := r.TodoStore.InsertTodo(todo)
+	if err == nil {
+		r.h.touch(ChangeTodo, id)
+	}
+	return id, err
+}
+
+func (r todoRecorder) UpdateTodo(todo *Todo) error {
+	r.h.touch(ChangeTodo, todo.ID)
+	return r.TodoStore.UpdateTodo(todo)
+}
+
+func (r todoRecorder) DeleteTodo(id int) error {
+	r.touchFamily(id)
+	return r.TodoStore.DeleteTodo(id)
+}
+
+func (r todoRecorder) RestoreTodo(id int) error {
+	// A subtask brings its parent back, and the parent its subtasks
+	r.touchFamily(id)
+	if todo := findTodo(r.h.todos.Deleted, id); todo != nil && todo.IsSubtask() {
+		r.touchFamily(todo.ParentID)
+	}
+	return r.TodoStore.RestoreTodo(id)
+}
+
+func (r todoRecorder) PurgeTodo(id int) error {
+	ids := r.touchFamily(id)
+	for _, id := range ids {
+		r.h.touch(ChangeCompletion, id)
+	}
+	if err := r.TodoStore.PurgeTodo(id); err != nil {
+		return err
+	}
+	refs := make([]ItemRef, len(ids))
+	for i, id := range ids {
+		refs[i] = ItemRef{Page: PageTodos, ID: id}
+	}
+	r.h.dropLinks(refs...)
+	return nil
+}
+
+func (r todoRecorder) PutTodo(todo *Todo) error {
+	r.h.touch(ChangeTodo, todo.ID)
+	return r.TodoStore.PutTodo(todo)
+}
+
+func (r todoRecorder) ToggleTodo(toggle *TodoToggle) error {
+	for _, todo := range toggle.Todos {
+		r.h.touch(ChangeTodo, todo.ID)
+	}
+	for _, completion := range toggle.Completions {
+		r.h.touch(ChangeCompletion, completion.TodoID)
+	}
+	for _, id := range toggle.Reopened {
+		r.h.touch(ChangeCompletion, id)
+	}
+	var purged []ItemRef
+	for _, id := range toggle.Purged {
+		for _, id := range r.touchFamily(id) {
+			purged = append(purged, ItemRef{Page: PageTodos, ID: id})
+		}
+	}
+
+	if err := r.TodoStore.ToggleTodo(toggle); err != nil {
+		return err
+	}
+	r.h.dropLinks(purged...)
+	for _, spawn := range toggle.Spawned {
+		r.h.touch(ChangeTodo, spawn.Todo.ID)
+		for _, subtask := range spawn.Subtasks {
+			r.h.touch(ChangeTodo, subtask.ID)
+		}
+	}
+	return nil
+}
+
+func (r todoRecorder) RecordCompletion(completion TodoCompletion) error {
+	r.h.touch(ChangeCompletion, completion.TodoID)
+	return r.TodoStore.RecordCompletion(completion)
+}
+
+func (r todoRecorder) RemoveCompletion(todoID int) error {
+	r.h.touch(ChangeCompletion, todoID)
+	return r.TodoStore.RemoveCompletion(todoID)
+}
+
+// touchFamily notes a todo and its subtasks, trashed or not, and returns
+// their ids
+func (r todoRecorder) touchFamily(id int) []int {
+	ids := []int{id}
+	for _, todo := range append(append([]*Todo{}, r.h.todos.Todos...), r.h.todos.Deleted...) {
+		if todo.ParentID == id {
+			ids = append(ids, todo.ID)
+		}
+	}
+	for _, id := range ids {
+		r.h.touch(ChangeTodo, id)
+	}
+	return ids
+}
+
+type noteRecorder struct {
+	NoteStore
+	h *History
+}
+
+func (r noteRecorder) InsertNote(note *Note) (int, error) {
+	id, err := r.NoteStore.InsertNote(note)
+	if err == nil {
+		r.h.touch(ChangeNote, id)
+	}
+	return id, err
+}
+
+func (r noteRecorder) UpdateNote(note *Note) error {
+	r.h.touch(ChangeNote, note.ID)
+	return r.NoteStore.UpdateNote(note)
+}
+
+// ReviseNote records the note only. Its revisions are a log of every
+// version, undo restoring an older one adds to it rather than erasing it.
+func (r noteRecorder) ReviseNote(note *Note, revisions []NoteRevision) error {
+	r.h.touch(ChangeNote, note.ID)
+	return r.NoteStore.ReviseNote(note, revisions)
+}
+
+func (r noteRecorder) DeleteNote(id int) error {
+	r.h.touch(ChangeNote, id)
+	return r.NoteStore.DeleteNote(id)
+}
+
+func (r noteRecorder) RestoreNote(id int) error {
+	r.h.touch(ChangeNote, id)
+	return r.NoteStore.RestoreNote(id)
+}
+
+func (r noteRecorder) PurgeNote(id int) error {
+	r.h.touch(ChangeNote, id)
+	if err := r.NoteStore.PurgeNote(id); err != nil {
+		return err
+	}
+	r.h.dropLinks(ItemRef{Page: PageNotes, ID: id})
+	return nil
+}
+
+func (r noteRecorder) PutNote(note *Note) error {
+	r.h.touch(ChangeNote, note.ID)
+	return r.NoteStore.PutNote(note)
+}
+
+type eventRecorder struct {
+	EventStore
+	h *History
+}
+
+func (r eventRecorder) InsertEvent(event *Event) (int, error) {
+	id, err := r.EventStore.InsertEvent(event)
+	if err == nil {
+		r.h.touch(ChangeEvent, id)
+	}
+	return id, err
+}
+
+func (r eventRecorder) UpdateEvent(event *Event) error {
+	r.h.touch(ChangeEvent, event.ID)
+	return r.EventStore.UpdateEvent(event)
+}
+
+func (r eventRecorder) DeleteEvent(id int) error {
+	r.h.touch(ChangeEvent, id)
+	return r.EventStore.DeleteEvent(id)
+}
+
+func (r eventRecorder) RestoreEvent(id int) error {
+	r.h.touch(ChangeEvent, id)
+	return r.EventStore.RestoreEvent(id)
+}
+
+func (r eventRecorder) PurgeEvent(id int) error {
+	r.h.touch(ChangeEvent, id)
+	if err := r.EventStore.PurgeEvent(id); err != nil {
+		return err
+	}
+	r.h.dropLinks(ItemRef{Page: PageCalendar, ID: id})
+	return nil
+}
+
+func (r eventRecorder) PutEvent(event *Event) error {
+	r.h.touch(ChangeEvent, event.ID)
+	return r.EventStore.PutEvent(event)
+}
+
+type linkRecorder struct {
+	LinkStore
+	h *History
+}
+
+func (r linkRecorder) InsertLink(link *Link) (int, error) {
+	id, err := r.LinkStore.InsertLink(link)
+	if err == nil {
+		r.h.touch(ChangeLink, id)
+	}
+	return id, err
+}
+
+func (r linkRecorder) DeleteLink(id int) error {
+	r.h.touch(ChangeLink, id)
+	return r.LinkStore.DeleteLink(id)
+}
+
+func (r linkRecorder) PutLink(link *Link) error {
+	r.h.touch(ChangeLink, link.ID)
+	return r.LinkStore.PutLink(link)
+}
+
+// dropLinks forgets the links of items deleted for good, which their store
+// dropped along with them
+func (h *History) dropLinks(refs ...ItemRef) {
+	gone := map[ItemRef]bool{}
+	for _, ref := range refs {
+		gone[ref] = true
+	}
+
+	kept := make([]*Link, 0, len(h.links.Links))
+	for _, link := range h.links.Links {
+		if gone[link.From] || gone[link.To] {
+			h.touch(ChangeLink, link.ID)
+			continue
+		}
+		kept = append(kept, link)
+	}
+	h.links.Links = kept
+}
+
+type notebookRecorder struct {
+	NotebookStore
+	h *History
+}
+
+func (r notebookRecorder) InsertNotebook(notebook *Notebook) (int, error) {
+	id, err := r.NotebookStore.InsertNotebook(notebook)
+	if err == nil {
+		r.h.touch(ChangeNotebook, id)
+	}
+	return id, err
+}
+
+func (r notebookRecorder) UpdateNotebook(notebook *Notebook) error {
+	r.h.touch(ChangeNotebook, notebook.ID)
+	return r.NotebookStore.UpdateNotebook(notebook)
+}
+
+func (r notebookRecorder) DeleteNotebook(id int) error {
+	r.h.touch(ChangeNotebook, id)
+	return r.NotebookStore.DeleteNotebook(id)
+}
+
+func (r notebookRecorder) PutNotebook(notebook *Notebook) error {
+	r.h.touch(ChangeNotebook, notebook.ID)
+	return r.NotebookStore.PutNotebook(notebook)
+}
+
+type viewRecorder struct {
+	ViewStore
+	h *History
+}
+
+func (r viewRecorder) InsertView(view *SavedView) (int, error) {
+	id, err := r.ViewStore.InsertView(view)
+	if err == nil {
+		r.h.touch(ChangeView, id)
+	}
+	return id, err
+}
+
+func (r viewRecorder) UpdateView(view *SavedView) error {
+	r.h.touch(ChangeView, view.ID)
+	return r.ViewStore.UpdateView(view)
+}
+
+func (r viewRecorder) DeleteView(id int) error {
+	r.h.touch(ChangeView, id)
+	return r.ViewStore.DeleteView(id)
+}
+
+func (r viewRecorder) PutView(view *SavedView) error {
+	r.h.touch(ChangeView, view.ID)
+	return r.ViewStore.PutView(view)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RevisionLimit is how many versions of a note are kept, older ones are
+// dropped as new ones are saved
+const RevisionLimit = 100
+
+// NoteRevision is one saved version of a note's title and content. Every
+// edit saves one, so a bad edit never loses the text it replaced.
+type NoteRevision struct {
+	ID        int
+	NoteID    int
+	Title     string
+	Content   string
+	CreatedAt time.Time // when this version was saved, zero if unknown
+}
+
+// Revisions - Semua versi tersimpan dari note, terbaru dulu (dari store)
+func (nl *NoteList) Revisions(id int) ([]NoteRevision, error) {
+	return nl.store.LoadRevisions(id)
+}
+
+// RestoreRevision - Kembalikan title dan content note ke versi lama. Ini
+// edit biasa, jadi tersimpan sebagai revisi baru dan bisa di-undo.
+func (nl *NoteList) RestoreRevision(id, revisionID int) error {
+	note := nl.find(id)
+	if note == nil {
+		return fmt.Errorf("note with id %d not found", id)
+	}
+	defer nl.history.track(describe("Restore old version of", "note", note.Title))()
+
+	revisions, err := nl.store.LoadRevisions(id)
+	if err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		if revision.ID == revisionID {
+			return nl.Update(id, revision.Title, revision.Content, note.Tags)
+		}
+	}
+	return fmt.Errorf("revision %d of note %d not found", revisionID, id)
+}
+
+// newRevisions returns the versions of a note an edit has to save: the one
+// it writes, and on the first edit also the one it replaced, as notes
+// written before they had revisions have none yet. When that text was last
+// changed isn't known, so that version is saved undated.
+func (nl *NoteList) newRevisions(before, after *Note, now time.Time) ([]NoteRevision, error) {
+	if before.Title == after.Title && before.Content == after.Content {
+		return nil, nil
+	}
+
+	saved, err := nl.store.LoadRevisions(after.ID)
+	if err != nil {
+		return nil, err
+	}
+	var revisions []NoteRevision
+	if len(saved) == 0 {
+		revisions = append(revisions, NoteRevision{NoteID: before.ID, Title: before.Title, Content: before.Content})
+	}
+	return append(revisions, NoteRevision{NoteID: after.ID, Title: after.Title, Content: after.Content, CreatedAt: now}), nil
+}
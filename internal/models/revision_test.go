@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+)
+
+func addNote(t *testing.T, nl *NoteList, title, content string) *Note {
+	t.Helper()
+	note, err := nl.Create(title, content, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return note
+}
+
+func revisions(t *testing.T, nl *NoteList, id int) []NoteRevision {
+	t.Helper()
+	revisions, err := nl.Revisions(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return revisions
+}
+
+func TestRevisions(t *testing.T) {
+	nl := NewNoteList(NewMemoryNoteStore())
+	note := addNote(t, nl, "Recipe", "flour")
+
+	if got := revisions(t, nl, note.ID); len(got) != 0 {
+		t.Fatalf("a new note has %d revisions, want none", len(got))
+	}
+
+	// The first edit also saves the text it replaces, undated
+	if err := nl.Update(note.ID, "Bread", "flour, water", nil); err != nil {
+		t.Fatal(err)
+	}
+	got := revisions(t, nl, note.ID)
+	if len(got) != 2 {
+		t.Fatalf("got %d revisions after the first edit, want 2", len(got))
+	}
+	if got[0].Title != "Bread" || got[0].Content != "flour, water" || got[0].CreatedAt.IsZero() {
+		t.Errorf("newest revision = %+v, want the dated edit", got[0])
+	}
+	if got[1].Title != "Recipe" || got[1].Content != "flour" || !got[1].CreatedAt.IsZero() {
+		t.Errorf("oldest revision = %+v, want the undated original", got[1])
+	}
+
+	if err := nl.Update(note.ID, "Bread", "flour, water, salt", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := revisions(t, nl, note.ID); len(got) != 3 {
+		t.Errorf("got %d revisions after the second edit, want 3", len(got))
+	}
+
+	// Saving without changing the text saves no revision
+	if err := nl.Update(note.ID, "Bread", "flour, water, salt", []string{"baking"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := revisions(t, nl, note.ID); len(got) != 3 {
+		t.Errorf("got %d revisions after a tags-only edit, want 3", len(got))
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	l := newLists(NewMemoryHistoryStore())
+	note := addNote(t, l.notes, "Recipe", "flour")
+	if err := l.notes.Update(note.ID, "Bread", "flour, water", nil); err != nil {
+		t.Fatal(err)
+	}
+	original := revisions(t, l.notes, note.ID)[1]
+
+	if err := l.notes.RestoreRevision(note.ID, original.ID); err != nil {
+		t.Fatal(err)
+	}
+	if note.Title != "Recipe" || note.Content != "flour" {
+		t.Errorf("restored note = %q %q, want the original", note.Title, note.Content)
+	}
+	// Restoring is an edit too, the text it replaced stays in the history
+	got := revisions(t, l.notes, note.ID)
+	if len(got) != 3 || got[0].Content != "flour" || got[1].Content != "flour, water" {
+		t.Errorf("revisions after restoring = %+v, want the restored text on top", got)
+	}
+
+	cmd, err := l.history.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd == nil || cmd.Label != "Restore old version of note “Bread”" {
+		t.Errorf("undid %+v, want the restore", cmd)
+	}
+	if note := l.notes.Get(note.ID); note.Title != "Bread" || note.Content != "flour, water" {
+		t.Errorf("note after undo = %q %q, want the edit back", note.Title, note.Content)
+	}
+
+	if err := l.notes.RestoreRevision(note.ID, 999); err == nil {
+		t.Error("restoring a revision that doesn't exist didn't fail")
+	}
+}
+
+func TestRevisionLimit(t *testing.T) {
+	nl := NewNoteList(NewMemoryNoteStore())
+	note := addNote(t, nl, "Draft", "edit 0")
+	other := addNote(t, nl, "Other", "")
+	if err := nl.Update(other.ID, "Other", "kept", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	edits := RevisionLimit + 2
+	for i := 1; i <= edits; i++ {
+		if err := nl.Update(note.ID, "Draft", fmt.Sprintf("edit %d", i), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := revisions(t, nl, note.ID)
+	if len(got) != RevisionLimit {
+		t.Fatalf("got %d revisions, want %d", len(got), RevisionLimit)
+	}
+	if want := fmt.Sprintf("edit %d", edits-RevisionLimit+1); got[len(got)-1].Content != want {
+		t.Errorf("oldest revision = %q, want %q", got[len(got)-1].Content, want)
+	}
+	if got := revisions(t, nl, other.ID); len(got) != 2 {
+		t.Errorf("another note has %d revisions left, want its 2", len(got))
+	}
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// === Full-text search ===
+// Stores answer Search* with the live items matching a query, best match
+// first. The SQLite stores use an FTS5 index when the binary is built with
+// it and fall back to LIKE otherwise; the memory stores use MatchSearch.
+//
+// Query syntax: bare words must all appear and match as prefixes (meet finds
+// meeting), "quoted phrases" must appear as written. A trailing * is
+// accepted and means the same as a bare word.
+
+// SearchHit is one search result
+type SearchHit struct {
+	ID      int
+	Rank    float64 // lower is better
+	Snippet string  // matched text, hits wrapped in SnippetStart/SnippetEnd
+}
+
+// Markers around the matched words in SearchHit.Snippet
+const (
+	SnippetStart = "\x02"
+	SnippetEnd   = "\x03"
+)
+
+// snippetRadius is how much text MatchSearch keeps around the first hit
+const snippetRadius = 40
+
+// SearchTerm is one word or phrase of a search query
+type SearchTerm struct {
+	Text   string
+	Phrase bool
+}
+
+// ParseSearch splits a query into terms. An unterminated quote runs to the
+// end of the query, so a phrase still matches while it is being typed.
+func ParseSearch(query string) []SearchTerm {
+	var terms []SearchTerm
+	for query = strings.TrimSpace(query); query != ""; query = strings.TrimSpace(query) {
+		if query[0] == '"' {
+			end := strings.IndexByte(query[1:], '"')
+			if end < 0 {
+				end = len(query) - 1
+			}
+			if phrase := strings.Join(searchWords(query[1:end+1]), " "); phrase != "" {
+				terms = append(terms, SearchTerm{Text: phrase, Phrase: true})
+			}
+			query = query[min(end+2, len(query)):]
+			continue
+		}
+
+		end := strings.IndexFunc(query, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		if end < 0 {
+			end = len(query)
+		}
+		for _, word := range searchWords(query[:end]) {
+			terms = append(terms, SearchTerm{Text: word})
+		}
+		query = query[end:]
+	}
+	return terms
+}
+
+// searchWords lowercases text and splits it into words of letters and
+// digits, the way the FTS5 tokenizer does
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// MatchSearch reports whether every term appears in one of fields, scoring
+// hits in the first field (the title) higher. Used where there is no FTS5
+// index; words match anywhere, not just at the start of a word.
+func MatchSearch(terms []SearchTerm, fields ...string) (SearchHit, bool) {
+	if len(terms) == 0 {
+		return SearchHit{}, true
+	}
+
+	lowered := make([]string, len(fields))
+	for i, field := range fields {
+		lowered[i] = strings.ToLower(field)
+	}
+
+	hit := SearchHit{}
+	for _, term := range terms {
+		found := false
+		for i, field := range lowered {
+			count := strings.Count(field, term.Text)
+			if count == 0 {
+				continue
+			}
+			found = true
+			weight := 1.0
+			if i == 0 {
+				weight = 10
+			}
+			hit.Rank -= weight * float64(count)
+		}
+		if !found {
+			return SearchHit{}, false
+		}
+	}
+
+	// Snippet from the body if it matches, otherwise the title
+	for i := len(fields) - 1; i >= 0; i-- {
+		if snippet, ok := searchSnippet(fields[i], lowered[i], terms); ok {
+			hit.Snippet = snippet
+			break
+		}
+	}
+	return hit, true
+}
+
+// searchSnippet cuts the text around the first hit and marks every hit
+// inside the cut. lowered is text in lower case.
+func searchSnippet(text, lowered string, terms []SearchTerm) (string, bool) {
+	first := -1
+	for _, term := range terms {
+		if i := strings.Index(lowered, term.Text); i >= 0 && (first < 0 || i < first) {
+			first = i
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+	// ToLower can change byte lengths outside ASCII, offsets into lowered
+	// then don't line up with text: show the start without highlights
+	if len(lowered) != len(text) {
+		runes := []rune(strings.Join(strings.Fields(text), " "))
+		if len(runes) > snippetRadius*3 {
+			return string(runes[:snippetRadius*3]) + "…", true
+		}
+		return string(runes), true
+	}
+
+	start, end := max(first-snippetRadius, 0), min(first+snippetRadius*2, len(text))
+	for start > 0 && !isRuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !isRuneStart(text[end]) {
+		end++
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		matched := 0
+		for _, term := range terms {
+			if strings.HasPrefix(lowered[i:end], term.Text) && len(term.Text) > matched {
+				matched = len(term.Text)
+			}
+		}
+		if matched > 0 {
+			b.WriteString(SnippetStart + text[i:i+matched] + SnippetEnd)
+			i += matched
+			continue
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return strings.Join(strings.Fields(b.String()), " "), true
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// SortHits orders hits best first, by id for equal ranks
+func SortHits(hits []SearchHit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Rank != hits[j].Rank {
+			return hits[i].Rank < hits[j].Rank
+		}
+		return hits[i].ID < hits[j].ID
+	})
+}
@@ -0,0 +1,113 @@
+package models
+
+// === Storage interfaces ===
+// TodoList, NoteList and EventList keep their items in memory and call a
+// store for every mutation. The SQLite implementation lives in internal/db,
+// the in-memory one in memory.go (handy for samples and tests).
+//
+// Deleting only moves an item to the trash: Load* skips trashed items,
+// LoadDeleted* returns them (most recently deleted first), Restore* brings
+// one back and Purge* deletes it for good.
+//
+// Search* returns the live items matching a query, best match first, see
+// search.go.
+//
+// Put* writes an item exactly as given, trash state included, inserting it
+// under its own id when it no longer exists. History uses it to undo and redo.
+
+// TodoStore persists todos
+type TodoStore interface {
+	// LoadTodos returns every stored todo ordered by id
+	LoadTodos() ([]*Todo, error)
+	// InsertTodo saves a new todo and returns its id
+	InsertTodo(todo *Todo) (int, error)
+	UpdateTodo(todo *Todo) error
+	// DeleteTodo, RestoreTodo and PurgeTodo take subtasks along
+	DeleteTodo(id int) error
+	LoadDeletedTodos() ([]*Todo, error)
+	RestoreTodo(id int) error
+	PurgeTodo(id int) error
+	PutTodo(todo *Todo) error
+	SearchTodos(query string) ([]SearchHit, error)
+	// ToggleTodo writes everything completing or reopening a todo changes
+	// at once, see TodoToggle
+	ToggleTodo(toggle *TodoToggle) error
+
+	// LoadCompletions returns the completion history of every recurring
+	// series, newest first
+	LoadCompletions() ([]TodoCompletion, error)
+	RecordCompletion(completion TodoCompletion) error
+	// RemoveCompletion drops the history entry written when todoID was completed
+	RemoveCompletion(todoID int) error
+}
+
+// NoteStore persists notes
+type NoteStore interface {
+	// LoadNotes returns every stored note ordered by id
+	LoadNotes() ([]*Note, error)
+	// InsertNote saves a new note and returns its id
+	InsertNote(note *Note) (int, error)
+	UpdateNote(note *Note) error
+	DeleteNote(id int) error
+	LoadDeletedNotes() ([]*Note, error)
+	RestoreNote(id int) error
+	PurgeNote(id int) error
+	PutNote(note *Note) error
+	SearchNotes(query string) ([]SearchHit, error)
+
+	// LoadRevisions returns the saved versions of a note, newest first.
+	// Undated ones (a zero CreatedAt) come last.
+	LoadRevisions(noteID int) ([]NoteRevision, error)
+	// ReviseNote is UpdateNote that also saves the given versions of the
+	// note, all or nothing. Only the newest RevisionLimit versions of a note
+	// are kept. PurgeNote drops the note's revisions along with it.
+	ReviseNote(note *Note, revisions []NoteRevision) error
+}
+
+// EventStore persists events
+type EventStore interface {
+	// LoadEvents returns every stored event ordered by start time
+	LoadEvents() ([]*Event, error)
+	// InsertEvent saves a new event and returns its id
+	InsertEvent(event *Event) (int, error)
+	UpdateEvent(event *Event) error
+	DeleteEvent(id int) error
+	LoadDeletedEvents() ([]*Event, error)
+	RestoreEvent(id int) error
+	PurgeEvent(id int) error
+	PutEvent(event *Event) error
+	SearchEvents(query string) ([]SearchHit, error)
+}
+
+// ViewStore persists saved views
+type ViewStore interface {
+	// LoadViews returns every saved view ordered by id
+	LoadViews() ([]*SavedView, error)
+	// InsertView saves a new view and returns its id
+	InsertView(view *SavedView) (int, error)
+	UpdateView(view *SavedView) error
+	DeleteView(id int) error
+	PutView(view *SavedView) error
+}
+
+// NotebookStore persists notebooks. Notes keep the id of their notebook.
+type NotebookStore interface {
+	// LoadNotebooks returns every notebook ordered by id
+	LoadNotebooks() ([]*Notebook, error)
+	// InsertNotebook saves a new notebook and returns its id
+	InsertNotebook(notebook *Notebook) (int, error)
+	UpdateNotebook(notebook *Notebook) error
+	DeleteNotebook(id int) error
+	PutNotebook(notebook *Notebook) error
+}
+
+// LinkStore persists links between items. Purging an item for good drops
+// its links too.
+type LinkStore interface {
+	// LoadLinks returns every stored link ordered by id
+	LoadLinks() ([]*Link, error)
+	// InsertLink saves a new link and returns its id
+	InsertLink(link *Link) (int, error)
+	DeleteLink(id int) error
+	PutLink(link *Link) error
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// ParseTags reads tags typed as "#work home, side-project" into a sorted,
+// de-duplicated list of lowercase names without the leading '#'
+func ParseTags(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, field := range fields {
+		tag := NormalizeTag(field)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// NormalizeTag lowercases a tag and strips the leading '#'
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimLeft(strings.TrimSpace(tag), "#"))
+}
+
+// FormatTags renders tags the way they are typed: "#home #work"
+func FormatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = "#" + tag
+	}
+	return strings.Join(parts, " ")
+}
+
+// HasTags reports whether every wanted tag is in tags
+func HasTags(tags []string, wanted []string) bool {
+	for _, w := range wanted {
+		found := false
+		for _, tag := range tags {
+			if tag == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// collectTags merges tag lists into one sorted list without duplicates
+func collectTags(lists ...[]string) []string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, list := range lists {
+		for _, tag := range list {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -1,9 +1,12 @@
 package models
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
+
+	"prodBooster/internal/recur"
 )
 
 type Priority int
@@ -34,61 +37,108 @@ type Todo struct {
 	Priority    Priority
 	CreatedAt   time.Time
 	DueTime     *time.Time
+	Tags        []string
+	Recurrence  string    // RRULE, empty kalau tidak berulang
+	SeriesID    int       // id todo pertama dari series berulang, 0 kalau bukan bagian series
+	ParentID    int       // id todo induk untuk subtask, 0 untuk todo biasa
+	DeletedAt   time.Time // kapan dibuang ke trash, zero untuk todo aktif
+	Pinned      bool      // stays at the top of the list and on the dashboard
 }
 
-type TodoList struct {
-	db       *sql.DB
-	Todos    []*Todo
-	Selected int
-	NextID   int
+// IsSubtask reports whether the todo is a checklist item of another todo
+func (t *Todo) IsSubtask() bool {
+	return t.ParentID != 0
 }
 
-// Load - Load semua todos dari database ke memory
-func (tl *TodoList) Load() error {
-	query := "SELECT id, title, description, completed, priority, created_at, due_date FROM todos ORDER BY id"
-	rows, err := tl.db.Query(query)
-	if err != nil {
-		return fmt.Errorf("failed to query todos: %w", err)
+// IsRecurring reports whether completing the todo spawns the next occurrence
+func (t *Todo) IsRecurring() bool {
+	return t.Recurrence != ""
+}
+
+// Rule parses the todo's recurrence. ok is false for non-recurring todos
+// and for rules that no longer parse.
+func (t *Todo) Rule() (rule recur.Rule, ok bool) {
+	if t.Recurrence == "" {
+		return recur.Rule{}, false
 	}
-	defer rows.Close()
+	rule, err := recur.Parse(t.Recurrence)
+	return rule, err == nil
+}
 
-	tl.Todos = []*Todo{} // Clear existing
+// Series returns the id shared by every occurrence of a recurring todo
+func (t *Todo) Series() int {
+	if t.SeriesID != 0 {
+		return t.SeriesID
+	}
+	return t.ID
+}
 
-	for rows.Next() {
-		var id int
-		var title, description string
-		var completed bool
-		var priority int
-		var createdAt time.Time
-		var dueDate sql.NullTime
+// TodoCompletion is one entry in the completion history of a recurring todo
+type TodoCompletion struct {
+	SeriesID    int
+	TodoID      int
+	DueTime     *time.Time
+	CompletedAt time.Time
+}
 
-		if err := rows.Scan(&id, &title, &description, &completed, &priority, &createdAt, &dueDate); err != nil {
-			return fmt.Errorf("failed to scan todo: %w", err)
-		}
+// TodoToggle is everything completing or reopening a todo writes: the todo
+// itself, a parent completed along with its last subtask, the next
+// occurrence of a recurring todo and its fresh checklist, and the
+// completion history. Stores write it in one go so a failure can't leave
+// half a series behind.
+type TodoToggle struct {
+	Todos       []*Todo          // written as given, completed flag included
+	Spawned     []TodoSpawn      // inserted, their ids are set
+	Completions []TodoCompletion // recorded
+	Reopened    []int            // todos whose completion is removed
+	Purged      []int            // spawned occurrences dropped again
+}
 
-		todo := &Todo{
-			ID:          id,
-			Title:       title,
-			Description: description,
-			Completed:   completed,
-			Priority:    Priority(priority),
-			CreatedAt:   createdAt,
-		}
+// TodoSpawn is the next occurrence of a recurring todo with its checklist.
+// The subtasks get the new todo as their parent when inserted.
+type TodoSpawn struct {
+	Todo     *Todo
+	Subtasks []*Todo
+}
 
-		if dueDate.Valid {
-			todo.DueTime = &dueDate.Time
-		}
+type TodoList struct {
+	store       TodoStore
+	Todos       []*Todo
+	Deleted     []*Todo // trash, terbaru dulu
+	Selected    int
+	NextID      int
+	completions []TodoCompletion // newest first
+	history     *History         // records mutations for undo, nil if none
+
+	// AutoCompleteParents marks a todo done once all its subtasks are done
+	AutoCompleteParents bool
+}
 
-		tl.Todos = append(tl.Todos, todo)
+// Load - Load semua todos dari store ke memory
+func (tl *TodoList) Load() error {
+	todos, err := tl.store.LoadTodos()
+	if err != nil {
+		return err
+	}
 
-		// Update NextID
-		if id >= tl.NextID {
-			tl.NextID = id + 1
-		}
+	deleted, err := tl.store.LoadDeletedTodos()
+	if err != nil {
+		return err
+	}
+
+	completions, err := tl.store.LoadCompletions()
+	if err != nil {
+		return err
 	}
 
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("error iterating todos: %w", err)
+	tl.Todos = todos
+	tl.Deleted = deleted
+	tl.completions = completions
+	for _, todo := range append(todos, deleted...) {
+		// Update NextID
+		if todo.ID >= tl.NextID {
+			tl.NextID = todo.ID + 1
+		}
 	}
 
 	return nil
@@ -100,8 +150,8 @@ func (tl *TodoList) Load() error {
  */
 
 func NewTodoListSample() *TodoList {
-	return &TodoList{
-		Todos: []*Todo{
+	return NewTodoList(NewMemoryTodoStore(
+		[]*Todo{
 			{
 				ID: 1, Title: "Buy groceries",
 				Description: "Milk, Bread, Eggs",
@@ -149,20 +199,19 @@ func NewTodoListSample() *TodoList {
 				Priority:    PriorityHigh,
 				CreatedAt:   time.Now(),
 			},
-		},
-		Selected: 0,
-		NextID:   7,
-	}
+		}...,
+	))
 }
 
-func NewTodoList(db_ *sql.DB) *TodoList {
+func NewTodoList(store TodoStore) *TodoList {
 	tl := &TodoList{
-		db:       db_,
-		Todos:    []*Todo{},
-		Selected: 0,
-		NextID:   1,
+		store:               store,
+		Todos:               []*Todo{},
+		Selected:            0,
+		NextID:              1,
+		AutoCompleteParents: true,
 	}
-	// Auto-load dari database saat inisialisasi
+	// Auto-load dari store saat inisialisasi
 	if err := tl.Load(); err != nil {
 		// Log error tapi tetap return instance kosong
 		fmt.Printf("Warning: failed to load todos: %v\n", err)
@@ -193,114 +242,519 @@ func (tl *TodoList) Count() int {
 	return len(tl.Todos)
 }
 
-// Add - Tambah todo ke database DAN memory sekaligus
-func (tl *TodoList) Add(title, description string, priority Priority, dueTime *time.Time) error {
-	query := `INSERT INTO todos (title, description, completed, priority, due_date, created_at)
-	          VALUES (?, ?, ?, ?, ?, ?)`
+// Add - Tambah todo ke store DAN memory sekaligus
+func (tl *TodoList) Add(title, description string, priority Priority, dueTime *time.Time, tags []string, recurrence string) error {
+	defer tl.history.track(describe("Add", "todo", title))()
 
-	now := time.Now()
-	result, err := tl.db.Exec(query, title, description, false, int(priority), dueTime, now)
-	if err != nil {
-		return fmt.Errorf("failed to add todo to database: %w", err)
+	todo := &Todo{
+		Title:       title,
+		Description: description,
+		Completed:   false,
+		Priority:    priority,
+		CreatedAt:   time.Now(),
+		DueTime:     dueTime,
+		Tags:        tags,
+		Recurrence:  recurrence,
 	}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return fmt.Errorf("failed to get last insert id: %w", err)
+	return tl.insert(todo)
+}
+
+// AddSubtask - Tambah subtask di bawah parentID ke store DAN memory sekaligus
+func (tl *TodoList) AddSubtask(parentID int, title, description string, priority Priority, dueTime *time.Time, tags []string) error {
+	defer tl.history.track(describe("Add", "subtask", title))()
+
+	parent := tl.find(parentID)
+	if parent == nil {
+		return fmt.Errorf("todo with id %d not found", parentID)
+	}
+	if parent.IsSubtask() {
+		// Checklist cuma satu level: tambahkan ke induknya
+		parentID = parent.ParentID
 	}
 
-	// Tambah ke memory
-	todo := &Todo{
-		ID:          int(id),
+	return tl.insert(&Todo{
 		Title:       title,
 		Description: description,
-		Completed:   false,
 		Priority:    priority,
-		CreatedAt:   now,
+		CreatedAt:   time.Now(),
 		DueTime:     dueTime,
+		Tags:        tags,
+		ParentID:    parentID,
+	})
+}
+
+func (tl *TodoList) insert(todo *Todo) error {
+	id, err := tl.store.InsertTodo(todo)
+	if err != nil {
+		return err
 	}
+
+	// Tambah ke memory
+	todo.ID = id
 	tl.Todos = append(tl.Todos, todo)
-	tl.NextID = int(id) + 1
+	tl.NextID = id + 1
 
 	return nil
 }
 
-// Update - Update todo di database DAN memory sekaligus
-func (tl *TodoList) Update(id int, title, description string, priority Priority, dueTime *time.Time) error {
-	query := `UPDATE todos SET title=?, description=?, priority=?, due_date=?, updated_at=CURRENT_TIMESTAMP
-	          WHERE id=?`
+// Update - Update todo di store DAN memory sekaligus
+func (tl *TodoList) Update(id int, title, description string, priority Priority, dueTime *time.Time, tags []string, recurrence string) error {
+	defer tl.history.track(describe("Edit", "todo", title))()
 
-	_, err := tl.db.Exec(query, title, description, int(priority), dueTime, id)
-	if err != nil {
-		return fmt.Errorf("failed to update todo in database: %w", err)
+	todo := tl.find(id)
+	if todo == nil {
+		return fmt.Errorf("todo with id %d not found", id)
+	}
+	if todo.IsSubtask() && recurrence != "" {
+		return errors.New("subtasks repeat with their parent, not on their own")
 	}
 
-	// Update di memory
-	for _, todo := range tl.Todos {
-		if todo.ID == id {
-			todo.Title = title
-			todo.Description = description
-			todo.Priority = priority
-			todo.DueTime = dueTime
-			break
-		}
+	updated := *todo
+	updated.Title = title
+	updated.Description = description
+	updated.Priority = priority
+	updated.DueTime = dueTime
+	updated.Tags = tags
+	updated.Recurrence = recurrence
+
+	if err := tl.store.UpdateTodo(&updated); err != nil {
+		return err
 	}
 
+	// Update di memory
+	*todo = updated
+
 	return nil
 }
 
-// Remove - Hapus todo dari database DAN memory sekaligus
+// Remove - Pindahkan todo (dan subtasks-nya) ke trash di store DAN memory sekaligus
 func (tl *TodoList) Remove(id int) error {
-	query := `DELETE FROM todos WHERE id=?`
+	defer tl.history.track(describe("Delete", "todo", tl.titleOf(id)))()
 
-	_, err := tl.db.Exec(query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete todo from database: %w", err)
+	if err := tl.store.DeleteTodo(id); err != nil {
+		return err
 	}
 
-	// Hapus dari memory
-	for i, todo := range tl.Todos {
-		if todo.ID == id {
-			tl.Todos = append(tl.Todos[:i], tl.Todos[i+1:]...)
-			// Adjust selected index
-			if tl.Selected >= len(tl.Todos) && tl.Selected > 0 {
-				tl.Selected--
+	// Pindah ke trash di memory
+	now := time.Now()
+	var trashed []*Todo
+	tl.Todos, trashed = splitTodos(tl.Todos, func(todo *Todo) bool {
+		return todo.ID == id || todo.ParentID == id
+	})
+	for _, todo := range trashed {
+		todo.DeletedAt = now
+	}
+	tl.Deleted = append(trashed, tl.Deleted...)
+
+	// Adjust selected index
+	if tl.Selected >= len(tl.Todos) && tl.Selected > 0 {
+		tl.Selected = len(tl.Todos) - 1
+	}
+
+	return nil
+}
+
+// Restore - Kembalikan todo dari trash di store DAN memory sekaligus,
+// bersama subtasks yang ikut dibuang dan induknya kalau ikut di trash
+func (tl *TodoList) Restore(id int) error {
+	defer tl.history.track(describe("Restore", "todo", tl.titleOf(id)))()
+
+	todo := findTodo(tl.Deleted, id)
+	if todo == nil {
+		return fmt.Errorf("todo with id %d is not in the trash", id)
+	}
+
+	if err := tl.store.RestoreTodo(id); err != nil {
+		return err
+	}
+
+	// A subtask can't be live under a trashed parent, so it brings the
+	// parent back the way restoring the parent would
+	parent := findTodo(tl.Deleted, todo.ParentID)
+
+	var restored []*Todo
+	tl.Deleted, restored = splitTodos(tl.Deleted, func(other *Todo) bool {
+		return other.trashedWith(todo) || parent != nil && other.trashedWith(parent)
+	})
+	for _, todo := range restored {
+		todo.DeletedAt = time.Time{}
+	}
+	tl.Todos = append(tl.Todos, restored...)
+
+	return nil
+}
+
+// Purge - Hapus todo (dan subtasks-nya) dari trash untuk selamanya
+func (tl *TodoList) Purge(id int) error {
+	defer tl.history.track(describe("Delete forever", "todo", tl.titleOf(id)))()
+
+	if err := tl.store.PurgeTodo(id); err != nil {
+		return err
+	}
+
+	var purged []*Todo
+	tl.Deleted, purged = splitTodos(tl.Deleted, func(todo *Todo) bool {
+		return todo.ID == id || todo.ParentID == id
+	})
+	// The completion history outlives its todos, like ON DELETE SET NULL
+	for _, todo := range purged {
+		for i := range tl.completions {
+			if tl.completions[i].TodoID == todo.ID {
+				tl.completions[i].TodoID = 0
 			}
-			break
 		}
 	}
 
 	return nil
 }
 
-// ToggleCompleted - Toggle status completed di database DAN memory sekaligus
+// PurgeTrash - Hapus selamanya semua todo yang dibuang sebelum cutoff
+func (tl *TodoList) PurgeTrash(cutoff time.Time) error {
+	for _, todo := range append([]*Todo{}, tl.Deleted...) {
+		if findTodo(tl.Deleted, todo.ID) != nil && todo.DeletedAt.Before(cutoff) {
+			if err := tl.Purge(todo.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitTodos separates the todos matching match from the rest
+func splitTodos(todos []*Todo, match func(*Todo) bool) (rest, matched []*Todo) {
+	rest = make([]*Todo, 0, len(todos))
+	for _, todo := range todos {
+		if match(todo) {
+			matched = append(matched, todo)
+		} else {
+			rest = append(rest, todo)
+		}
+	}
+	return rest, matched
+}
+
+// Search - Cari todo aktif lewat store (full-text), hasil terbaik dulu
+func (tl *TodoList) Search(query string) ([]SearchHit, error) {
+	return tl.store.SearchTodos(query)
+}
+
+// titleOf returns the title of a todo, trashed or not
+func (tl *TodoList) titleOf(id int) string {
+	if todo := findTodo(append(append([]*Todo{}, tl.Todos...), tl.Deleted...), id); todo != nil {
+		return todo.Title
+	}
+	return ""
+}
+
+// trashedWith reports whether restoring todo brings t back: t is todo or
+// one of its subtasks trashed along with it
+func (t *Todo) trashedWith(todo *Todo) bool {
+	return t.ID == todo.ID || t.ParentID == todo.ID && t.DeletedAt.Equal(todo.DeletedAt)
+}
+
+func findTodo(todos []*Todo, id int) *Todo {
+	for _, todo := range todos {
+		if todo.ID == id {
+			return todo
+		}
+	}
+	return nil
+}
+
+// ToggleCompleted - Toggle status completed di store DAN memory sekaligus
 func (tl *TodoList) ToggleCompleted(id int) error {
-	// Get current todo
-	var todo *Todo
-	for _, t := range tl.Todos {
-		if t.ID == id {
-			todo = t
-			break
+	todo := tl.find(id)
+	if todo == nil {
+		return fmt.Errorf("todo with id %d not found", id)
+	}
+
+	action := "Complete"
+	if todo.Completed {
+		action = "Reopen"
+	}
+	defer tl.history.track(describe(action, "todo", todo.Title))()
+
+	now := time.Now()
+	toggled := *todo
+	toggled.Completed = !todo.Completed
+	toggle := &TodoToggle{Todos: []*Todo{&toggled}}
+
+	if toggled.Completed {
+		if todo.IsRecurring() {
+			if err := tl.completeOccurrence(&toggled, now, toggle); err != nil {
+				return err
+			}
+		}
+		if err := tl.completeParent(todo, now, toggle); err != nil {
+			return err
+		}
+	} else {
+		tl.reopenOccurrence(&toggled, toggle)
+	}
+
+	if err := tl.store.ToggleTodo(toggle); err != nil {
+		return err
+	}
+
+	// Update di memory
+	for _, changed := range toggle.Todos {
+		*tl.find(changed.ID) = *changed
+	}
+	for _, completion := range toggle.Completions {
+		tl.completions = append([]TodoCompletion{completion}, tl.completions...)
+	}
+	for _, id := range toggle.Reopened {
+		tl.dropCompletion(id)
+	}
+	for _, id := range toggle.Purged {
+		tl.Todos, _ = splitTodos(tl.Todos, func(other *Todo) bool {
+			return other.ID == id || other.ParentID == id
+		})
+	}
+	for _, spawn := range toggle.Spawned {
+		tl.Todos = append(tl.Todos, spawn.Todo)
+		tl.Todos = append(tl.Todos, spawn.Subtasks...)
+		for _, spawned := range append([]*Todo{spawn.Todo}, spawn.Subtasks...) {
+			tl.NextID = max(tl.NextID, spawned.ID+1)
 		}
 	}
+
+	return nil
+}
+
+// TogglePinned - Pin atau lepas pin todo di store DAN memory sekaligus
+func (tl *TodoList) TogglePinned(id int) error {
+	todo := tl.find(id)
 	if todo == nil {
 		return fmt.Errorf("todo with id %d not found", id)
 	}
 
-	newStatus := !todo.Completed
-	query := `UPDATE todos SET completed=?, updated_at=CURRENT_TIMESTAMP WHERE id=?`
+	action := "Pin"
+	if todo.Pinned {
+		action = "Unpin"
+	}
+	defer tl.history.track(describe(action, "todo", todo.Title))()
 
-	_, err := tl.db.Exec(query, newStatus, id)
-	if err != nil {
-		return fmt.Errorf("failed to toggle todo in database: %w", err)
+	updated := *todo
+	updated.Pinned = !todo.Pinned
+	if err := tl.store.UpdateTodo(&updated); err != nil {
+		return err
 	}
 
 	// Update di memory
-	todo.Completed = newStatus
+	*todo = updated
+
+	return nil
+}
+
+// completeParent marks the parent of subtask done too when subtask, about
+// to be completed, is the last one open and AutoCompleteParents is on
+func (tl *TodoList) completeParent(subtask *Todo, now time.Time, toggle *TodoToggle) error {
+	if !tl.AutoCompleteParents || !subtask.IsSubtask() {
+		return nil
+	}
+	parent := tl.find(subtask.ParentID)
+	if parent == nil || parent.Completed {
+		return nil
+	}
+	if done, total := tl.Progress(parent.ID); done+1 < total {
+		return nil
+	}
 
+	completed := *parent
+	completed.Completed = true
+	toggle.Todos = append(toggle.Todos, &completed)
+	if parent.IsRecurring() {
+		return tl.completeOccurrence(&completed, now, toggle)
+	}
 	return nil
 }
 
+// completeOccurrence records the completion of a recurring todo and spawns
+// the next occurrence. The recurrence moves to the new todo so only the
+// latest occurrence of a series ever repeats. todo is the copy of the
+// occurrence that toggle writes.
+func (tl *TodoList) completeOccurrence(todo *Todo, now time.Time, toggle *TodoToggle) error {
+	toggle.Completions = append(toggle.Completions, TodoCompletion{
+		SeriesID:    todo.Series(),
+		TodoID:      todo.ID,
+		DueTime:     todo.DueTime,
+		CompletedAt: now,
+	})
+
+	due, ok, err := tl.nextDue(todo, now)
+	if err != nil {
+		return fmt.Errorf("can't find the next %q: %w", todo.Title, err)
+	}
+	if ok {
+		spawn := TodoSpawn{Todo: &Todo{
+			Title:       todo.Title,
+			Description: todo.Description,
+			Priority:    todo.Priority,
+			CreatedAt:   now,
+			DueTime:     &due,
+			Tags:        cloneTags(todo.Tags),
+			Recurrence:  todo.Recurrence,
+			SeriesID:    todo.Series(),
+			Pinned:      todo.Pinned,
+		}}
+
+		// The checklist starts over with every occurrence
+		for _, subtask := range tl.Subtasks(todo.ID) {
+			fresh := *subtask
+			fresh.ID = 0
+			fresh.Completed = false
+			fresh.CreatedAt = now
+			fresh.Tags = cloneTags(subtask.Tags)
+			spawn.Subtasks = append(spawn.Subtasks, &fresh)
+		}
+		toggle.Spawned = append(toggle.Spawned, spawn)
+	}
+	// Otherwise the series is over (UNTIL/COUNT reached)
+
+	todo.SeriesID = todo.Series()
+	todo.Recurrence = ""
+	return nil
+}
+
+// nextDue computes when the occurrence after todo is due. Occurrences that
+// already passed are skipped, so finishing a daily todo a week late doesn't
+// queue up seven overdue copies. COUNT is checked against the history,
+// counting todo as completed, since each occurrence is anchored on its own
+// due date.
+func (tl *TodoList) nextDue(todo *Todo, now time.Time) (time.Time, bool, error) {
+	rule, ok := todo.Rule()
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	if rule.Count > 0 && len(tl.Completions(todo.Series()))+1 >= rule.Count {
+		return time.Time{}, false, nil
+	}
+	rule.Count = 0
+
+	start := now
+	if todo.DueTime != nil {
+		start = *todo.DueTime
+	}
+	after := start
+	if now.After(after) {
+		after = now
+	}
+	return rule.Next(start, after)
+}
+
+// reopenOccurrence undoes completeOccurrence when a todo that spawned the
+// next occurrence is marked pending again, as long as that occurrence is
+// still untouched (pending). Otherwise the todo simply becomes pending.
+// todo is the copy of the occurrence that toggle writes.
+func (tl *TodoList) reopenOccurrence(todo *Todo, toggle *TodoToggle) {
+	if todo.SeriesID == 0 || todo.IsRecurring() || !tl.hasCompletion(todo.ID) {
+		return
+	}
+
+	var spawned *Todo
+	for _, other := range tl.Todos {
+		if other.SeriesID == todo.SeriesID && other.ID > todo.ID && other.IsRecurring() {
+			spawned = other
+		}
+	}
+
+	toggle.Reopened = append(toggle.Reopened, todo.ID)
+	if spawned == nil || spawned.Completed {
+		return
+	}
+
+	// Occurrence yang di-spawn dibuang langsung, bukan ke trash
+	todo.Recurrence = spawned.Recurrence
+	toggle.Purged = append(toggle.Purged, spawned.ID)
+}
+
+// Subtasks - Checklist sebuah todo, urut waktu dibuat (hanya memory)
+func (tl *TodoList) Subtasks(parentID int) []*Todo {
+	var subtasks []*Todo
+	for _, todo := range tl.Todos {
+		if todo.ParentID == parentID {
+			subtasks = append(subtasks, todo)
+		}
+	}
+	sort.SliceStable(subtasks, func(i, j int) bool { return subtasks[i].ID < subtasks[j].ID })
+	return subtasks
+}
+
+// Progress - Jumlah subtask yang selesai dan total (hanya memory)
+func (tl *TodoList) Progress(parentID int) (done, total int) {
+	for _, todo := range tl.Todos {
+		if todo.ParentID == parentID {
+			total++
+			if todo.Completed {
+				done++
+			}
+		}
+	}
+	return done, total
+}
+
+// Completions - Riwayat penyelesaian sebuah series, terbaru dulu (hanya memory)
+func (tl *TodoList) Completions(seriesID int) []TodoCompletion {
+	var history []TodoCompletion
+	for _, completion := range tl.completions {
+		if completion.SeriesID == seriesID {
+			history = append(history, completion)
+		}
+	}
+	return history
+}
+
+func (tl *TodoList) hasCompletion(todoID int) bool {
+	for _, completion := range tl.completions {
+		if completion.TodoID == todoID {
+			return true
+		}
+	}
+	return false
+}
+
+func (tl *TodoList) dropCompletion(todoID int) {
+	kept := tl.completions[:0]
+	for _, completion := range tl.completions {
+		if completion.TodoID != todoID {
+			kept = append(kept, completion)
+		}
+	}
+	tl.completions = kept
+}
+
+// Get returns the todo with the given id, or nil
+func (tl *TodoList) Get(id int) *Todo {
+	return tl.find(id)
+}
+
+// find returns the in-memory todo with the given id, or nil
+func (tl *TodoList) find(id int) *Todo {
+	return findTodo(tl.Todos, id)
+}
+
+// AllTags - Semua tag yang dipakai todos (hanya memory)
+func (tl *TodoList) AllTags() []string {
+	lists := make([][]string, len(tl.Todos))
+	for i, todo := range tl.Todos {
+		lists[i] = todo.Tags
+	}
+	return collectTags(lists...)
+}
+
+// Pinned - Todo yang di-pin, urut id (hanya memory)
+func (tl *TodoList) Pinned() []*Todo {
+	var pinned []*Todo
+	for _, todo := range tl.Todos {
+		if todo.Pinned {
+			pinned = append(pinned, todo)
+		}
+	}
+	return pinned
+}
+
 // GetByPriority - Filter (hanya memory)
 func (tl *TodoList) GetByPriority(priority Priority) []*Todo {
 	var filtered []*Todo
@@ -0,0 +1,290 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// tomorrow is a due date that hasn't passed yet, so the next occurrence of
+// a daily todo is the day after it
+func tomorrow() time.Time {
+	return time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+}
+
+func addTodo(t *testing.T, tl *TodoList, title string, due *time.Time, recurrence string) *Todo {
+	t.Helper()
+	if err := tl.Add(title, "", PriorityMedium, due, nil, recurrence); err != nil {
+		t.Fatal(err)
+	}
+	return tl.Todos[len(tl.Todos)-1]
+}
+
+func addSubtask(t *testing.T, tl *TodoList, parentID int, title string) *Todo {
+	t.Helper()
+	if err := tl.AddSubtask(parentID, title, "", PriorityMedium, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	return tl.Todos[len(tl.Todos)-1]
+}
+
+func toggle(t *testing.T, tl *TodoList, id int) {
+	t.Helper()
+	if err := tl.ToggleCompleted(id); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// occurrences returns the pending occurrences of a series
+
+func occurrences(tl *TodoList, seriesID int) []*Todo {
+	var pending []*Todo
+	for _, todo := range tl.Todos {
+		if !todo.IsSubtask() && todo.Series() == seriesID && !todo.Completed {
+			pending = append(pending, todo)
+		}
+	}
+	return pending
+}
+
+func TestCompleteRecurring(t *testing.T) {
+	tl := NewTodoList(NewMemoryTodoStore())
+	due := tomorrow()
+	todo := addTodo(t, tl, "Water plants", &due, "FREQ=DAILY")
+	step := addSubtask(t, tl, todo.ID, "Fill the can")
+	addSubtask(t, tl, todo.ID, "Check the soil")
+	toggle(t, tl, step.ID)
+
+	// Completed by hand with one subtask still open
+	toggle(t, tl, todo.ID)
+
+	if !todo.Completed || todo.IsRecurring() || todo.SeriesID != todo.ID {
+		t.Errorf("completed occurrence = %+v, want completed, not recurring, in its own series", *todo)
+	}
+
+	history := tl.Completions(todo.ID)
+	if len(history) != 1 || history[0].TodoID != todo.ID || !history[0].DueTime.Equal(due) {
+		t.Fatalf("completions = %+v, want one for todo %d due %v", history, todo.ID, due)
+	}
+
+	next := occurrences(tl, todo.ID)
+	if len(next) != 1 {
+		t.Fatalf("got %d pending occurrences, want 1", len(next))
+	}
+	spawned := next[0]
+	if spawned.Recurrence != "FREQ=DAILY" || spawned.SeriesID != todo.ID {
+		t.Errorf("spawned occurrence = %+v, want the daily rule in series %d", *spawned, todo.ID)
+	}
+	if want := due.AddDate(0, 0, 1); spawned.DueTime == nil || !spawned.DueTime.Equal(want) {
+		t.Errorf("spawned due = %v, want %v", spawned.DueTime, want)
+	}
+
+	checklist := tl.Subtasks(spawned.ID)
+	if len(checklist) != 2 || checklist[0].Title != "Fill the can" || checklist[0].Completed {
+		t.Errorf("spawned checklist = %+v, want both subtasks, none done", checklist)
+	}
+	if len(tl.Subtasks(todo.ID)) != 2 {
+		t.Errorf("the completed occurrence lost its checklist")
+	}
+}
+
+func TestReopenRecurring(t *testing.T) {
+	tl := NewTodoList(NewMemoryTodoStore())
+	due := tomorrow()
+	todo := addTodo(t, tl, "Stretch", &due, "FREQ=DAILY")
+	addSubtask(t, tl, todo.ID, "Neck")
+
+	toggle(t, tl, todo.ID)
+	spawned := occurrences(tl, todo.ID)[0]
+	toggle(t, tl, todo.ID)
+
+	if todo.Completed || todo.Recurrence != "FREQ=DAILY" {
+		t.Errorf("reopened todo = %+v, want pending with the rule back", *todo)
+	}
+	if tl.Get(spawned.ID) != nil || len(tl.Subtasks(spawned.ID)) != 0 {
+		t.Errorf("the untouched occurrence %d and its checklist are still there", spawned.ID)
+	}
+	if got := len(tl.Completions(todo.ID)); got != 0 {
+		t.Errorf("got %d completions after reopening, want 0", got)
+	}
+	if got := len(occurrences(tl, todo.ID)); got != 1 {
+		t.Errorf("got %d pending occurrences, want 1", got)
+	}
+}
+
+func TestReopenKeepsCompletedOccurrence(t *testing.T) {
+	tl := NewTodoList(NewMemoryTodoStore())
+	due := tomorrow()
+	// The last occurrence is done too, so nothing repeats any more
+	todo := addTodo(t, tl, "Stretch", &due, "FREQ=DAILY;COUNT=2")
+
+	toggle(t, tl, todo.ID)
+	spawned := occurrences(tl, todo.ID)[0]
+	toggle(t, tl, spawned.ID)
+	toggle(t, tl, todo.ID)
+
+	if tl.Get(spawned.ID) == nil {
+		t.Fatal("reopening dropped an occurrence that was already done")
+	}
+	if todo.IsRecurring() {
+		t.Error("the reopened todo took the rule back from a finished series")
+	}
+	if got := len(tl.Completions(todo.ID)); got != 1 {
+		t.Errorf("got %d completions, want only the later one", got)
+	}
+}
+
+func TestRecurringCountEndsSeries(t *testing.T) {
+	tl := NewTodoList(NewMemoryTodoStore())
+	due := tomorrow()
+	todo := addTodo(t, tl, "Physio", &due, "FREQ=DAILY;COUNT=2")
+
+	toggle(t, tl, todo.ID)
+	next := occurrences(tl, todo.ID)
+	if len(next) != 1 {
+		t.Fatalf("got %d pending occurrences after the first of 2, want 1", len(next))
+	}
+	toggle(t, tl, next[0].ID)
+
+	if got := occurrences(tl, todo.ID); len(got) != 0 {
+		t.Errorf("got %d pending occurrences after the last of 2, want none", len(got))
+	}
+	if got := len(tl.Completions(todo.ID)); got != 2 {
+		t.Errorf("got %d completions, want 2", got)
+	}
+}
+
+func TestCompleteRecurringParentWithLastSubtask(t *testing.T) {
+	tl := NewTodoList(NewMemoryTodoStore())
+	due := tomorrow()
+	todo := addTodo(t, tl, "Weekly review", &due, "FREQ=WEEKLY")
+	first := addSubtask(t, tl, todo.ID, "Inbox zero")
+	last := addSubtask(t, tl, todo.ID, "Plan the week")
+
+	toggle(t, tl, first.ID)
+	if todo.Completed {
+		t.Fatal("parent completed with a subtask still open")
+	}
+	toggle(t, tl, last.ID)
+
+	if !todo.Completed {
+		t.Fatal("parent not completed with its last subtask")
+	}
+	if got := len(tl.Completions(todo.ID)); got != 1 {
+		t.Errorf("got %d completions, want 1", got)
+	}
+	next := occurrences(tl, todo.ID)
+	if len(next) != 1 || len(tl.Subtasks(next[0].ID)) != 2 {
+		t.Errorf("want one next occurrence with both subtasks, got %d occurrences", len(next))
+	}
+}
+
+func TestAutoCompleteParents(t *testing.T) {
+	for _, auto := range []bool{true, false} {
+		tl := NewTodoList(NewMemoryTodoStore())
+		tl.AutoCompleteParents = auto
+		parent := addTodo(t, tl, "Pack", nil, "")
+		first := addSubtask(t, tl, parent.ID, "Passport")
+		last := addSubtask(t, tl, parent.ID, "Charger")
+
+		toggle(t, tl, first.ID)
+		if done, total := tl.Progress(parent.ID); done != 1 || total != 2 {
+			t.Errorf("progress = %d/%d, want 1/2", done, total)
+		}
+		toggle(t, tl, last.ID)
+		if parent.Completed != auto {
+			t.Errorf("AutoCompleteParents %v: parent completed = %v", auto, parent.Completed)
+		}
+
+		// Reopening a subtask leaves the parent as it is
+		toggle(t, tl, last.ID)
+		if parent.Completed != auto {
+			t.Errorf("AutoCompleteParents %v: reopening a subtask changed the parent", auto)
+		}
+	}
+}
+
+func TestRestoreTodo(t *testing.T) {
+	tl := NewTodoList(NewMemoryTodoStore())
+	parent := addTodo(t, tl, "Move house", nil, "")
+	earlier := addSubtask(t, tl, parent.ID, "Sell the sofa")
+	boxes := addSubtask(t, tl, parent.ID, "Buy boxes")
+	tape := addSubtask(t, tl, parent.ID, "Buy tape")
+
+	// One subtask goes on its own first, then the rest with their parent
+	if err := tl.Remove(earlier.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.Remove(parent.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(tl.Todos) != 0 || len(tl.Deleted) != 4 {
+		t.Fatalf("got %d live and %d trashed todos, want 0 and 4", len(tl.Todos), len(tl.Deleted))
+	}
+
+	// Restoring one subtask brings its parent back with the subtasks
+	// trashed along with it
+	if err := tl.Restore(tape.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, todo := range []*Todo{parent, boxes, tape} {
+		if tl.Get(todo.ID) == nil || !todo.DeletedAt.IsZero() {
+			t.Errorf("%q is not restored", todo.Title)
+		}
+	}
+	if tl.Get(earlier.ID) != nil || findTodo(tl.Deleted, earlier.ID) == nil {
+		t.Errorf("%q was trashed on its own and should stay in the trash", earlier.Title)
+	}
+}
+
+func TestPurgeTodo(t *testing.T) {
+	tl := NewTodoList(NewMemoryTodoStore())
+	due := tomorrow()
+	todo := addTodo(t, tl, "Stretch", &due, "FREQ=DAILY")
+	toggle(t, tl, todo.ID)
+
+	if err := tl.Remove(todo.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := tl.Purge(todo.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if findTodo(tl.Deleted, todo.ID) != nil {
+		t.Error("the purged todo is still in the trash")
+	}
+	history := tl.Completions(todo.ID)
+	if len(history) != 1 || history[0].TodoID != 0 {
+		t.Errorf("completions = %+v, want the one completion kept without its todo", history)
+	}
+	if err := tl.Restore(todo.ID); err == nil {
+		t.Error("restoring a purged todo didn't fail")
+	}
+}
+
+func TestPurgeTodoTrash(t *testing.T) {
+	store := NewMemoryTodoStore()
+	tl := NewTodoList(store)
+	old := addTodo(t, tl, "Old", nil, "")
+	addSubtask(t, tl, old.ID, "Old step")
+	if err := tl.Remove(old.ID); err != nil {
+		t.Fatal(err)
+	}
+	cutoff := time.Now()
+	recent := addTodo(t, tl, "Recent", nil, "")
+	if err := tl.Remove(recent.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tl.PurgeTrash(cutoff); err != nil {
+		t.Fatal(err)
+	}
+	if len(tl.Deleted) != 1 || tl.Deleted[0].ID != recent.ID {
+		t.Errorf("trash = %+v, want only the todo deleted after the cutoff", tl.Deleted)
+	}
+
+	// The store agrees
+	reloaded := NewTodoList(store)
+	if len(reloaded.Todos) != 0 || len(reloaded.Deleted) != 1 {
+		t.Errorf("reloaded %d live and %d trashed todos, want 0 and 1", len(reloaded.Todos), len(reloaded.Deleted))
+	}
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxViewsPerPage is how many saved views a page can bind to number keys
+const MaxViewsPerPage = 9
+
+// SavedView is a named search for one page: the search bar query, its quick
+// filter and the sort order
+type SavedView struct {
+	ID          int
+	Name        string
+	Page        PageType
+	Query       string
+	Filter      int    // quick filter cycled with Tab, 0 for none
+	Sort        string // sort order name, empty for the page's default
+	OnDashboard bool   // shown as a card on the dashboard
+	CreatedAt   time.Time
+}
+
+type ViewList struct {
+	store   ViewStore
+	Views   []*SavedView // oldest first, the order of their number keys
+	history *History     // records mutations for undo, nil if none
+}
+
+// Load - Load semua saved views dari store ke memory
+func (vl *ViewList) Load() error {
+	views, err := vl.store.LoadViews()
+	if err != nil {
+		return err
+	}
+	vl.Views = views
+	return nil
+}
+
+func NewViewList(store ViewStore) *ViewList {
+	vl := &ViewList{
+		store: store,
+		Views: []*SavedView{},
+	}
+	// Auto-load dari store saat inisialisasi
+	if err := vl.Load(); err != nil {
+		// Log error tapi tetap return instance kosong
+		fmt.Printf("Warning: failed to load saved views: %v\n", err)
+	}
+	return vl
+}
+
+// ForPage returns the views saved for page, numbered from 1 in this order
+func (vl *ViewList) ForPage(page PageType) []*SavedView {
+	views := []*SavedView{}
+	for _, view := range vl.Views {
+		if view.Page == page {
+			views = append(views, view)
+		}
+	}
+	return views
+}
+
+// OnDashboard returns the views shown as dashboard cards
+func (vl *ViewList) OnDashboard() []*SavedView {
+	views := []*SavedView{}
+	for _, view := range vl.Views {
+		if view.OnDashboard {
+			views = append(views, view)
+		}
+	}
+	return views
+}
+
+// Add - Simpan view baru ke store DAN memory sekaligus
+func (vl *ViewList) Add(name string, page PageType, query string, filter int, sort string) error {
+	if name == "" {
+		return errors.New("a saved view needs a name")
+	}
+	if len(vl.ForPage(page)) >= MaxViewsPerPage {
+		return fmt.Errorf("a page can have at most %d saved views", MaxViewsPerPage)
+	}
+	defer vl.history.track(describe("Save", "view", name))()
+
+	view := &SavedView{
+		Name:      name,
+		Page:      page,
+		Query:     query,
+		Filter:    filter,
+		Sort:      sort,
+		CreatedAt: time.Now(),
+	}
+	id, err := vl.store.InsertView(view)
+	if err != nil {
+		return err
+	}
+	view.ID = id
+	vl.Views = append(vl.Views, view)
+	return nil
+}
+
+// ToggleDashboard - Tampilkan/sembunyikan view sebagai card di dashboard
+func (vl *ViewList) ToggleDashboard(id int) error {
+	view := vl.find(id)
+	if view == nil {
+		return fmt.Errorf("saved view with id %d not found", id)
+	}
+
+	action := "Show on the dashboard"
+	if view.OnDashboard {
+		action = "Hide from the dashboard"
+	}
+	defer vl.history.track(describe(action, "view", view.Name))()
+
+	updated := *view
+	updated.OnDashboard = !view.OnDashboard
+	if err := vl.store.UpdateView(&updated); err != nil {
+		return err
+	}
+	*view = updated
+	return nil
+}
+
+// Remove - Hapus view dari store DAN memory
+func (vl *ViewList) Remove(id int) error {
+	if view := vl.find(id); view != nil {
+		defer vl.history.track(describe("Delete", "view", view.Name))()
+	}
+
+	if err := vl.store.DeleteView(id); err != nil {
+		return err
+	}
+	for i, view := range vl.Views {
+		if view.ID == id {
+			vl.Views = append(vl.Views[:i], vl.Views[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (vl *ViewList) find(id int) *SavedView {
+	for _, view := range vl.Views {
+		if view.ID == id {
+			return view
+		}
+	}
+	return nil
+}
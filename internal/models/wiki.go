@@ -0,0 +1,49 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// === Wiki links ===
+// A note links to another one by naming it in double brackets, e.g.
+// [[Project X]]. Saving a note creates empty stub notes for targets that
+// don't exist yet and indexes its wiki links in the LinkList, so they show
+// up as backlinks of the notes they point at.
+
+// WikiRef is one [[link]] in a note's text
+type WikiRef struct {
+	Title string // target title, trimmed
+	Start int    // byte offset of the opening [[
+	End   int    // byte offset just past the closing ]]
+}
+
+var wikiPattern = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+
+// ParseWikiLinks returns the [[links]] in content, in order
+func ParseWikiLinks(content string) []WikiRef {
+	refs := []WikiRef{}
+	for _, match := range wikiPattern.FindAllStringSubmatchIndex(content, -1) {
+		title := strings.TrimSpace(content[match[2]:match[3]])
+		if title == "" {
+			continue
+		}
+		refs = append(refs, WikiRef{Title: title, Start: match[0], End: match[1]})
+	}
+	return refs
+}
+
+// WikiTargets returns the titles content links to, each once (ignoring
+// case) in order of first appearance
+func WikiTargets(content string) []string {
+	seen := map[string]bool{}
+	titles := []string{}
+	for _, ref := range ParseWikiLinks(content) {
+		key := strings.ToLower(ref.Title)
+		if !seen[key] {
+			seen[key] = true
+			titles = append(titles, ref.Title)
+		}
+	}
+	return titles
+}
@@ -0,0 +1,136 @@
+// Package notefile converts notes to and from the Markdown files they are
+// edited as outside the app. The title and tags go in a front matter block:
+//
+//	---
+//	title: Standup notes
+//	tags: work, meeting
+//	---
+//
+//	The note itself...
+package notefile
+
+import (
+	"fmt"
+	"strings"
+
+	"prodBooster/internal/models"
+)
+
+const delimiter = "---"
+
+// ParseError explains why a note file can't be read back
+type ParseError struct {
+	Line int // 1-based line number the problem is on
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Format renders a note as a Markdown file with front matter
+func Format(note *models.Note) string {
+	var b strings.Builder
+	b.WriteString(delimiter + "\n")
+	b.WriteString("title: " + quote(note.Title) + "\n")
+	b.WriteString("tags: " + strings.Join(note.Tags, ", ") + "\n")
+	b.WriteString(delimiter + "\n\n")
+	b.WriteString(note.Content)
+	if note.Content != "" && !strings.HasSuffix(note.Content, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Parse reads a note file back into its title, tags and content. The blank
+// line after the front matter and trailing newlines are dropped, they are
+// added by Format and most editors.
+func Parse(text string) (title string, tags []string, content string, err error) {
+	if !hasFrontMatter(text) {
+		return "", nil, "", &ParseError{Line: 1, Msg: "the file must start with a --- front matter line"}
+	}
+	title, tags, content, err = parse(text)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if title == "" {
+		return "", nil, "", &ParseError{Line: 2, Msg: "the note needs a title"}
+	}
+	return title, tags, content, nil
+}
+
+// ParseOptional is Parse for files where the front matter and the title are
+// optional, like note templates. Without front matter the whole text is the
+// content.
+func ParseOptional(text string) (title string, tags []string, content string, err error) {
+	if !hasFrontMatter(text) {
+		return "", nil, strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n"), nil
+	}
+	return parse(text)
+}
+
+func hasFrontMatter(text string) bool {
+	first, _, _ := strings.Cut(text, "\n")
+	return strings.TrimSpace(first) == delimiter
+}
+
+// parse reads the front matter at the top of text and the content after it
+func parse(text string) (title string, tags []string, content string, err error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == delimiter {
+			end = i
+			break
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return "", nil, "", &ParseError{Line: i + 1, Msg: fmt.Sprintf("expected key: value, got %q", line)}
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			title = unquote(value)
+		case "tags":
+			tags = models.ParseTags(value)
+		default:
+			return "", nil, "", &ParseError{Line: i + 1, Msg: fmt.Sprintf("unknown field %q, only title and tags are allowed", strings.TrimSpace(key))}
+		}
+	}
+
+	if end < 0 {
+		return "", nil, "", &ParseError{Line: len(lines), Msg: "the front matter is never closed with ---"}
+	}
+
+	body := lines[end+1:]
+	if len(body) > 0 && strings.TrimSpace(body[0]) == "" {
+		body = body[1:]
+	}
+	return title, tags, strings.TrimRight(strings.Join(body, "\n"), "\n"), nil
+}
+
+// quote wraps a value in double quotes when reading it back would change
+// it otherwise: when it is quoted itself or has spaces around it
+func quote(value string) string {
+	if unquote(value) != value || strings.TrimSpace(value) != value {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// unquote strips the quotes YAML-minded editors may put around a value
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
@@ -0,0 +1,80 @@
+package notefile
+
+import (
+	"slices"
+	"testing"
+
+	"prodBooster/internal/models"
+)
+
+func TestRoundTrip(t *testing.T) {
+	tests := []models.Note{
+		{Title: "Standup notes", Tags: []string{"meeting", "work"}, Content: "The note itself"},
+		{Title: `"Foo"`, Content: "quoted title"},
+		{Title: `'Bar'`},
+		{Title: `"`},
+		{Title: `"Half`},
+		{Title: `It's "quoted" inside`},
+		{Title: "  Spaced  "},
+		{Title: "Title: with a colon"},
+		{Title: "Content", Content: "---\ntitle: not front matter\n---\n\n\nindented\n  last line"},
+	}
+
+	for _, note := range tests {
+		title, tags, content, err := Parse(Format(&note))
+		if err != nil {
+			t.Errorf("Parse(Format(%q)) failed: %v", note.Title, err)
+			continue
+		}
+		if title != note.Title {
+			t.Errorf("title %q read back as %q", note.Title, title)
+		}
+		if len(tags) > 0 || len(note.Tags) > 0 {
+			if !slices.Equal(tags, note.Tags) {
+				t.Errorf("tags of %q: got %v, want %v", note.Title, tags, note.Tags)
+			}
+		}
+		if content != note.Content {
+			t.Errorf("content of %q: got %q, want %q", note.Title, content, note.Content)
+		}
+	}
+}
+
+func TestParseQuotedTitle(t *testing.T) {
+	// Editors that write YAML quote titles themselves
+	for _, text := range []string{
+		"---\ntitle: \"Foo\"\n---\n",
+		"---\ntitle: 'Foo'\n---\n",
+		"---\ntitle: Foo\n---\n",
+	} {
+		title, _, _, err := Parse(text)
+		if err != nil || title != "Foo" {
+			t.Errorf("Parse(%q) = %q, %v, want Foo", text, title, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		line int
+	}{
+		{"no front matter", 1},
+		{"---\ntags: a\n---\n", 2},
+		{"---\ntitle: A\nauthor: me\n---\n", 3},
+		{"---\ntitle: A\njust text\n---\n", 3},
+		{"---\ntitle: A\n", 3},
+	}
+
+	for _, tt := range tests {
+		_, _, _, err := Parse(tt.text)
+		parseErr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("Parse(%q) = %v, want a ParseError", tt.text, err)
+			continue
+		}
+		if parseErr.Line != tt.line {
+			t.Errorf("Parse(%q) fails on line %d, want %d", tt.text, parseErr.Line, tt.line)
+		}
+	}
+}
@@ -0,0 +1,781 @@
+// Package query implements the search bar's query language:
+//
+//	priority:high due:<=fri -done tag:work "release notes"
+//
+// Terms next to each other must all match, OR between them lets either
+// match, a leading - negates a term and parentheses group. Besides plain
+// words, "phrases" and #tags a term can be a field, written name:value or
+// name:<op>value with op one of < <= > >= (dates and priorities only):
+//
+//	priority:high   p:>=medium    (todos)
+//	due:today       due:<=fri     due:none   (todos)
+//	start:>=mon     (events)
+//	created:>-7d    (todos, notes)
+//	tag:work        title:meeting   (everything)
+//	location:office location:none   (events)
+//	is:done is:overdue is:recurring is:subtask is:pinned
+//
+// Any other name: is searched as a plain word, so http://… and TODO: work.
+// done and overdue on their own are short for is:done and is:overdue. Dates
+// take anything dateparse understands and compare by day; quote values with
+// spaces (due:<="next fri").
+//
+// Parse turns a query into an AST of nodes. Words and phrases at the top
+// level are kept apart as Text, for the stores' full-text search to match
+// and rank; everything else is evaluated in memory by MatchTodo, MatchNote
+// and MatchEvent.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"prodBooster/internal/dateparse"
+	"prodBooster/internal/models"
+)
+
+// Kind is a set of item types, used to tell which fields apply to what
+type Kind int
+
+const (
+	Todos Kind = 1 << iota
+	Notes
+	Events
+
+	All = Todos | Notes | Events
+)
+
+// SyntaxError points at the part of a query that doesn't parse
+type SyntaxError struct {
+	Pos int // rune offset into the query
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos+1, e.Msg)
+}
+
+// Query is a parsed query. The zero Query matches everything.
+type Query struct {
+	root node // filter evaluated in memory, nil matches everything
+	text []models.SearchTerm
+	now  time.Time
+}
+
+// Text returns the top-level words and phrases as a query for the stores'
+// Search methods, empty when there are none
+func (q *Query) Text() string {
+	parts := make([]string, len(q.text))
+	for i, term := range q.text {
+		if term.Phrase {
+			parts[i] = `"` + term.Text + `"`
+		} else {
+			parts[i] = term.Text
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// MatchTodo evaluates everything but Text against a todo
+func (q *Query) MatchTodo(todo *models.Todo) bool {
+	return q.match(&item{
+		kind:      Todos,
+		fields:    []string{todo.Title, todo.Description},
+		tags:      todo.Tags,
+		priority:  todo.Priority,
+		due:       todo.DueTime,
+		created:   &todo.CreatedAt,
+		done:      todo.Completed,
+		recurring: todo.IsRecurring(),
+		subtask:   todo.IsSubtask(),
+		pinned:    todo.Pinned,
+	})
+}
+
+// MatchNote evaluates everything but Text against a note
+func (q *Query) MatchNote(note *models.Note) bool {
+	return q.match(&item{
+		kind:    Notes,
+		fields:  []string{note.Title, note.Content},
+		tags:    note.Tags,
+		created: &note.CreatedAt,
+		pinned:  note.Pinned,
+	})
+}
+
+// MatchEvent evaluates everything but Text against an event
+func (q *Query) MatchEvent(event *models.Event) bool {
+	return q.match(&item{
+		kind:      Events,
+		fields:    []string{event.Title, event.Content, event.Location},
+		tags:      event.Tags,
+		start:     &event.StartTime,
+		location:  event.Location,
+		recurring: event.IsRecurring(),
+		pinned:    event.Pinned,
+	})
+}
+
+func (q *Query) match(it *item) bool {
+	if q == nil || q.root == nil {
+		return true
+	}
+	it.now = q.now
+	return q.root.match(it)
+}
+
+// item is the view of a todo, note or event the nodes evaluate against
+type item struct {
+	kind      Kind
+	fields    []string // searchable text, title first
+	tags      []string
+	priority  models.Priority
+	due       *time.Time
+	start     *time.Time
+	created   *time.Time
+	location  string
+	done      bool
+	recurring bool
+	subtask   bool
+	pinned    bool
+	now       time.Time
+}
+
+// === AST ===
+
+type node interface {
+	match(it *item) bool
+}
+
+type andNode []node
+
+func (n andNode) match(it *item) bool {
+	for _, child := range n {
+		if !child.match(it) {
+			return false
+		}
+	}
+	return true
+}
+
+type orNode []node
+
+func (n orNode) match(it *item) bool {
+	for _, child := range n {
+		if child.match(it) {
+			return true
+		}
+	}
+	return false
+}
+
+type notNode struct{ node }
+
+func (n notNode) match(it *item) bool {
+	return !n.node.match(it)
+}
+
+// textNode is a word or phrase, matched anywhere in the item's text
+type textNode []models.SearchTerm
+
+func (n textNode) match(it *item) bool {
+	_, ok := models.MatchSearch(n, it.fields...)
+	return ok
+}
+
+// titleNode matches words in the title only
+type titleNode []models.SearchTerm
+
+func (n titleNode) match(it *item) bool {
+	_, ok := models.MatchSearch(n, it.fields[0])
+	return ok
+}
+
+type tagNode string
+
+func (n tagNode) match(it *item) bool {
+	return models.HasTags(it.tags, []string{string(n)})
+}
+
+// locationNode matches words in an event's location, or events without one
+// when terms is empty
+type locationNode []models.SearchTerm
+
+func (n locationNode) match(it *item) bool {
+	if it.kind != Events {
+		return false
+	}
+	if len(n) == 0 {
+		return strings.TrimSpace(it.location) == ""
+	}
+	_, ok := models.MatchSearch(n, it.location)
+	return ok
+}
+
+type op int
+
+const (
+	opEq op = iota
+	opLt
+	opLe
+	opGt
+	opGe
+)
+
+var ops = []struct {
+	text string
+	op   op
+}{{"<=", opLe}, {">=", opGe}, {"<", opLt}, {">", opGt}, {"=", opEq}}
+
+func (o op) compare(diff int) bool {
+	switch o {
+	case opLt:
+		return diff < 0
+	case opLe:
+		return diff <= 0
+	case opGt:
+		return diff > 0
+	case opGe:
+		return diff >= 0
+	}
+	return diff == 0
+}
+
+type priorityNode struct {
+	op       op
+	priority models.Priority
+}
+
+func (n priorityNode) match(it *item) bool {
+	return it.kind == Todos && n.op.compare(int(it.priority)-int(n.priority))
+}
+
+// dateNode compares a date field by day, or matches items without the date
+// when none is set
+type dateNode struct {
+	field string
+	op    op
+	day   time.Time // midnight
+	none  bool
+}
+
+func (n dateNode) match(it *item) bool {
+	if fieldsByName[n.field].kinds&it.kind == 0 {
+		return false
+	}
+	var t *time.Time
+	switch n.field {
+	case "due":
+		t = it.due
+	case "start":
+		t = it.start
+	case "created":
+		t = it.created
+	}
+	if n.none || t == nil {
+		return n.none && t == nil
+	}
+
+	diff := 0
+	if t.Before(n.day) {
+		diff = -1
+	} else if !t.Before(n.day.AddDate(0, 0, 1)) {
+		diff = 1
+	}
+	return n.op.compare(diff)
+}
+
+// isNode is a yes/no property
+type isNode string
+
+func (n isNode) match(it *item) bool {
+	switch n {
+	case "done":
+		return it.kind == Todos && it.done
+	case "overdue":
+		return it.kind == Todos && !it.done && it.due != nil && it.due.Before(it.now)
+	case "recurring":
+		return it.recurring
+	case "subtask":
+		return it.subtask
+	case "pinned":
+		return it.pinned
+	}
+	return false
+}
+
+// === Fields ===
+
+type field struct {
+	name    string
+	alias   string
+	kinds   Kind
+	ordered bool     // takes < <= > >=
+	values  []string // offered as completions
+}
+
+var fieldList = []field{
+	{name: "priority", alias: "p", kinds: Todos, ordered: true, values: []string{"high", "medium", "low"}},
+	{name: "due", kinds: Todos, ordered: true, values: []string{"today", "tomorrow", "none"}},
+	{name: "start", kinds: Events, ordered: true, values: []string{"today", "tomorrow"}},
+	{name: "created", kinds: Todos | Notes, ordered: true, values: []string{"today", "-7d"}},
+	{name: "tag", kinds: All},
+	{name: "title", kinds: All},
+	{name: "location", alias: "loc", kinds: Events, values: []string{"none"}},
+	{name: "is", kinds: All, values: []string{"done", "overdue", "recurring", "subtask", "pinned"}},
+}
+
+var fieldsByName = func() map[string]field {
+	byName := map[string]field{}
+	for _, f := range fieldList {
+		byName[f.name] = f
+		if f.alias != "" {
+			byName[f.alias] = f
+		}
+	}
+	return byName
+}()
+
+// FieldsFor lists the names of the fields that apply to kind
+func FieldsFor(kind Kind) []string {
+	var names []string
+	for _, f := range fieldList {
+		if f.kinds&kind != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// keywords are bare words that stand for an is: value
+var keywords = map[string]string{"done": "done", "overdue": "overdue"}
+
+// === Lexer ===
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokPhrase
+	tokField
+	tokOpen
+	tokClose
+	tokNot
+	tokOr
+	tokEOF
+)
+
+type token struct {
+	kind  tokenKind
+	text  string // word, phrase or field name
+	value string // field value, op included
+	pos   int    // byte offset
+}
+
+var fieldRe = regexp.MustCompile(`^([A-Za-z]+):`)
+
+// isField reports whether name is a field, so that words like http: and
+// TODO: stay plain text
+func isField(name string) bool {
+	_, ok := fieldsByName[strings.ToLower(name)]
+	return ok
+}
+
+func lex(input string) []token {
+	var tokens []token
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, token{kind: tokOpen, pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokClose, pos: i})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokNot, pos: i})
+			i++
+		case r == '"':
+			// An unterminated quote runs to the end, so a phrase still
+			// matches while it is being typed
+			text, end := quoted(input, i)
+			tokens = append(tokens, token{kind: tokPhrase, text: text, pos: i})
+			i = end
+		default:
+			start := i
+			if m := fieldRe.FindStringSubmatch(input[i:]); m != nil && isField(m[1]) {
+				i += len(m[0])
+				valueStart := i
+				for _, o := range ops {
+					if strings.HasPrefix(input[i:], o.text) {
+						i += len(o.text)
+						break
+					}
+				}
+				value := input[valueStart:i]
+				if i < len(input) && input[i] == '"' {
+					text, end := quoted(input, i)
+					value += text
+					i = end
+				} else {
+					end := wordEnd(input, i)
+					value += input[i:end]
+					i = end
+				}
+				tokens = append(tokens, token{kind: tokField, text: strings.ToLower(m[1]), value: value, pos: start})
+				continue
+			}
+
+			i = wordEnd(input, i)
+			kind := tokWord
+			if input[start:i] == "OR" {
+				kind = tokOr
+			}
+			tokens = append(tokens, token{kind: kind, text: input[start:i], pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(input)})
+}
+
+// quoted reads the quoted string starting at input[i], returning its text
+// and the offset just past the closing quote
+func quoted(input string, i int) (string, int) {
+	end := strings.IndexByte(input[i+1:], '"')
+	if end < 0 {
+		return input[i+1:], len(input)
+	}
+	return input[i+1 : i+1+end], i + end + 2
+}
+
+func wordEnd(input string, i int) int {
+	end := strings.IndexFunc(input[i:], func(r rune) bool {
+		return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+	})
+	if end < 0 {
+		return len(input)
+	}
+	return i + end
+}
+
+// === Parser ===
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+	now    time.Time
+}
+
+// Parse parses a query, resolving relative dates against now
+func Parse(input string, now time.Time) (*Query, error) {
+	p := &parser{input: input, tokens: lex(input), now: now}
+	if p.peek().kind == tokEOF {
+		return &Query{now: now}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorAt(tok, "unexpected )")
+	}
+
+	q := &Query{now: now}
+
+	// Top-level words and phrases go to full-text search
+	conjuncts := []node{root}
+	if and, ok := root.(andNode); ok {
+		conjuncts = and
+	}
+	var rest andNode
+	for _, n := range conjuncts {
+		if text, ok := n.(textNode); ok {
+			q.text = append(q.text, text...)
+		} else {
+			rest = append(rest, n)
+		}
+	}
+	switch len(rest) {
+	case 0:
+	case 1:
+		q.root = rest[0]
+	default:
+		q.root = rest
+	}
+	return q, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) errorAt(tok token, format string, args ...any) *SyntaxError {
+	return &SyntaxError{Pos: utf8.RuneCountInString(p.input[:tok.pos]), Msg: fmt.Sprintf(format, args...)}
+}
+
+// endsTerm reports whether tok can't start a term
+func endsTerm(tok token) bool {
+	return tok.kind == tokEOF || tok.kind == tokClose || tok.kind == tokOr
+}
+
+func (p *parser) parseOr() (node, error) {
+	var either orNode
+	for {
+		if tok := p.peek(); endsTerm(tok) {
+			if tok.kind == tokClose && len(either) == 0 {
+				return nil, p.errorAt(tok, "unexpected )")
+			}
+			return nil, p.errorAt(tok, "OR needs a term on both sides")
+		}
+		and, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		either = append(either, and)
+
+		if p.peek().kind != tokOr {
+			break
+		}
+		p.next()
+	}
+	if len(either) == 1 {
+		return either[0], nil
+	}
+	return either, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	var all andNode
+	for !endsTerm(p.peek()) {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			all = append(all, n)
+		}
+	}
+	if len(all) == 1 {
+		return all[0], nil
+	}
+	return all, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind != tokNot {
+		return p.parsePrimary()
+	}
+
+	not := p.next()
+	if endsTerm(p.peek()) {
+		return nil, p.errorAt(not, "nothing to exclude after -")
+	}
+	n, err := p.parseUnary()
+	if err != nil || n == nil {
+		return n, err
+	}
+	return notNode{n}, nil
+}
+
+// parsePrimary parses one term. It returns a nil node for terms that match
+// everything, like "" or a word of punctuation only.
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokOpen:
+		if p.peek().kind == tokClose {
+			return nil, p.errorAt(tok, "empty ( )")
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokClose {
+			return nil, p.errorAt(tok, "missing ) for this (")
+		}
+		p.next()
+		return n, nil
+
+	case tokPhrase:
+		if terms := models.ParseSearch(`"` + tok.text + `"`); len(terms) > 0 {
+			return textNode(terms), nil
+		}
+		return nil, nil
+
+	case tokField:
+		return p.parseField(tok)
+
+	case tokWord:
+		if strings.HasPrefix(tok.text, "#") {
+			tag := models.NormalizeTag(tok.text)
+			if tag == "" {
+				return nil, p.errorAt(tok, "# needs a tag name")
+			}
+			return tagNode(tag), nil
+		}
+		if is, ok := keywords[strings.ToLower(tok.text)]; ok {
+			return isNode(is), nil
+		}
+		if terms := models.ParseSearch(tok.text); len(terms) > 0 {
+			return textNode(terms), nil
+		}
+		return nil, nil
+	}
+	return nil, p.errorAt(tok, "unexpected token")
+}
+
+func (p *parser) parseField(tok token) (node, error) {
+	f := fieldsByName[tok.text] // lex only makes tokFields of known names
+
+	o, value := opEq, tok.value
+	for _, candidate := range ops {
+		if strings.HasPrefix(value, candidate.text) {
+			o, value = candidate.op, value[len(candidate.text):]
+			break
+		}
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, p.errorAt(tok, "%s: needs a value", f.name)
+	}
+	if o != opEq && !f.ordered {
+		return nil, p.errorAt(tok, "%s: can't be compared with < or >", f.name)
+	}
+	lower := strings.ToLower(value)
+
+	switch f.name {
+	case "priority":
+		priority, ok := map[string]models.Priority{
+			"high": models.PriorityHigh, "h": models.PriorityHigh,
+			"medium": models.PriorityMedium, "med": models.PriorityMedium, "m": models.PriorityMedium,
+			"low": models.PriorityLow, "l": models.PriorityLow,
+		}[lower]
+		if !ok {
+			return nil, p.errorAt(tok, "priority: must be high, medium or low")
+		}
+		return priorityNode{op: o, priority: priority}, nil
+
+	case "due", "start", "created":
+		if lower == "none" {
+			if o != opEq {
+				return nil, p.errorAt(tok, "%s:none can't be compared", f.name)
+			}
+			return dateNode{field: f.name, none: true}, nil
+		}
+		t, err := dateparse.Parse(value, p.now, 0)
+		if err != nil {
+			return nil, p.errorAt(tok, "%s: %v", f.name, err)
+		}
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return dateNode{field: f.name, op: o, day: day}, nil
+
+	case "tag":
+		tag := models.NormalizeTag(value)
+		if tag == "" {
+			return nil, p.errorAt(tok, "tag: needs a tag name")
+		}
+		return tagNode(tag), nil
+
+	case "title":
+		return titleNode(models.ParseSearch(`"` + value + `"`)), nil
+
+	case "location":
+		if lower == "none" {
+			return locationNode(nil), nil
+		}
+		return locationNode(models.ParseSearch(`"` + value + `"`)), nil
+
+	case "is":
+		for _, v := range f.values {
+			if lower == v {
+				return isNode(v), nil
+			}
+		}
+		return nil, p.errorAt(tok, "is: must be %s", strings.Join(f.values, ", "))
+	}
+	return nil, p.errorAt(tok, "unknown field %q", tok.text)
+}
+
+// === Completion ===
+
+// Complete suggests how to finish word, the last word of a query being
+// typed: field names and values that apply to kind, and tags after # or
+// tag:. Each suggestion replaces the whole word.
+func Complete(word string, kind Kind, tags []string) []string {
+	prefix := ""
+	for strings.HasPrefix(word, "-") || strings.HasPrefix(word, "(") {
+		prefix, word = prefix+word[:1], word[1:]
+	}
+
+	var candidates []string
+	switch m := fieldRe.FindStringSubmatch(word); {
+	case strings.HasPrefix(word, "#"):
+		for _, tag := range tags {
+			candidates = append(candidates, "#"+tag)
+		}
+
+	case m != nil:
+		f, ok := fieldsByName[strings.ToLower(m[1])]
+		if !ok || f.kinds&kind == 0 {
+			return nil
+		}
+		head := m[0]
+		for _, o := range ops {
+			if strings.HasPrefix(word[len(head):], o.text) {
+				head += o.text
+				break
+			}
+		}
+		values := f.values
+		if f.name == "tag" {
+			values = tags
+		}
+		for _, v := range values {
+			candidates = append(candidates, head+v)
+		}
+
+	case word != "":
+		for _, f := range fieldList {
+			if f.kinds&kind != 0 {
+				candidates = append(candidates, f.name+":")
+			}
+		}
+		if kind&Todos != 0 {
+			for keyword := range keywords {
+				candidates = append(candidates, keyword)
+			}
+		}
+	}
+
+	var suggestions []string
+	lower := strings.ToLower(word)
+	for _, c := range candidates {
+		if strings.HasPrefix(strings.ToLower(c), lower) && len(c) > len(word) {
+			suggestions = append(suggestions, prefix+c)
+		}
+	}
+	// Shortest first, it is what Tab picks
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		if len(suggestions[i]) != len(suggestions[j]) {
+			return len(suggestions[i]) < len(suggestions[j])
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	return suggestions
+}
@@ -0,0 +1,247 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"prodBooster/internal/models"
+)
+
+// Wednesday
+var now = time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 9, 0, 0, 0, time.UTC)
+}
+
+func todos() []*models.Todo {
+	reportDue, rentDue := day(10, 15), day(10, 10)
+	return []*models.Todo{
+		{ID: 1, Title: "Quarterly report", Description: "draft for the board", Tags: []string{"work"},
+			Priority: models.PriorityHigh, DueTime: &reportDue, CreatedAt: day(10, 1)},
+		{ID: 2, Title: "Buy milk", Tags: []string{"home"},
+			Priority: models.PriorityLow, Completed: true, CreatedAt: day(10, 13)},
+		{ID: 3, Title: "Pay rent", Tags: []string{"home"}, Recurrence: "FREQ=MONTHLY", Pinned: true,
+			Priority: models.PriorityMedium, DueTime: &rentDue, CreatedAt: day(9, 1)},
+		{ID: 4, Title: "Read http://example.com/docs", Description: "TODO: summarise", ParentID: 1,
+			Priority: models.PriorityMedium, CreatedAt: day(10, 14)},
+	}
+}
+
+// matching returns the titles of the todos q matches, applying Text the
+// way the stores' Search does
+func matching(q *Query) []string {
+	terms := models.ParseSearch(q.Text())
+	var titles []string
+	for _, todo := range todos() {
+		if _, ok := models.MatchSearch(terms, todo.Title, todo.Description); ok && q.MatchTodo(todo) {
+			titles = append(titles, todo.Title)
+		}
+	}
+	return titles
+}
+
+func TestMatchTodo(t *testing.T) {
+	const (
+		report = "Quarterly report"
+		milk   = "Buy milk"
+		rent   = "Pay rent"
+		read   = "Read http://example.com/docs"
+	)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{report, milk, rent, read}},
+
+		// Fields
+		{"priority:high", []string{report}},
+		{"p:>=medium", []string{report, rent, read}},
+		{"P:<MEDIUM", []string{milk}},
+		{"tag:home", []string{milk, rent}},
+		{"#home", []string{milk, rent}},
+		{"title:rent", []string{rent}},
+		{"is:recurring", []string{rent}},
+		{"is:subtask", []string{read}},
+		{"is:pinned", []string{rent}},
+
+		// Keywords
+		{"done", []string{milk}},
+		{"overdue", []string{rent}},
+		{"is:overdue", []string{rent}},
+
+		// Dates
+		{"due:today", nil},
+		{"due:tomorrow", []string{report}},
+		{"due:<=fri", []string{report, rent}},
+		{`due:<"oct 20"`, []string{report, rent}},
+		{"due:none", []string{milk, read}},
+		{"created:>-7d", []string{milk, read}},
+		{"created:<=2026-10-01", []string{report, rent}},
+
+		// Negation
+		{"-done", []string{report, rent, read}},
+		{"--done", []string{milk}},
+		{"tag:home -done", []string{rent}},
+		{"-due:none", []string{report, rent}},
+		{"-(tag:home OR done)", []string{report, read}},
+
+		// Precedence: AND binds tighter than OR
+		{"tag:home OR priority:high -done", []string{report, milk, rent}},
+		{"(tag:home OR priority:high) -done", []string{report, rent}},
+		{"done OR overdue OR is:subtask", []string{milk, rent, read}},
+
+		// Words and quoting
+		{"rent", []string{rent}},
+		{"board", []string{report}},
+		{`"quarterly report"`, []string{report}},
+		{`"report quarterly"`, nil},
+		{`title:"pay rent"`, []string{rent}},
+		{`title:milk OR "pay rent"`, []string{milk, rent}},
+		{`"pay`, []string{rent}}, // unterminated while typing
+
+		// Unknown names are plain words
+		{"http://example.com", []string{read}},
+		{"TODO:", []string{read}},
+		{"TODO: OR done", []string{milk, read}},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query, now)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.query, err)
+			continue
+		}
+		if got := matching(q); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) matches %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatchKinds(t *testing.T) {
+	note := &models.Note{Title: "Standup", Content: "notes", Tags: []string{"work"}, CreatedAt: day(10, 14)}
+	event := &models.Event{Title: "Standup", Location: "Room 1", StartTime: day(10, 14)}
+
+	tests := []struct {
+		query       string
+		note, event bool
+	}{
+		{"tag:work", true, false},
+		{"title:standup", true, true},
+		{"priority:high", false, false},
+		{"-priority:high", true, true},
+		{"start:today", false, true},
+		{"created:today", true, false},
+		{"location:room", false, true},
+		{"location:none", false, false},
+		{"done", false, false},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query, now)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.query, err)
+			continue
+		}
+		if got := q.MatchNote(note); got != tt.note {
+			t.Errorf("Parse(%q).MatchNote = %v, want %v", tt.query, got, tt.note)
+		}
+		if got := q.MatchEvent(event); got != tt.event {
+			t.Errorf("Parse(%q).MatchEvent = %v, want %v", tt.query, got, tt.event)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", ""},
+		{`priority:high "release notes" draft`, `"release notes" draft`},
+		{"Draft-2", "draft 2"},
+		{"tag:work (draft OR final)", ""},
+		{"-draft", ""},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query, now)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.query, err)
+			continue
+		}
+		if got := q.Text(); got != tt.want {
+			t.Errorf("Parse(%q).Text() = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string // prefix
+	}{
+		{")", 0, "unexpected )"},
+		{"done)", 4, "unexpected )"},
+		{"()", 0, "empty ( )"},
+		{"x (done", 2, "missing ) for this ("},
+		{"OR done", 0, "OR needs a term on both sides"},
+		{"done OR", 7, "OR needs a term on both sides"},
+		{"-", 0, "nothing to exclude after -"},
+		{"#", 0, "# needs a tag name"},
+		{"priority:urgent", 0, "priority: must be high, medium or low"},
+		{"due:", 0, "due: needs a value"},
+		{"tag:<work", 0, "tag: can't be compared with < or >"},
+		{"due:>none", 0, "due:none can't be compared"},
+		{"due:someday", 0, "due: "},
+		{"due:<oct 20", 0, "due: "}, // unquoted, the value ends at the space
+		{"is:late", 0, "is: must be done, overdue"},
+		{"é is:late", 2, "is: must be"}, // counted in runes
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.query, now)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) error = %v, want a SyntaxError", tt.query, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos || !strings.HasPrefix(syntaxErr.Msg, tt.msg) {
+			t.Errorf("Parse(%q) error = col %d %q, want col %d %q…", tt.query, syntaxErr.Pos+1, syntaxErr.Msg, tt.pos+1, tt.msg)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	tags := []string{"work", "home", "writing"}
+
+	tests := []struct {
+		word string
+		kind Kind
+		want []string
+	}{
+		{"", All, nil},
+		{"pri", Todos, []string{"priority:"}},
+		{"pri", Notes, nil},
+		{"d", Todos, []string{"done", "due:"}},
+		{"d", Events, nil},
+		{"p:", Todos, []string{"p:low", "p:high", "p:medium"}},
+		{"-due:<=to", Todos, []string{"-due:<=today", "-due:<=tomorrow"}},
+		{"(is:o", Todos, []string{"(is:overdue"}},
+		{"#w", All, []string{"#work", "#writing"}},
+		{"tag:h", All, []string{"tag:home"}},
+		{"start:", Todos, nil},
+		{"http:", All, nil},
+	}
+
+	for _, tt := range tests {
+		if got := Complete(tt.word, tt.kind, tags); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
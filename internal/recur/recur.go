@@ -0,0 +1,493 @@
+// Package recur implements the subset of iCalendar RRULEs the app needs for
+// repeating todos and events: daily, weekly (optionally on given weekdays),
+// monthly on a day of the month and yearly, each with an interval and an
+// optional UNTIL or COUNT.
+package recur
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Freq int
+
+const (
+	Daily Freq = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+func (f Freq) String() string {
+	switch f {
+	case Daily:
+		return "DAILY"
+	case Weekly:
+		return "WEEKLY"
+	case Monthly:
+		return "MONTHLY"
+	case Yearly:
+		return "YEARLY"
+	}
+	return "UNKNOWN"
+}
+
+// Rule is a parsed recurrence rule. Occurrences are anchored on a start time
+// (the todo's due date or the event's start) which supplies the time of day
+// and any field the rule leaves out.
+type Rule struct {
+	Freq       Freq
+	Interval   int            // every N days/weeks/months/years, at least 1
+	ByDay      []time.Weekday // weekly only, empty means the start's weekday
+	ByMonthDay int            // monthly only, 0 means the start's day, -1 the last day
+	Until      time.Time      // zero means no end
+	UntilDate  bool           // UNTIL named a day, which ends in the series' own time zone
+	Count      int            // 0 means no limit
+}
+
+// maxIterations bounds expansion so a bad rule can't hang the UI
+const maxIterations = 10000
+
+// ErrTooManyOccurrences is returned when the dates asked for lie further
+// into a series than expansion goes
+var ErrTooManyOccurrences = fmt.Errorf("repeats over %d times before the dates asked for", maxIterations)
+
+var (
+	everyRe     = regexp.MustCompile(`^every (\d+) (day|days|week|weeks|month|months|year|years)$`)
+	monthlyOnRe = regexp.MustCompile(`^(?:monthly|every month) on (?:the )?(\d{1,2}|last)(?:st|nd|rd|th)?(?: day)?$`)
+	everyDaysRe = regexp.MustCompile(`^(?:every|weekly on) ([a-z, ]+)$`)
+)
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Parse reads either a friendly phrase or an RRULE:
+//
+//	daily, weekdays, weekly, monthly, yearly
+//	every 3 days, every 2 weeks, every mon, wed
+//	monthly on 15, every month on the last day
+//	FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR
+func Parse(input string) (Rule, error) {
+	s := strings.ToLower(strings.Join(strings.Fields(input), " "))
+	if s == "" {
+		return Rule{}, errors.New("no recurrence given")
+	}
+
+	if strings.Contains(s, "freq=") {
+		return parseRRule(s)
+	}
+
+	switch s {
+	case "daily", "every day":
+		return Rule{Freq: Daily, Interval: 1}, nil
+	case "weekdays", "every weekday":
+		return Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{
+			time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday,
+		}}, nil
+	case "weekends", "every weekend":
+		return Rule{Freq: Weekly, Interval: 1, ByDay: []time.Weekday{time.Saturday, time.Sunday}}, nil
+	case "weekly", "every week":
+		return Rule{Freq: Weekly, Interval: 1}, nil
+	case "biweekly", "fortnightly":
+		return Rule{Freq: Weekly, Interval: 2}, nil
+	case "monthly", "every month":
+		return Rule{Freq: Monthly, Interval: 1}, nil
+	case "yearly", "annually", "every year":
+		return Rule{Freq: Yearly, Interval: 1}, nil
+	}
+
+	if m := everyRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if n < 1 {
+			return Rule{}, errors.New("interval must be at least 1")
+		}
+		rule := Rule{Interval: n}
+		switch strings.TrimSuffix(m[2], "s") {
+		case "day":
+			rule.Freq = Daily
+		case "week":
+			rule.Freq = Weekly
+		case "month":
+			rule.Freq = Monthly
+		case "year":
+			rule.Freq = Yearly
+		}
+		return rule, nil
+	}
+
+	if m := monthlyOnRe.FindStringSubmatch(s); m != nil {
+		day := -1
+		if m[1] != "last" {
+			day, _ = strconv.Atoi(m[1])
+			if day < 1 || day > 31 {
+				return Rule{}, fmt.Errorf("%d is not a day of the month", day)
+			}
+		}
+		return Rule{Freq: Monthly, Interval: 1, ByMonthDay: day}, nil
+	}
+
+	if m := everyDaysRe.FindStringSubmatch(s); m != nil {
+		days, err := parseWeekdays(strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' }))
+		if err == nil {
+			return Rule{Freq: Weekly, Interval: 1, ByDay: days}, nil
+		}
+	}
+
+	return Rule{}, fmt.Errorf("don't know how to repeat %q", input)
+}
+
+func parseWeekdays(names []string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range names {
+		if name == "and" {
+			continue
+		}
+		wd, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		days = append(days, wd)
+	}
+	if len(days) == 0 {
+		return nil, errors.New("no weekdays given")
+	}
+	return days, nil
+}
+
+func parseRRule(s string) (Rule, error) {
+	s = strings.TrimPrefix(s, "rrule:")
+	rule := Rule{Interval: 1}
+	hasFreq := false
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("bad RRULE part %q", part)
+		}
+
+		switch key {
+		case "freq":
+			hasFreq = true
+			switch value {
+			case "daily":
+				rule.Freq = Daily
+			case "weekly":
+				rule.Freq = Weekly
+			case "monthly":
+				rule.Freq = Monthly
+			case "yearly":
+				rule.Freq = Yearly
+			default:
+				return Rule{}, fmt.Errorf("unsupported FREQ %q", strings.ToUpper(value))
+			}
+		case "interval":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("bad INTERVAL %q", value)
+			}
+			rule.Interval = n
+		case "byday":
+			for _, code := range strings.Split(strings.ToUpper(value), ",") {
+				idx := indexOf(weekdayCodes, code)
+				if idx < 0 {
+					return Rule{}, fmt.Errorf("unsupported BYDAY %q", code)
+				}
+				rule.ByDay = append(rule.ByDay, time.Weekday(idx))
+			}
+		case "bymonthday":
+			n, err := strconv.Atoi(value)
+			if err != nil || n == 0 || n < -1 || n > 31 {
+				return Rule{}, fmt.Errorf("unsupported BYMONTHDAY %q", value)
+			}
+			rule.ByMonthDay = n
+		case "until":
+			until, date, err := parseUntil(value)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Until, rule.UntilDate = until, date
+		case "count":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, fmt.Errorf("bad COUNT %q", value)
+			}
+			rule.Count = n
+		default:
+			return Rule{}, fmt.Errorf("unsupported RRULE part %q", strings.ToUpper(key))
+		}
+	}
+
+	if !hasFreq {
+		return Rule{}, errors.New("RRULE needs a FREQ")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return Rule{}, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if rule.ByMonthDay != 0 && rule.Freq != Monthly {
+		return Rule{}, errors.New("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return rule, nil
+}
+
+// parseUntil reads an UNTIL value, date reports whether it is a day only
+func parseUntil(value string) (until time.Time, date bool, err error) {
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+	for _, layout := range []string{"20060102t150405z", "20060102t150405"} {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("bad UNTIL %q", value)
+}
+
+// End returns the last moment an occurrence may start at for a series in
+// loc, zero if the rule has no UNTIL. A day-only UNTIL includes the whole
+// day there.
+func (r Rule) End(loc *time.Location) time.Time {
+	if !r.UntilDate {
+		return r.Until
+	}
+	return time.Date(r.Until.Year(), r.Until.Month(), r.Until.Day()+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+}
+
+// String renders the rule as an RRULE value (without the "RRULE:" prefix),
+// which is how it is stored
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			codes[i] = weekdayCodes[wd]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, fmt.Sprintf("BYMONTHDAY=%d", r.ByMonthDay))
+	}
+	switch {
+	case r.UntilDate:
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	case !r.Until.IsZero():
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Describe renders the rule for people: "every 2 weeks on Mon, Fri"
+func (r Rule) Describe() string {
+	units := map[Freq]string{Daily: "day", Weekly: "week", Monthly: "month", Yearly: "year"}
+
+	desc := "every " + units[r.Freq]
+	if r.Interval > 1 {
+		desc = fmt.Sprintf("every %d %ss", r.Interval, units[r.Freq])
+	}
+	if r.Freq == Weekly && r.Interval == 1 && isWeekdays(r.ByDay) {
+		desc = "every weekday"
+	} else if len(r.ByDay) > 0 {
+		names := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			names[i] = wd.String()[:3]
+		}
+		desc += " on " + strings.Join(names, ", ")
+	}
+	switch {
+	case r.ByMonthDay == -1:
+		desc += " on the last day"
+	case r.ByMonthDay > 0:
+		desc += " on the " + ordinal(r.ByMonthDay)
+	}
+	switch {
+	case r.UntilDate:
+		desc += " until " + r.Until.Format("Jan 2, 2006")
+	case !r.Until.IsZero():
+		desc += " until " + r.Until.Local().Format("Jan 2, 2006")
+	}
+	if r.Count > 0 {
+		desc += fmt.Sprintf(", %d times", r.Count)
+	}
+	return desc
+}
+
+// Next returns the first occurrence of a series starting at start that is
+// strictly after after, or false once the series has ended
+func (r Rule) Next(start, after time.Time) (time.Time, bool, error) {
+	var next time.Time
+	found := false
+	err := r.each(start, func(t time.Time) bool {
+		if t.After(after) {
+			next, found = t, true
+			return false
+		}
+		return true
+	})
+	return next, found, err
+}
+
+// Between returns the occurrences of a series starting at start that fall
+// within [from, to). With ErrTooManyOccurrences it returns those it got to.
+func (r Rule) Between(start, from, to time.Time) ([]time.Time, error) {
+	var occurrences []time.Time
+	err := r.each(start, func(t time.Time) bool {
+		if !t.Before(to) {
+			return false
+		}
+		if !t.Before(from) {
+			occurrences = append(occurrences, t)
+		}
+		return true
+	})
+	return occurrences, err
+}
+
+// each calls fn with every occurrence in order, starting with start itself,
+// until fn returns false or the series ends. It gives up with
+// ErrTooManyOccurrences after maxIterations steps.
+func (r Rule) each(start time.Time, fn func(time.Time) bool) error {
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	until := r.End(start.Location())
+	emitted := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if !until.IsZero() && t.After(until) {
+			return false
+		}
+		if r.Count > 0 && emitted >= r.Count {
+			return false
+		}
+		emitted++
+		return fn(t)
+	}
+
+	clock := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(),
+			start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	for i := 0; i < maxIterations; i++ {
+		switch r.Freq {
+		case Daily:
+			if !emit(start.AddDate(0, 0, i*interval)) {
+				return nil
+			}
+
+		case Weekly:
+			days := r.ByDay
+			if len(days) == 0 {
+				days = []time.Weekday{start.Weekday()}
+			}
+			// Walk the week (Sunday first) that is i*interval weeks after start's
+			weekStart := start.AddDate(0, 0, -int(start.Weekday())+7*i*interval)
+			for wd := time.Sunday; wd <= time.Saturday; wd++ {
+				if !containsWeekday(days, wd) {
+					continue
+				}
+				if !emit(clock(weekStart.AddDate(0, 0, int(wd)))) {
+					return nil
+				}
+			}
+
+		case Monthly:
+			first := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location()).AddDate(0, i*interval, 0)
+			day := r.ByMonthDay
+			if day == 0 {
+				day = start.Day()
+			}
+			last := first.AddDate(0, 1, -1).Day()
+			if day == -1 {
+				day = last
+			}
+			// Months without that day (the 31st in April) are skipped, as in RFC 5545
+			if day > last {
+				continue
+			}
+			if !emit(clock(first.AddDate(0, 0, day-1))) {
+				return nil
+			}
+
+		case Yearly:
+			t := start.AddDate(i*interval, 0, 0)
+			// Feb 29 only happens in leap years
+			if t.Day() != start.Day() {
+				continue
+			}
+			if !emit(t) {
+				return nil
+			}
+		}
+	}
+	return ErrTooManyOccurrences
+}
+
+func containsWeekday(days []time.Weekday, wd time.Weekday) bool {
+	for _, d := range days {
+		if d == wd {
+			return true
+		}
+	}
+	return false
+}
+
+func isWeekdays(days []time.Weekday) bool {
+	if len(days) != 5 {
+		return false
+	}
+	for wd := time.Monday; wd <= time.Friday; wd++ {
+		if !containsWeekday(days, wd) {
+			return false
+		}
+	}
+	return true
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+func indexOf(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
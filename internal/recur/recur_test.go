@@ -0,0 +1,240 @@
+package recur
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata" // America/New_York without relying on the system zoneinfo
+)
+
+const layout = "2006-01-02 15:04"
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func mustParse(t *testing.T, input string) Rule {
+	t.Helper()
+	rule, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", input, err)
+	}
+	return rule
+}
+
+func at(t *testing.T, value string, loc *time.Location) time.Time {
+	t.Helper()
+	parsed, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// Phrases
+		{"daily", "FREQ=DAILY"},
+		{"Every  3 Days", "FREQ=DAILY;INTERVAL=3"},
+		{"every 1 year", "FREQ=YEARLY"},
+		{"every weekday", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+		{"weekends", "FREQ=WEEKLY;BYDAY=SA,SU"},
+		{"biweekly", "FREQ=WEEKLY;INTERVAL=2"},
+		{"every mon, wed", "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"weekly on tue and thu", "FREQ=WEEKLY;BYDAY=TU,TH"},
+		{"monthly on the 31st", "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{"every month on the last day", "FREQ=MONTHLY;BYMONTHDAY=-1"},
+		{"annually", "FREQ=YEARLY"},
+
+		// RRULEs
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12", "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12"},
+		{"freq=daily;until=20261231", "FREQ=DAILY;UNTIL=20261231"},
+		{"FREQ=DAILY;UNTIL=20261231T235959Z;", "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{"FREQ=DAILY;UNTIL=20261231T120000", "FREQ=DAILY;UNTIL=20261231T120000Z"}, // floating, read as UTC
+	}
+
+	for _, tt := range tests {
+		rule, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got := rule.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+		if again, err := Parse(rule.String()); err != nil || !reflect.DeepEqual(again, rule) {
+			t.Errorf("Parse(%q) doesn't round-trip: %+v, %v", rule.String(), again, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"every 0 days",
+		"monthly on 32",
+		"every funday",
+		"hourly",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;COUNT",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=-2",
+		"FREQ=DAILY;WKST=MO",
+	} {
+		if rule, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q) = %s, want an error", input, rule)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	utc, ny := time.UTC, newYork(t)
+
+	tests := []struct {
+		name     string
+		rule     string
+		loc      *time.Location
+		start    string
+		from, to string
+		want     []string
+	}{
+		{"daily", "daily", utc, "2026-10-14 09:00", "2026-10-01 00:00", "2026-10-17 00:00",
+			[]string{"2026-10-14 09:00", "2026-10-15 09:00", "2026-10-16 09:00"}},
+		{"to is exclusive", "daily", utc, "2026-10-14 09:00", "2026-10-14 09:00", "2026-10-16 09:00",
+			[]string{"2026-10-14 09:00", "2026-10-15 09:00"}},
+		{"interval", "every 3 days", utc, "2026-10-14 09:00", "2026-10-15 00:00", "2026-10-24 00:00",
+			[]string{"2026-10-17 09:00", "2026-10-20 09:00", "2026-10-23 09:00"}},
+		{"local clock across DST", "daily", ny, "2026-10-31 09:00", "2026-10-31 00:00", "2026-11-03 00:00",
+			[]string{"2026-10-31 09:00", "2026-11-01 09:00", "2026-11-02 09:00"}},
+
+		{"weekdays from a Wednesday", "FREQ=WEEKLY;BYDAY=MO,FR", utc, "2026-10-14 09:00", "2026-10-01 00:00", "2026-10-24 00:00",
+			[]string{"2026-10-16 09:00", "2026-10-19 09:00", "2026-10-23 09:00"}},
+		{"every other week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", utc, "2026-10-12 09:00", "2026-10-01 00:00", "2026-11-01 00:00",
+			[]string{"2026-10-12 09:00", "2026-10-16 09:00", "2026-10-26 09:00", "2026-10-30 09:00"}},
+
+		{"monthly on the 31st", "monthly", utc, "2026-01-31 09:00", "2026-01-01 00:00", "2026-08-01 00:00",
+			[]string{"2026-01-31 09:00", "2026-03-31 09:00", "2026-05-31 09:00", "2026-07-31 09:00"}},
+		{"monthly on the last day", "FREQ=MONTHLY;BYMONTHDAY=-1", utc, "2026-01-31 09:00", "2026-01-01 00:00", "2026-05-01 00:00",
+			[]string{"2026-01-31 09:00", "2026-02-28 09:00", "2026-03-31 09:00", "2026-04-30 09:00"}},
+		{"monthly on the 29th", "monthly", utc, "2024-01-29 09:00", "2024-01-01 00:00", "2024-04-01 00:00",
+			[]string{"2024-01-29 09:00", "2024-02-29 09:00", "2024-03-29 09:00"}},
+		{"monthly on the 29th outside leap years", "monthly", utc, "2026-01-29 09:00", "2026-01-01 00:00", "2026-04-01 00:00",
+			[]string{"2026-01-29 09:00", "2026-03-29 09:00"}},
+		{"yearly on Feb 29", "yearly", utc, "2024-02-29 09:00", "2024-01-01 00:00", "2033-01-01 00:00",
+			[]string{"2024-02-29 09:00", "2028-02-29 09:00", "2032-02-29 09:00"}},
+
+		{"COUNT counts from the start", "FREQ=DAILY;COUNT=3", utc, "2026-10-14 09:00", "2026-10-15 00:00", "2026-11-01 00:00",
+			[]string{"2026-10-15 09:00", "2026-10-16 09:00"}},
+		{"COUNT skips missing days", "FREQ=MONTHLY;COUNT=2", utc, "2026-01-31 09:00", "2026-01-01 00:00", "2027-01-01 00:00",
+			[]string{"2026-01-31 09:00", "2026-03-31 09:00"}},
+		{"UNTIL is inclusive", "FREQ=DAILY;UNTIL=20261016T090000Z", utc, "2026-10-14 09:00", "2026-10-01 00:00", "2026-11-01 00:00",
+			[]string{"2026-10-14 09:00", "2026-10-15 09:00", "2026-10-16 09:00"}},
+		{"UNTIL before the last clock", "FREQ=DAILY;UNTIL=20261016T085959Z", utc, "2026-10-14 09:00", "2026-10-01 00:00", "2026-11-01 00:00",
+			[]string{"2026-10-14 09:00", "2026-10-15 09:00"}},
+		// 20:00 in New York is already the next day in UTC
+		{"UNTIL date in the series' zone", "FREQ=DAILY;UNTIL=20261016", ny, "2026-10-14 20:00", "2026-10-01 00:00", "2026-11-01 00:00",
+			[]string{"2026-10-14 20:00", "2026-10-15 20:00", "2026-10-16 20:00"}},
+		{"UNTIL before the start", "FREQ=DAILY;UNTIL=20261001", utc, "2026-10-14 09:00", "2026-10-01 00:00", "2026-11-01 00:00",
+			nil},
+	}
+
+	for _, tt := range tests {
+		rule := mustParse(t, tt.rule)
+		start := at(t, tt.start, tt.loc)
+		occurrences, err := rule.Between(start, at(t, tt.from, tt.loc), at(t, tt.to, tt.loc))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, occurrence := range occurrences {
+			got = append(got, occurrence.In(tt.loc).Format(layout))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		rule         string
+		start, after string
+		want         string // empty when the series has ended
+	}{
+		{"daily", "2026-10-14 09:00", "2026-10-14 09:00", "2026-10-15 09:00"},
+		{"daily", "2026-10-14 09:00", "2026-10-14 12:00", "2026-10-15 09:00"},
+		{"daily", "2026-10-14 09:00", "2026-10-01 00:00", "2026-10-14 09:00"},
+		{"every weekday", "2026-10-16 09:00", "2026-10-16 09:00", "2026-10-19 09:00"},
+		{"monthly", "2026-01-31 09:00", "2026-01-31 09:00", "2026-03-31 09:00"},
+		{"yearly", "2024-02-29 09:00", "2024-02-29 09:00", "2028-02-29 09:00"},
+		{"FREQ=DAILY;COUNT=2", "2026-10-14 09:00", "2026-10-14 09:00", "2026-10-15 09:00"},
+		{"FREQ=DAILY;COUNT=2", "2026-10-14 09:00", "2026-10-15 09:00", ""},
+		{"FREQ=DAILY;UNTIL=20261015", "2026-10-14 09:00", "2026-10-15 09:00", ""},
+	}
+
+	for _, tt := range tests {
+		rule := mustParse(t, tt.rule)
+		next, ok, err := rule.Next(at(t, tt.start, time.UTC), at(t, tt.after, time.UTC))
+		if err != nil {
+			t.Errorf("%s from %s after %s: %v", tt.rule, tt.start, tt.after, err)
+			continue
+		}
+		got := ""
+		if ok {
+			got = next.Format(layout)
+		}
+		if got != tt.want {
+			t.Errorf("%s from %s: next after %s = %q, want %q", tt.rule, tt.start, tt.after, got, tt.want)
+		}
+	}
+}
+
+func TestTooManyOccurrences(t *testing.T) {
+	rule := mustParse(t, "daily")
+	start := at(t, "2000-01-01 09:00", time.UTC)
+	occurrences, err := rule.Between(start, at(t, "2100-01-01 00:00", time.UTC), at(t, "2100-01-02 00:00", time.UTC))
+	if !errors.Is(err, ErrTooManyOccurrences) || len(occurrences) != 0 {
+		t.Errorf("Between a century in = %d occurrences, %v, want ErrTooManyOccurrences", len(occurrences), err)
+	}
+	if _, _, err := rule.Next(start, at(t, "2100-01-01 00:00", time.UTC)); !errors.Is(err, ErrTooManyOccurrences) {
+		t.Errorf("Next a century in = %v, want ErrTooManyOccurrences", err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"daily", "every day"},
+		{"weekdays", "every weekday"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "every 2 weeks on Mon, Fri"},
+		{"monthly on the 22nd", "every month on the 22nd"},
+		{"monthly on the 11th", "every month on the 11th"},
+		{"every month on the last day", "every month on the last day"},
+		{"FREQ=YEARLY;UNTIL=20301231;COUNT=3", "every year until Dec 31, 2030, 3 times"},
+	}
+
+	for _, tt := range tests {
+		if got := mustParse(t, tt.rule).Describe(); got != tt.want {
+			t.Errorf("Describe(%s) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
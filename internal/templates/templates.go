@@ -0,0 +1,206 @@
+// Package templates holds the skeletons new notes can start from. They are
+// Markdown files in ~/.prodbooster/templates/, one per template and named
+// after it, with the same optional front matter as notes edited in $EDITOR:
+//
+//	---
+//	title: Meeting: {{event.title}}
+//	tags: meeting
+//	---
+//
+//	📍 {{event.location}}
+//
+// Variables in {{braces}} are filled in when a note starts from the
+// template, see Template.Expand.
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/notefile"
+)
+
+type Template struct {
+	Name    string // file name without .md
+	Title   string
+	Tags    []string
+	Content string
+}
+
+// Vars are what the variables of a template are filled in with
+type Vars struct {
+	Now   time.Time     // when the note is written
+	Event *models.Event // event the note is written for, nil if none
+}
+
+// builtin are the templates used until the templates directory exists.
+// Copying them there is a good start for your own.
+var builtin = []Template{
+	{
+		Name:  "Meeting",
+		Title: "Meeting: {{event.title}}",
+		Tags:  []string{"meeting"},
+		Content: "📅 {{event.date}} {{event.time}} • 📍 {{event.location}}\n\n" +
+			"## Attendees\n\n- \n\n## Agenda\n\n- \n\n## Notes\n\n\n## Action items\n\n- [ ] ",
+	},
+	{
+		Name:    "Daily log",
+		Title:   "Log {{date}}",
+		Tags:    []string{"log"},
+		Content: "# {{weekday}}, {{date}}\n\n## Done\n\n- \n\n## Blocked\n\n- \n\n## Tomorrow\n\n- [ ] ",
+	},
+}
+
+var dir string
+
+// Init sets the directory templates are read from
+func Init(path string) {
+	dir = path
+}
+
+// Dir returns the directory templates are read from
+func Dir() string {
+	return dir
+}
+
+// SkippedError is returned by List along with the templates it could read,
+// naming the files it couldn't
+type SkippedError struct {
+	Files map[string]error // why each file was skipped, by template name
+}
+
+func (e *SkippedError) Error() string {
+	names := make([]string, 0, len(e.Files))
+	for name := range e.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	problems := make([]string, len(names))
+	for i, name := range names {
+		problems[i] = fmt.Sprintf("%s (%v)", name, e.Files[name])
+	}
+	return "skipped templates: " + strings.Join(problems, ", ")
+}
+
+// List reads the templates, sorted by name. They are read on every call,
+// so edits to the files show up without a restart. Files that can't be
+// read are left out and reported with a *SkippedError.
+func List() ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) || dir == "" {
+		return append([]Template(nil), builtin...), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	templates := []Template{}
+	skipped := &SkippedError{Files: map[string]error{}}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			skipped.Files[name] = err
+			continue
+		}
+		title, tags, content, err := notefile.ParseOptional(string(data))
+		if err != nil {
+			skipped.Files[name] = err
+			continue
+		}
+		templates = append(templates, Template{
+			Name:    name,
+			Title:   title,
+			Tags:    tags,
+			Content: content,
+		})
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return strings.ToLower(templates[i].Name) < strings.ToLower(templates[j].Name)
+	})
+	if len(skipped.Files) > 0 {
+		return templates, skipped
+	}
+	return templates, nil
+}
+
+// Expand fills in the variables of the template's title and content:
+//
+//	{{date}} {{time}} {{weekday}}                        when the note is written
+//	{{event.title}} {{event.location}} {{event.date}} {{event.time}}
+//
+// The event ones are empty when the note isn't written for an event.
+// Unknown variables are left as they are.
+func (t Template) Expand(vars Vars) (title string, tags []string, content string) {
+	pairs := []string{
+		"{{date}}", vars.Now.Format("2006-01-02"),
+		"{{time}}", vars.Now.Format("15:04"),
+		"{{weekday}}", vars.Now.Format("Monday"),
+	}
+
+	event := vars.Event
+	if event == nil {
+		event = &models.Event{}
+	}
+	eventDate, eventTime := "", ""
+	if !event.StartTime.IsZero() {
+		eventDate = event.StartTime.Format("2006-01-02")
+		eventTime = event.StartTime.Format("15:04")
+	}
+	pairs = append(pairs,
+		"{{event.title}}", event.Title,
+		"{{event.location}}", event.Location,
+		"{{event.date}}", eventDate,
+		"{{event.time}}", eventTime,
+	)
+
+	replacer := strings.NewReplacer(pairs...)
+	// So "Meeting: {{event.title}}" is just "Meeting" without an event
+	title = strings.TrimRight(strings.TrimSpace(replacer.Replace(t.Title)), " :-")
+	return title, t.Tags, replacer.Replace(t.Content)
+}
+
+// journal is the template daily journal notes start from until there is a
+// journal.md in the templates directory
+var journal = Template{
+	Name:    "Journal",
+	Title:   "Journal {{date}}",
+	Tags:    []string{"journal"},
+	Content: "# {{weekday}}, {{date}}\n\n## Today\n\n- \n\n## Grateful for\n\n- \n\n## Thoughts\n\n",
+}
+
+// Journal returns the template daily journal notes start from: the one
+// named journal, or a built-in one. Problems with other templates don't
+// matter here, but when journal.md can't be read the built-in template
+// comes back together with why.
+func Journal() (Template, error) {
+	templates, err := List()
+	for _, t := range templates {
+		if strings.EqualFold(t.Name, "journal") {
+			return t, nil
+		}
+	}
+
+	var skipped *SkippedError
+	if errors.As(err, &skipped) {
+		for name, err := range skipped.Files {
+			if strings.EqualFold(name, "journal") {
+				return journal, fmt.Errorf("journal template: %w", err)
+			}
+		}
+		return journal, nil
+	}
+	return journal, err
+}
@@ -0,0 +1,35 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	"prodBooster/internal/dateparse"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// datePreview renders the live "→ resolved timestamp" line shown under date
+// inputs, so a typo is caught before the form is saved. Empty input renders
+// the hint instead.
+func datePreview(input, hint string, parse func(string) (time.Time, error)) string {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+	if strings.TrimSpace(input) == "" {
+		return hintStyle.Render("  " + hint)
+	}
+
+	t, err := parse(input)
+	if err != nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  ✗ " + err.Error())
+	}
+	preview := "  → " + dateparse.Describe(t, time.Now())
+	if _, offset := t.Zone(); offset != localOffset(t) {
+		preview += " · " + t.Local().Format("Mon 3:04 PM") + " your time"
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("45")).Render(preview)
+}
+
+func localOffset(t time.Time) int {
+	_, offset := t.Local().Zone()
+	return offset
+}
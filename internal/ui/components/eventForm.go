@@ -1,7 +1,11 @@
 package components
 
 import (
+	"errors"
+	"fmt"
+	"prodBooster/internal/dateparse"
 	"prodBooster/internal/models"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -16,14 +20,32 @@ type EventForm struct {
 	locationInput textinput.Model
 	startInput    textinput.Model
 	endInput      textinput.Model
+	zoneInput     textinput.Model
+	repeatInput   textinput.Model
+	tagsInput     textinput.Model
 	focusIndex    int
 	width         int
 	height        int
 	isActive      bool
 	editMode      bool
 	editingID     int
+	scope         EditScope
+	occurrence    time.Time // original start of the occurrence being edited
+	err           error
 }
 
+// EditScope says which occurrences of a repeating event an edit applies to
+type EditScope int
+
+const (
+	ScopeAll        EditScope = iota // the whole event
+	ScopeOccurrence                  // only the selected occurrence
+	ScopeFuture                      // the selected occurrence and every later one
+)
+
+// eventDefaultStart is the time of day used when the start only names a day
+const eventDefaultStart = 9 * time.Hour
+
 func NewEventForm(eventList *models.EventList) *EventForm {
 	ti := textinput.New()
 	ti.Placeholder = "What's happening? (e.g., Team meeting, Lunch with Sarah)"
@@ -36,10 +58,19 @@ func NewEventForm(eventList *models.EventList) *EventForm {
 	li.Placeholder = "Where? (e.g., Office, Zoom, Coffee shop)"
 
 	si := textinput.New()
-	si.Placeholder = "When does it start? → 2025-12-25 14:30"
+	si.Placeholder = "When does it start? → tomorrow 3pm, next fri 10:00, 2025-12-25 14:30"
 
 	ei := textinput.New()
-	ei.Placeholder = "When does it end? → 2025-12-25 16:00 (or leave empty)"
+	ei.Placeholder = "When does it end? → 1h30m, 16:00, fri 5pm (or leave empty)"
+
+	zi := textinput.New()
+	zi.Placeholder = "Scheduled in another time zone? → America/New_York (or leave empty)"
+
+	ri := textinput.New()
+	ri.Placeholder = "Repeat? → weekdays, every mon, monthly on 1 (optional)"
+
+	tgi := textinput.New()
+	tgi.Placeholder = "Tags? → #work #family (optional)"
 
 	return &EventForm{
 		eventList:     eventList,
@@ -48,6 +79,9 @@ func NewEventForm(eventList *models.EventList) *EventForm {
 		locationInput: li,
 		startInput:    si,
 		endInput:      ei,
+		zoneInput:     zi,
+		repeatInput:   ri,
+		tagsInput:     tgi,
 		focusIndex:    0,
 		isActive:      false,
 		editMode:      false,
@@ -75,15 +109,22 @@ func (f *EventForm) Update(msg tea.Msg) (*EventForm, tea.Cmd) {
 
 		case "tab":
 			f.focusIndex++
-			if f.focusIndex > 4 {
+			if f.focusIndex > 7 {
 				f.focusIndex = 0
 			}
+			if f.focusIndex == 6 && f.scope == ScopeOccurrence {
+				// A single occurrence can't repeat on its own
+				f.focusIndex++
+			}
 
 			f.titleInput.Blur()
 			f.descInput.Blur()
 			f.locationInput.Blur()
 			f.startInput.Blur()
 			f.endInput.Blur()
+			f.zoneInput.Blur()
+			f.repeatInput.Blur()
+			f.tagsInput.Blur()
 
 			switch f.focusIndex {
 			case 0:
@@ -96,14 +137,21 @@ func (f *EventForm) Update(msg tea.Msg) (*EventForm, tea.Cmd) {
 				cmd = f.startInput.Focus()
 			case 4:
 				cmd = f.endInput.Focus()
+			case 5:
+				cmd = f.zoneInput.Focus()
+			case 6:
+				cmd = f.repeatInput.Focus()
+			case 7:
+				cmd = f.tagsInput.Focus()
 			}
 
 			return f, cmd
 
 		case "ctrl+s":
-			// Submit form
+			// Submit form, keep it open if the input can't be saved
 			if err := f.Submit(); err != nil {
-				// TODO: Show error
+				f.err = err
+				return f, nil
 			}
 			f.isActive = false
 			f.Reset()
@@ -122,6 +170,12 @@ func (f *EventForm) Update(msg tea.Msg) (*EventForm, tea.Cmd) {
 		f.startInput, cmd = f.startInput.Update(msg)
 	case 4:
 		f.endInput, cmd = f.endInput.Update(msg)
+	case 5:
+		f.zoneInput, cmd = f.zoneInput.Update(msg)
+	case 6:
+		f.repeatInput, cmd = f.repeatInput.Update(msg)
+	case 7:
+		f.tagsInput, cmd = f.tagsInput.Update(msg)
 	}
 
 	return f, cmd
@@ -142,6 +196,12 @@ func (f *EventForm) View() string {
 	if f.editMode {
 		title = "✏️  Edit Event"
 	}
+	switch f.scope {
+	case ScopeOccurrence:
+		title += " · only " + f.occurrence.Format("Mon, Jan 2")
+	case ScopeFuture:
+		title += " · " + f.occurrence.Format("Mon, Jan 2") + " and later"
+	}
 
 	focusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
@@ -155,10 +215,16 @@ func (f *EventForm) View() string {
 		{"🎯 What's happening?", f.titleInput.View(), ""},
 		{"💬 Details", f.descInput.View(), "💡 optional - add context if needed"},
 		{"📍 Where?", f.locationInput.View(), "💡 optional - place, room, or link"},
-		{"🕐 Start time", f.startInput.View(), "Format: 2025-12-25 14:30 (year-month-day hour:minute)"},
-		{"🕑 End time", f.endInput.View(), "💡 optional - leave empty for no end time"},
+		{"🕐 Start time", f.startInput.View(), ""},
+		{"🕑 End time", f.endInput.View(), ""},
+		{"🌍 Time zone", f.zoneInput.View(), ""},
+		{"🔁 Repeat", f.repeatInput.View(), ""},
+		{"🏷️  Tags", f.tagsInput.View(), "💡 optional - separate with spaces or commas"},
 	}
 
+	startPreview := datePreview(f.startInput.Value(), "💡 e.g. tomorrow 3pm, mon 09:30, in 2h - a day alone starts at 9:00 AM", f.parseStart)
+	endPreview := datePreview(f.endInput.Value(), "💡 optional - a length like 1h30m or a time, leave empty for no end time", f.parseEnd)
+
 	var lines []string
 	lines = append(lines, lipgloss.NewStyle().Bold(true).Render(title))
 	lines = append(lines, "")
@@ -172,17 +238,25 @@ func (f *EventForm) View() string {
 		}
 		lines = append(lines, label)
 		lines = append(lines, field.input)
-		if field.hint != "" {
+		switch {
+		case i == 3:
+			lines = append(lines, startPreview)
+		case i == 4:
+			lines = append(lines, endPreview)
+		case i == 5:
+			lines = append(lines, f.zoneHint(hintStyle))
+		case i == 6:
+			lines = append(lines, f.repeatHint(hintStyle))
+		case field.hint != "":
 			lines = append(lines, hintStyle.Render("  "+field.hint))
 		}
 		lines = append(lines, "")
 	}
 
-	lines = append(lines, "")
-	lines = append(lines, hintStyle.Render("💡 Quick tips:"))
-	lines = append(lines, hintStyle.Render("  • Time uses 24-hour format (14:30 = 2:30 PM, 09:00 = 9:00 AM)"))
-	lines = append(lines, hintStyle.Render("  • No end time? No problem - just leave it blank!"))
-	lines = append(lines, "")
+	if f.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+f.err.Error()))
+		lines = append(lines, "")
+	}
 	lines = append(lines, normalStyle.Render("✨ Ctrl+S to save • Tab to move around • Esc to cancel"))
 
 	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
@@ -215,9 +289,23 @@ func (f *EventForm) Reset() {
 	f.locationInput.SetValue("")
 	f.startInput.SetValue("")
 	f.endInput.SetValue("")
+	f.zoneInput.SetValue("")
+	f.repeatInput.SetValue("")
+	f.tagsInput.SetValue("")
 	f.focusIndex = 0
 	f.editMode = false
 	f.editingID = 0
+	f.scope = ScopeAll
+	f.occurrence = time.Time{}
+	f.err = nil
+	f.titleInput.Focus()
+	f.descInput.Blur()
+	f.locationInput.Blur()
+	f.startInput.Blur()
+	f.endInput.Blur()
+	f.zoneInput.Blur()
+	f.repeatInput.Blur()
+	f.tagsInput.Blur()
 }
 
 func (f *EventForm) Submit() error {
@@ -225,29 +313,112 @@ func (f *EventForm) Submit() error {
 	desc := f.descInput.Value()
 	location := f.locationInput.Value()
 	startStr := f.startInput.Value()
-	endStr := f.endInput.Value()
 
-	if title == "" || startStr == "" || endStr == "" {
+	if title == "" || strings.TrimSpace(startStr) == "" {
 		return nil // Don't submit incomplete events
 	}
 
-	// Parse times (simple parsing, you might want better validation)
-	layout := "2006-01-02 15:04"
-	startTime, err := time.Parse(layout, startStr)
+	timezone := strings.TrimSpace(f.zoneInput.Value())
+	if _, err := f.zone(); err != nil {
+		return err
+	}
+
+	startTime, err := f.parseStart(startStr)
 	if err != nil {
 		return err
 	}
 
-	endTime, err := time.Parse(layout, endStr)
+	// An empty end means the event has no end time
+	endTime, err := f.parseEnd(f.endInput.Value())
+	if err != nil && !errors.Is(err, dateparse.ErrEmpty) {
+		return err
+	}
+	if !endTime.IsZero() && endTime.Before(startTime) {
+		return errors.New("the event ends before it starts")
+	}
+
+	recurrence, err := parseRecurrence(f.repeatInput.Value())
 	if err != nil {
 		return err
 	}
 
+	tags := models.ParseTags(f.tagsInput.Value())
+
 	if f.editMode {
-		return f.eventList.Update(f.editingID, title, desc, location, startTime, endTime)
+		switch f.scope {
+		case ScopeOccurrence:
+			return f.eventList.UpdateOccurrence(f.editingID, f.occurrence, title, desc, location, timezone, startTime, endTime, tags)
+		case ScopeFuture:
+			return f.eventList.UpdateFuture(f.editingID, f.occurrence, title, desc, location, timezone, startTime, endTime, tags, recurrence)
+		}
+		return f.eventList.Update(f.editingID, title, desc, location, timezone, startTime, endTime, tags, recurrence)
+	}
+
+	return f.eventList.Add(title, desc, location, timezone, startTime, endTime, tags, recurrence)
+}
+
+// zone is the location start and end are typed in: the time zone field if
+// set, local time otherwise
+func (f *EventForm) zone() (*time.Location, error) {
+	name := strings.TrimSpace(f.zoneInput.Value())
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+func (f *EventForm) parseStart(input string) (time.Time, error) {
+	loc, err := f.zone()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return dateparse.Parse(input, time.Now().In(loc), eventDefaultStart)
+}
+
+// parseEnd resolves the end input relative to whatever start is typed in
+func (f *EventForm) parseEnd(input string) (time.Time, error) {
+	start, err := f.parseStart(f.startInput.Value())
+	if err != nil {
+		return time.Time{}, errors.New("fix the start time first")
+	}
+	return dateparse.ParseEnd(input, start)
+}
+
+func (f *EventForm) zoneHint(hintStyle lipgloss.Style) string {
+	if strings.TrimSpace(f.zoneInput.Value()) == "" {
+		return hintStyle.Render("  💡 optional - times above are read in this zone, shown in yours")
+	}
+	loc, err := f.zone()
+	if err != nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  ✗ " + err.Error())
 	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("45")).Render("  → now " + time.Now().In(loc).Format("3:04 PM MST") + " there")
+}
 
-	return f.eventList.Add(title, desc, location, startTime, endTime)
+func (f *EventForm) repeatHint(hintStyle lipgloss.Style) string {
+	if f.scope == ScopeOccurrence {
+		return hintStyle.Render("  💡 this occurrence becomes a one-off event, the rest keep repeating")
+	}
+	start, err := f.parseStart(f.startInput.Value())
+	if err != nil {
+		start = time.Now()
+	}
+	return recurrencePreview(f.repeatInput.Value(), "💡 optional - leave empty for a one-off event", start)
+}
+
+// LoadOccurrenceForEdit edits one occurrence of a repeating event, and with
+// ScopeFuture every occurrence after it too
+func (f *EventForm) LoadOccurrenceForEdit(occurrence *models.Event, scope EditScope) {
+	f.LoadForEdit(occurrence)
+	f.scope = scope
+	f.occurrence = occurrence.Occurrence
+	if scope == ScopeOccurrence {
+		f.repeatInput.SetValue("")
+	}
 }
 
 func (f *EventForm) LoadForEdit(event *models.Event) {
@@ -256,10 +427,18 @@ func (f *EventForm) LoadForEdit(event *models.Event) {
 	f.titleInput.SetValue(event.Title)
 	f.descInput.SetValue(event.Content)
 	f.locationInput.SetValue(event.Location)
+	f.tagsInput.SetValue(models.FormatTags(event.Tags))
+	if event.IsRecurring() {
+		f.repeatInput.SetValue(recurrenceInput(event.Recurrence))
+	}
 
-	layout := "2006-01-02 15:04"
-	f.startInput.SetValue(event.StartTime.Format(layout))
-	f.endInput.SetValue(event.EndTime.Format(layout))
+	// Edit in the zone the event was scheduled in
+	zone := event.Zone()
+	f.zoneInput.SetValue(event.Timezone)
+	f.startInput.SetValue(event.StartTime.In(zone).Format(dateparse.Layout))
+	if !event.EndTime.IsZero() {
+		f.endInput.SetValue(event.EndTime.In(zone).Format(dateparse.Layout))
+	}
 
 	f.isActive = true
 	f.titleInput.Focus()
@@ -0,0 +1,294 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/query"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// globalSearchLimit is how many results each group shows
+const globalSearchLimit = 5
+
+// SearchResult is one item found by GlobalSearch
+type SearchResult struct {
+	Page    models.PageType // page the item lives on
+	ID      int
+	Title   string
+	Snippet string
+}
+
+// GlobalSearch is an overlay searching todos, notes and events at once.
+// Results are grouped by type; Enter picks one, see Chosen.
+type GlobalSearch struct {
+	todoList  *models.TodoList
+	noteList  *models.NoteList
+	eventList *models.EventList
+	input     textinput.Model
+	title     string
+	groups    [3][]SearchResult // todos, notes, events
+	cursor    int               // index into all results, in group order
+	chosen    *SearchResult
+	active    bool
+	err       error
+	width     int
+	substring bool // no full-text index in this build, see SetFullText
+}
+
+func NewGlobalSearch(todoList *models.TodoList, noteList *models.NoteList, eventList *models.EventList) *GlobalSearch {
+	input := textinput.New()
+	input.Placeholder = "Search todos, notes and events... words, \"phrases\", #tags, fields like due:today"
+	input.CharLimit = 100
+	input.Width = 60
+
+	return &GlobalSearch{
+		todoList:  todoList,
+		noteList:  noteList,
+		eventList: eventList,
+		input:     input,
+		width:     80,
+	}
+}
+
+func (g *GlobalSearch) Activate() {
+	g.Pick("🔎 Search Everything")
+}
+
+// Pick activates the search to choose an item for something else than
+// jumping to it, e.g. a link target, under the given title
+func (g *GlobalSearch) Pick(title string) {
+	g.title = title
+	g.active = true
+	g.chosen = nil
+	g.input.Focus()
+	g.search()
+}
+
+func (g *GlobalSearch) Deactivate() {
+	g.active = false
+	g.input.Blur()
+	g.input.SetValue("")
+	g.groups = [3][]SearchResult{}
+	g.cursor = 0
+	g.err = nil
+}
+
+func (g *GlobalSearch) IsActive() bool {
+	return g.active
+}
+
+// Chosen returns the result picked with Enter, once
+func (g *GlobalSearch) Chosen() (SearchResult, bool) {
+	if g.chosen == nil {
+		return SearchResult{}, false
+	}
+	chosen := *g.chosen
+	g.chosen = nil
+	return chosen, true
+}
+
+// SetFullText tells the search whether the store has a full-text index.
+// Without one it says that it only matches substrings.
+func (g *GlobalSearch) SetFullText(ok bool) {
+	g.substring = !ok
+}
+
+func (g *GlobalSearch) SetWidth(width int) {
+	g.width = width
+	g.input.Width = width - 20
+}
+
+func (g *GlobalSearch) Update(msg tea.Msg) (*GlobalSearch, tea.Cmd) {
+	if !g.active {
+		return g, nil
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			g.Deactivate()
+			return g, nil
+		case "enter":
+			if result, ok := g.selected(); ok {
+				g.Deactivate()
+				g.chosen = &result
+			}
+			return g, nil
+		case "up", "shift+tab":
+			if g.cursor > 0 {
+				g.cursor--
+			}
+			return g, nil
+		case "down", "tab":
+			if g.cursor < g.count()-1 {
+				g.cursor++
+			}
+			return g, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	previous := g.input.Value()
+	g.input, cmd = g.input.Update(msg)
+	if g.input.Value() != previous {
+		g.search()
+	}
+	return g, cmd
+}
+
+// search fills the result groups from the current query: full-text search
+// for its words and phrases, narrowed down by the rest of it
+func (g *GlobalSearch) search() {
+	g.groups = [3][]SearchResult{}
+	g.cursor = 0
+	g.err = nil
+
+	raw := strings.TrimSpace(g.input.Value())
+	if raw == "" {
+		return
+	}
+	q, err := query.Parse(raw, time.Now())
+	if err != nil {
+		g.err = err
+		return
+	}
+
+	// Every item stands in for search hits, in the order the lists keep
+	// them, when the query has no words to search for
+	var todos, notes, events []models.SearchHit
+	if text := q.Text(); text != "" {
+		if todos, err = g.todoList.Search(text); err == nil {
+			if notes, err = g.noteList.Search(text); err == nil {
+				events, err = g.eventList.Search(text)
+			}
+		}
+		if err != nil {
+			g.err = err
+			return
+		}
+	} else {
+		for _, todo := range g.todoList.Todos {
+			todos = append(todos, models.SearchHit{ID: todo.ID})
+		}
+		for _, note := range g.noteList.Notes {
+			notes = append(notes, models.SearchHit{ID: note.ID})
+		}
+		for _, event := range g.eventList.Events {
+			events = append(events, models.SearchHit{ID: event.ID})
+		}
+	}
+
+	for _, hit := range todos {
+		if todo := g.todoList.Get(hit.ID); todo != nil && q.MatchTodo(todo) {
+			g.groups[0] = append(g.groups[0], SearchResult{models.PageTodos, hit.ID, todo.Title, hit.Snippet})
+		}
+	}
+	for _, hit := range notes {
+		if note := g.noteList.Get(hit.ID); note != nil && q.MatchNote(note) {
+			g.groups[1] = append(g.groups[1], SearchResult{models.PageNotes, hit.ID, note.Title, hit.Snippet})
+		}
+	}
+	for _, hit := range events {
+		if event := g.eventList.Get(hit.ID); event != nil && q.MatchEvent(event) {
+			g.groups[2] = append(g.groups[2], SearchResult{models.PageCalendar, hit.ID, event.Title, hit.Snippet})
+		}
+	}
+}
+
+// shown returns the results of a group that fit on screen
+func (g *GlobalSearch) shown(group int) []SearchResult {
+	results := g.groups[group]
+	return results[:min(len(results), globalSearchLimit)]
+}
+
+func (g *GlobalSearch) count() int {
+	return len(g.shown(0)) + len(g.shown(1)) + len(g.shown(2))
+}
+
+func (g *GlobalSearch) selected() (SearchResult, bool) {
+	i := g.cursor
+	for group := range g.groups {
+		shown := g.shown(group)
+		if i < len(shown) {
+			return shown[i], true
+		}
+		i -= len(shown)
+	}
+	return SearchResult{}, false
+}
+
+func (g *GlobalSearch) View() string {
+	if !g.active {
+		return ""
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	heading := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213"))
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render(g.title),
+		g.input.View(),
+		"",
+	}
+
+	switch {
+	case g.err != nil:
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+g.err.Error()))
+	case strings.TrimSpace(g.input.Value()) == "":
+		lines = append(lines, dim.Render("Start typing to search across all your todos, notes and events"))
+		if g.substring {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).
+				Render("⚠️  Substring search only: this build has no FTS5 (build with make or -tags sqlite_fts5)"))
+		}
+	case g.count() == 0:
+		lines = append(lines, dim.Render("Nothing found 🤷"))
+	}
+
+	names := [3]string{"✅ Todos", "📝 Notes", "📅 Events"}
+	index := 0
+	for group, name := range names {
+		shown := g.shown(group)
+		if len(shown) == 0 {
+			continue
+		}
+
+		title := name
+		if more := len(g.groups[group]) - len(shown); more > 0 {
+			title += dim.Render(fmt.Sprintf(" (%d more)", more))
+		}
+		lines = append(lines, heading.Render(title))
+
+		for _, result := range shown {
+			style := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+			prefix := "  "
+			if index == g.cursor {
+				style = style.Background(lipgloss.Color("238")).Bold(true)
+				prefix = "→ "
+			}
+			lines = append(lines, style.Render(prefix+result.Title))
+			// Title hits need no snippet, the title already shows them
+			plain := strings.NewReplacer(models.SnippetStart, "", models.SnippetEnd, "").Replace(result.Snippet)
+			if plain != "" && plain != result.Title {
+				lines = append(lines, "    "+RenderSnippet(result.Snippet))
+			}
+			index++
+		}
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, dim.Render("↑/↓: choose • Enter: pick • Esc: close"))
+
+	searchBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(g.width - 4)
+
+	return searchBox.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,200 @@
+package components
+
+import (
+	"strings"
+
+	"prodBooster/internal/models"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ItemIcon returns the icon of the kind of item living on page
+func ItemIcon(page models.PageType) string {
+	switch page {
+	case models.PageTodos:
+		return "✅"
+	case models.PageNotes:
+		return "📝"
+	case models.PageCalendar:
+		return "📅"
+	default:
+		return "•"
+	}
+}
+
+// linkEntry is one line of the LinkMenu
+type linkEntry struct {
+	link     *models.Link
+	other    models.ItemRef // the item at the other end of the link
+	incoming bool           // other links to the item, not the other way round
+}
+
+// LinkMenu is an overlay listing the items linked to and from one item.
+// Enter picks one to jump to, see Chosen; x removes a link.
+type LinkMenu struct {
+	links         *models.LinkList
+	item          models.ItemRef
+	title         string
+	entries       []linkEntry
+	cursor        int
+	confirmUnlink bool // waiting for y/n before removing the selected link
+	chosen        *models.ItemRef
+	active        bool
+	err           error
+	width         int
+}
+
+func NewLinkMenu(links *models.LinkList) *LinkMenu {
+	return &LinkMenu{links: links, width: 80}
+}
+
+// Open shows the links of item
+func (l *LinkMenu) Open(item models.ItemRef) {
+	l.item = item
+	l.title, _ = l.links.Title(item)
+	l.active = true
+	l.chosen = nil
+	l.err = nil
+	l.cursor = 0
+	l.load()
+}
+
+func (l *LinkMenu) load() {
+	l.entries = nil
+	for _, link := range l.links.Outgoing(l.item) {
+		l.entries = append(l.entries, linkEntry{link: link, other: link.To})
+	}
+	for _, link := range l.links.Incoming(l.item) {
+		l.entries = append(l.entries, linkEntry{link: link, other: link.From, incoming: true})
+	}
+	l.cursor = max(min(l.cursor, len(l.entries)-1), 0)
+}
+
+func (l *LinkMenu) Close() {
+	l.active = false
+	l.confirmUnlink = false
+}
+
+func (l *LinkMenu) IsActive() bool {
+	return l.active
+}
+
+// Chosen returns the linked item picked to jump to, once
+func (l *LinkMenu) Chosen() (models.ItemRef, bool) {
+	if l.chosen == nil {
+		return models.ItemRef{}, false
+	}
+	chosen := *l.chosen
+	l.chosen = nil
+	return chosen, true
+}
+
+func (l *LinkMenu) SetWidth(width int) {
+	l.width = width
+}
+
+func (l *LinkMenu) Update(msg tea.Msg) (*LinkMenu, tea.Cmd) {
+	if !l.active {
+		return l, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return l, nil
+	}
+
+	if l.confirmUnlink {
+		if keyMsg.String() == "y" && l.cursor < len(l.entries) {
+			l.err = l.links.Remove(l.entries[l.cursor].link.ID)
+			l.load()
+		}
+		l.confirmUnlink = false
+		return l, nil
+	}
+
+	l.err = nil
+	switch keyMsg.String() {
+	case "esc", "o":
+		l.Close()
+	case "up", "k", "shift+tab":
+		if l.cursor > 0 {
+			l.cursor--
+		}
+	case "down", "j", "tab":
+		if l.cursor < len(l.entries)-1 {
+			l.cursor++
+		}
+	case "enter":
+		if l.cursor < len(l.entries) {
+			other := l.entries[l.cursor].other
+			l.Close()
+			l.chosen = &other
+		}
+	case "x":
+		if l.cursor < len(l.entries) {
+			l.confirmUnlink = true
+		}
+	}
+	return l, nil
+}
+
+func (l *LinkMenu) View() string {
+	if !l.active {
+		return ""
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	heading := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213"))
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Render("🔗 Links of " + l.title), ""}
+	if len(l.entries) == 0 {
+		lines = append(lines, dim.Render("Nothing linked yet. Press l on an item to link it to another one"), "")
+	}
+
+	for i, entry := range l.entries {
+		if i == 0 && !entry.incoming {
+			lines = append(lines, heading.Render("Linked items"))
+		}
+		if entry.incoming && (i == 0 || !l.entries[i-1].incoming) {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, heading.Render("Referenced by"))
+		}
+
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+		prefix := "  "
+		if i == l.cursor {
+			style = style.Background(lipgloss.Color("238")).Bold(true)
+			prefix = "→ "
+		}
+		title, _ := l.links.Title(entry.other)
+		line := style.Render(prefix + ItemIcon(entry.other.Page) + " " + title)
+		if entry.link.Wiki {
+			line += dim.Render(" [[wiki link]]")
+		}
+		lines = append(lines, line)
+	}
+	if len(l.entries) > 0 {
+		lines = append(lines, "")
+	}
+
+	if l.confirmUnlink {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render("⚠️  Remove this link? y: yes • any other key: cancel"))
+	} else {
+		lines = append(lines, dim.Render("↑/↓: choose • Enter: go there • x: unlink • Esc: close"))
+	}
+	if l.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+l.err.Error()))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(l.width - 4)
+
+	return box.Render(strings.Join(lines, "\n"))
+}
@@ -2,6 +2,7 @@ package components
 
 import (
 	"prodBooster/internal/models"
+	"prodBooster/internal/templates"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -12,6 +13,7 @@ import (
 type NoteForm struct {
 	noteList     *models.NoteList
 	titleInput   textinput.Model
+	tagsInput    textinput.Model
 	contentInput textarea.Model
 	focusIndex   int
 	width        int
@@ -19,6 +21,9 @@ type NoteForm struct {
 	isActive     bool
 	editMode     bool
 	editingID    int
+	source       string       // what a new note started from, shown in the heading
+	notebookID   int          // notebook new notes go in, 0 for none
+	created      *models.Note // note the last submit added, see Created
 }
 
 func NewNoteForm(noteList *models.NoteList) *NoteForm {
@@ -26,13 +31,17 @@ func NewNoteForm(noteList *models.NoteList) *NoteForm {
 	ti.Placeholder = "Give your note a title (e.g., Meeting notes, Ideas, Reminders)"
 	ti.Focus()
 
+	tgi := textinput.New()
+	tgi.Placeholder = "Tags? → #ideas #meeting (optional)"
+
 	ta := textarea.New()
 	ta.Blur()
-	ta.Placeholder = "Write anything here... your thoughts, plans, random ideas 💭\n\nCtrl+S to save • Esc to cancel"
+	ta.Placeholder = "Write anything here... your thoughts, plans, random ideas 💭\nLink another note with [[Its Title]]\n\nCtrl+S to save • Esc to cancel"
 
 	return &NoteForm{
 		noteList:     noteList,
 		titleInput:   ti,
+		tagsInput:    tgi,
 		contentInput: ta,
 		focusIndex:   0,
 		isActive:     false,
@@ -60,16 +69,8 @@ func (f *NoteForm) Update(msg tea.Msg) (*NoteForm, tea.Cmd) {
 			return f, nil
 
 		case "tab":
-			if f.focusIndex == 0 {
-				f.focusIndex = 1
-				f.titleInput.Blur()
-				cmd = f.contentInput.Focus()
-			} else {
-				f.focusIndex = 0
-				f.contentInput.Blur()
-				cmd = f.titleInput.Focus()
-			}
-			return f, cmd
+			f.focusIndex = (f.focusIndex + 1) % 3
+			return f, f.focusField()
 
 		case "ctrl+s":
 			// Submit form
@@ -82,15 +83,35 @@ func (f *NoteForm) Update(msg tea.Msg) (*NoteForm, tea.Cmd) {
 		}
 	}
 
-	if f.focusIndex == 0 {
+	switch f.focusIndex {
+	case 0:
 		f.titleInput, cmd = f.titleInput.Update(msg)
-	} else {
+	case 1:
+		f.tagsInput, cmd = f.tagsInput.Update(msg)
+	case 2:
 		f.contentInput, cmd = f.contentInput.Update(msg)
 	}
 
 	return f, cmd
 }
 
+// focusField moves the cursor to the input matching focusIndex
+func (f *NoteForm) focusField() tea.Cmd {
+	f.titleInput.Blur()
+	f.tagsInput.Blur()
+	f.contentInput.Blur()
+
+	switch f.focusIndex {
+	case 0:
+		return f.titleInput.Focus()
+	case 1:
+		return f.tagsInput.Focus()
+	case 2:
+		return f.contentInput.Focus()
+	}
+	return nil
+}
+
 func (f *NoteForm) View() string {
 	if !f.isActive {
 		return ""
@@ -105,6 +126,8 @@ func (f *NoteForm) View() string {
 	title := "✍️  New Note"
 	if f.editMode {
 		title = "✏️  Edit Note"
+	} else if f.source != "" {
+		title += " • " + f.source
 	}
 
 	focusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
@@ -117,8 +140,15 @@ func (f *NoteForm) View() string {
 		titleLabel = normalStyle.Render(titleLabel)
 	}
 
-	contentLabel := "📝 Your thoughts"
+	tagsLabel := "🏷️  Tags"
 	if f.focusIndex == 1 {
+		tagsLabel = focusStyle.Render("→ " + tagsLabel)
+	} else {
+		tagsLabel = normalStyle.Render(tagsLabel)
+	}
+
+	contentLabel := "📝 Your thoughts"
+	if f.focusIndex == 2 {
 		contentLabel = focusStyle.Render("→ " + contentLabel)
 	} else {
 		contentLabel = normalStyle.Render(contentLabel)
@@ -130,6 +160,9 @@ func (f *NoteForm) View() string {
 		titleLabel,
 		f.titleInput.View(),
 		"",
+		tagsLabel,
+		f.tagsInput.View(),
+		"",
 		contentLabel,
 		f.contentInput.View(),
 		"",
@@ -143,7 +176,7 @@ func (f *NoteForm) SetSize(width, height int) {
 	f.width = width
 	f.height = height
 	f.contentInput.SetWidth(width - 10)
-	f.contentInput.SetHeight(height - 15)
+	f.contentInput.SetHeight(height - 18)
 }
 
 func (f *NoteForm) Activate() {
@@ -162,10 +195,13 @@ func (f *NoteForm) IsActive() bool {
 
 func (f *NoteForm) Reset() {
 	f.titleInput.SetValue("")
+	f.tagsInput.SetValue("")
 	f.contentInput.SetValue("")
 	f.focusIndex = 0
+	f.focusField()
 	f.editMode = false
 	f.editingID = 0
+	f.source = ""
 }
 
 func (f *NoteForm) Submit() error {
@@ -176,17 +212,48 @@ func (f *NoteForm) Submit() error {
 		return nil // Don't submit empty notes
 	}
 
+	tags := models.ParseTags(f.tagsInput.Value())
+
 	if f.editMode {
-		return f.noteList.Update(f.editingID, title, content)
+		return f.noteList.Update(f.editingID, title, content, tags)
+	}
+
+	note, err := f.noteList.CreateIn(f.notebookID, title, content, tags)
+	f.created = note
+	return err
+}
+
+// SetNotebook sets the notebook new notes go in, 0 for none
+func (f *NoteForm) SetNotebook(id int) {
+	f.notebookID = id
+}
+
+// Created returns the note the form added when it was last submitted, once
+func (f *NoteForm) Created() (*models.Note, bool) {
+	if f.created == nil {
+		return nil, false
 	}
+	created := f.created
+	f.created = nil
+	return created, true
+}
 
-	return f.noteList.Add(title, content)
+// ActivateFrom opens the form for a new note filled in from a template.
+// source says where it came from, e.g. the template and event names.
+func (f *NoteForm) ActivateFrom(t templates.Template, vars templates.Vars, source string) {
+	title, tags, content := t.Expand(vars)
+	f.titleInput.SetValue(title)
+	f.tagsInput.SetValue(models.FormatTags(tags))
+	f.contentInput.SetValue(content)
+	f.source = source
+	f.Activate()
 }
 
 func (f *NoteForm) LoadForEdit(note *models.Note) {
 	f.editMode = true
 	f.editingID = note.ID
 	f.titleInput.SetValue(note.Title)
+	f.tagsInput.SetValue(models.FormatTags(note.Tags))
 	f.contentInput.SetValue(note.Content)
 	f.isActive = true
 	f.titleInput.Focus()
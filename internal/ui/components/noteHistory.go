@@ -0,0 +1,329 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"prodBooster/internal/linediff"
+	"prodBooster/internal/models"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// noteHistoryListWidth is the width of the revision list left of the diff
+const noteHistoryListWidth = 34
+
+// NoteHistory is an overlay listing the saved versions of a note, newest
+// first, next to a line diff of the selected one. The diff is against the
+// version before it, or against the one marked with space, so any two can
+// be compared. r restores the selected version, see Restored.
+type NoteHistory struct {
+	notes          *models.NoteList
+	note           *models.Note
+	revisions      []models.NoteRevision // index 0 is the note as it is now, ID 0
+	cursor         int
+	base           int // revision marked to compare against, -1 for none
+	confirmRestore bool
+	restored       *models.NoteRevision
+	viewer         viewport.Model
+	shown          string // cursor and base the viewer content is for
+	active         bool
+	err            error
+	width          int
+	height         int
+}
+
+func NewNoteHistory(notes *models.NoteList) *NoteHistory {
+	return &NoteHistory{
+		notes:  notes,
+		base:   -1,
+		viewer: viewport.New(0, 0),
+		width:  80,
+		height: 24,
+	}
+}
+
+// Open shows the history of note
+func (h *NoteHistory) Open(note *models.Note) {
+	h.note = note
+	h.active = true
+	h.cursor = 0
+	h.base = -1
+	h.confirmRestore = false
+	h.restored = nil
+	h.shown = ""
+
+	revisions, err := h.notes.Revisions(note.ID)
+	h.err = err
+
+	// The newest revision is normally what the note says now; it is listed
+	// as the current version instead of twice. Undo can leave the note
+	// different from it, then both are listed.
+	current := models.NoteRevision{NoteID: note.ID, Title: note.Title, Content: note.Content}
+	if len(revisions) > 0 && revisions[0].Title == note.Title && revisions[0].Content == note.Content {
+		current.CreatedAt = revisions[0].CreatedAt
+		revisions = revisions[1:]
+	}
+	h.revisions = append([]models.NoteRevision{current}, revisions...)
+}
+
+func (h *NoteHistory) Close() {
+	h.active = false
+	h.confirmRestore = false
+}
+
+func (h *NoteHistory) IsActive() bool {
+	return h.active
+}
+
+// Restored returns the revision the note was just restored to, once
+func (h *NoteHistory) Restored() (models.NoteRevision, bool) {
+	if h.restored == nil {
+		return models.NoteRevision{}, false
+	}
+	restored := *h.restored
+	h.restored = nil
+	return restored, true
+}
+
+func (h *NoteHistory) SetSize(width, height int) {
+	h.width = width
+	h.height = height
+	h.viewer.Width = max(width-noteHistoryListWidth-10, 20)
+	h.viewer.Height = max(height-12, 3)
+	h.shown = ""
+}
+
+func (h *NoteHistory) Update(msg tea.Msg) (*NoteHistory, tea.Cmd) {
+	if !h.active {
+		return h, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return h, nil
+	}
+
+	if h.confirmRestore {
+		h.confirmRestore = false
+		if keyMsg.String() == "y" {
+			h.restore()
+		}
+		return h, nil
+	}
+
+	h.err = nil
+	switch keyMsg.String() {
+	case "esc", "H":
+		h.Close()
+	case "up", "k":
+		if h.cursor > 0 {
+			h.cursor--
+		}
+	case "down", "j":
+		if h.cursor < len(h.revisions)-1 {
+			h.cursor++
+		}
+	case " ":
+		// Mark the selected version to compare the others against
+		if h.base == h.cursor {
+			h.base = -1
+		} else {
+			h.base = h.cursor
+		}
+	case "r":
+		if h.cursor > 0 {
+			h.confirmRestore = true
+		}
+	case "pgdown", "J":
+		if keyMsg.String() == "J" {
+			h.viewer.ScrollDown(1)
+		} else {
+			h.viewer.HalfPageDown()
+		}
+	case "pgup", "K":
+		if keyMsg.String() == "K" {
+			h.viewer.ScrollUp(1)
+		} else {
+			h.viewer.HalfPageUp()
+		}
+	}
+	return h, nil
+}
+
+// restore puts the selected version back and closes the history
+func (h *NoteHistory) restore() {
+	revision := h.revisions[h.cursor]
+	if err := h.notes.RestoreRevision(h.note.ID, revision.ID); err != nil {
+		h.err = err
+		return
+	}
+	h.restored = &revision
+	h.Close()
+}
+
+// compared returns the older and newer version the diff is between. from
+// is nil when the selected version is the oldest and nothing is marked.
+func (h *NoteHistory) compared() (from, to *models.NoteRevision) {
+	to = &h.revisions[h.cursor]
+	switch {
+	case h.base >= 0 && h.base != h.cursor:
+		from = &h.revisions[h.base]
+		if h.base < h.cursor {
+			// The marked one is newer, still show old → new
+			from, to = to, from
+		}
+	case h.cursor+1 < len(h.revisions):
+		from = &h.revisions[h.cursor+1]
+	}
+	return from, to
+}
+
+// label names a version in the list and the diff heading
+func (h *NoteHistory) label(index int) string {
+	revision := h.revisions[index]
+	when := revision.CreatedAt.Format("Jan 2 2006 15:04:05")
+	switch {
+	case index == 0 && revision.CreatedAt.IsZero():
+		return "Current"
+	case index == 0:
+		return "Current • " + when
+	case revision.CreatedAt.IsZero():
+		// Saved by the first edit, which doesn't know when the text it
+		// replaced was written
+		return "Original • undated"
+	}
+	return when
+}
+
+func (h *NoteHistory) indexOf(revision *models.NoteRevision) int {
+	for i := range h.revisions {
+		if &h.revisions[i] == revision {
+			return i
+		}
+	}
+	return -1
+}
+
+func (h *NoteHistory) View() string {
+	if !h.active {
+		return ""
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	from, to := h.compared()
+	diff := h.diff(from, to)
+	added, removed := linediff.Count(diff)
+
+	// Revision list
+	list := []string{lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213")).
+		Render(fmt.Sprintf("🕘 Versions (%d)", len(h.revisions))), ""}
+	// Two lines a version, scrolled so the cursor stays in sight
+	fits := max((h.height-12)/2, 1)
+	first := max(h.cursor-fits+1, 0)
+	for i := first; i < min(first+fits, len(h.revisions)); i++ {
+		revision := h.revisions[i]
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).MaxWidth(noteHistoryListWidth - 2)
+		marker := "  "
+		if i == h.base {
+			marker = "◆ "
+			style = style.Foreground(lipgloss.Color("51"))
+		}
+		if i == h.cursor {
+			style = style.Background(lipgloss.Color("238")).Bold(true)
+		}
+		list = append(list, style.Render(marker+h.label(i)))
+		list = append(list, dim.MaxWidth(noteHistoryListWidth-2).
+			Render(fmt.Sprintf("    %d words • %s", len(strings.Fields(revision.Content)), revision.Title)))
+	}
+	listPane := lipgloss.NewStyle().
+		Width(noteHistoryListWidth).
+		Height(h.height - 8).
+		Render(strings.Join(list, "\n"))
+
+	// Diff of the compared versions
+	heading := "First saved version of the note"
+	if from != nil {
+		heading = h.label(h.indexOf(from)) + "  →  " + h.label(h.indexOf(to))
+	}
+	stats := lipgloss.NewStyle().Foreground(lipgloss.Color("46")).Render(fmt.Sprintf("+%d", added)) + " " +
+		lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("-%d", removed))
+
+	key := fmt.Sprintf("%d/%d", h.cursor, h.base)
+	if key != h.shown {
+		h.viewer.SetContent(h.renderDiff(from, to, diff))
+		h.viewer.GotoTop()
+		h.shown = key
+	}
+	diffPane := lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.NewStyle().Bold(true).Render(heading)+"  "+stats,
+		"",
+		h.viewer.View(),
+	)
+
+	lines := []string{
+		lipgloss.NewStyle().Bold(true).Render("📜 History of " + h.note.Title),
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, listPane, "  ", diffPane),
+		"",
+	}
+	switch {
+	case h.confirmRestore:
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).
+			Render("↩️  Restore the version of "+h.label(h.cursor)+"? y: yes • any other key: cancel"))
+	default:
+		lines = append(lines, dim.Render("↑/↓: choose • space: compare against this • r: restore • pgup/pgdown: scroll • Esc: close"))
+	}
+	if h.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+h.err.Error()))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(0, 1).
+		Width(h.width - 4).
+		Height(h.height - 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// diff compares the content of two versions, from nil meaning nothing
+func (h *NoteHistory) diff(from, to *models.NoteRevision) []linediff.Line {
+	before := ""
+	if from != nil {
+		before = from.Content
+	}
+	return linediff.Diff(before, to.Content)
+}
+
+// renderDiff colors a diff, with a line for a changed title on top
+func (h *NoteHistory) renderDiff(from, to *models.NoteRevision, diff []linediff.Line) string {
+	width := h.viewer.Width
+	added := lipgloss.NewStyle().Foreground(lipgloss.Color("46")).MaxWidth(width)
+	removed := lipgloss.NewStyle().Foreground(lipgloss.Color("196")).MaxWidth(width)
+	same := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).MaxWidth(width)
+
+	var lines []string
+	if from != nil && from.Title != to.Title {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("214")).MaxWidth(width).
+			Render("✎ Title: "+from.Title+" → "+to.Title), "")
+	}
+	for _, line := range diff {
+		switch line.Op {
+		case linediff.Insert:
+			lines = append(lines, added.Render("+ "+line.Text))
+		case linediff.Delete:
+			lines = append(lines, removed.Render("- "+line.Text))
+		default:
+			lines = append(lines, same.Render("  "+line.Text))
+		}
+	}
+	if len(diff) == 0 {
+		lines = append(lines, same.Render("(empty)"))
+	} else if a, r := linediff.Count(diff); a == 0 && r == 0 && (from == nil || from.Title == to.Title) {
+		lines = append([]string{same.Render("No changes to the text"), ""}, lines...)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,295 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"prodBooster/internal/models"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NotebookPanelWidth is the width of the notebooks sidebar, the same as the
+// saved views one it shares a column with
+const NotebookPanelWidth = ViewsPanelWidth
+
+// notebookEntry is one line of the NotebookPanel
+type notebookEntry struct {
+	id    int // notebook id, models.AllNotes or models.Unfiled
+	depth int
+	name  string
+}
+
+// NotebookPanel is the notebook tree of the notes page. Pressing B on the
+// page activates it to pick the notebook shown, see Chosen; M activates it
+// to pick where the selected note moves, see MoveTarget.
+type NotebookPanel struct {
+	notebooks     *models.NotebookList
+	current       int // notebook the page shows
+	cursor        int
+	input         textinput.Model
+	naming        bool // typing the name of a new notebook
+	renaming      bool // typing a new name for the selected notebook
+	confirmDelete bool // waiting for y/n before deleting the selected notebook
+	moving        bool // picking where a note goes instead of what to show
+	chosen        *int
+	moveTo        *int
+	active        bool
+	err           error
+	height        int
+}
+
+func NewNotebookPanel(notebooks *models.NotebookList) *NotebookPanel {
+	input := textinput.New()
+	input.CharLimit = 40
+	input.Width = NotebookPanelWidth - 6
+
+	return &NotebookPanel{
+		notebooks: notebooks,
+		current:   models.AllNotes,
+		input:     input,
+		height:    20,
+	}
+}
+
+// entries lists All notes, Unfiled and then the notebook tree
+func (b *NotebookPanel) entries() []notebookEntry {
+	entries := []notebookEntry{{id: models.AllNotes, name: "All notes"}, {id: models.Unfiled, name: "Unfiled"}}
+	notebooks, depths := b.notebooks.Tree()
+	for i, notebook := range notebooks {
+		entries = append(entries, notebookEntry{id: notebook.ID, depth: depths[i], name: notebook.Name})
+	}
+	return entries
+}
+
+// Activate opens the panel to pick the notebook the page shows
+func (b *NotebookPanel) Activate() {
+	b.open(false)
+}
+
+// ActivateMove opens the panel to pick where the selected note moves
+func (b *NotebookPanel) ActivateMove() {
+	b.open(true)
+}
+
+func (b *NotebookPanel) open(moving bool) {
+	b.active = true
+	b.moving = moving
+	b.chosen = nil
+	b.moveTo = nil
+	b.err = nil
+	b.cursor = 0
+	for i, entry := range b.entries() {
+		if entry.id == b.current {
+			b.cursor = i
+		}
+	}
+}
+
+func (b *NotebookPanel) Deactivate() {
+	b.active = false
+	b.naming = false
+	b.renaming = false
+	b.confirmDelete = false
+	b.input.Blur()
+}
+
+func (b *NotebookPanel) IsActive() bool {
+	return b.active
+}
+
+// Visible reports whether the page should show the panel: while it is
+// active or once there are notebooks
+func (b *NotebookPanel) Visible() bool {
+	return b.active || len(b.notebooks.Notebooks) > 0
+}
+
+// SetCurrent tells the panel which notebook the page shows, to mark it
+func (b *NotebookPanel) SetCurrent(id int) {
+	b.current = id
+}
+
+// Chosen returns the notebook picked to show, once
+func (b *NotebookPanel) Chosen() (int, bool) {
+	if b.chosen == nil {
+		return 0, false
+	}
+	chosen := *b.chosen
+	b.chosen = nil
+	return chosen, true
+}
+
+// MoveTarget returns the notebook picked to move the note to, once
+func (b *NotebookPanel) MoveTarget() (int, bool) {
+	if b.moveTo == nil {
+		return 0, false
+	}
+	moveTo := *b.moveTo
+	b.moveTo = nil
+	return moveTo, true
+}
+
+func (b *NotebookPanel) SetHeight(height int) {
+	b.height = height
+}
+
+func (b *NotebookPanel) Update(msg tea.Msg) (*NotebookPanel, tea.Cmd) {
+	if !b.active {
+		return b, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return b, nil
+	}
+	entries := b.entries()
+	selected := entries[min(b.cursor, len(entries)-1)]
+
+	if b.naming || b.renaming {
+		switch keyMsg.String() {
+		case "esc":
+			b.naming, b.renaming = false, false
+			b.input.Blur()
+			return b, nil
+		case "enter":
+			name := b.input.Value()
+			if b.naming {
+				b.err = b.notebooks.Add(name, max(selected.id, models.Unfiled))
+			} else {
+				b.err = b.notebooks.Rename(selected.id, name)
+			}
+			if b.err == nil {
+				b.naming, b.renaming = false, false
+				b.input.Blur()
+			}
+			return b, nil
+		}
+		var cmd tea.Cmd
+		b.input, cmd = b.input.Update(msg)
+		return b, cmd
+	}
+
+	if b.confirmDelete {
+		if keyMsg.String() == "y" {
+			b.err = b.notebooks.Remove(selected.id)
+			if b.err == nil {
+				if selected.id == b.current {
+					// The page can't keep showing it
+					all := models.AllNotes
+					b.chosen = &all
+				}
+				b.cursor = max(b.cursor-1, 0)
+			}
+		}
+		b.confirmDelete = false
+		return b, nil
+	}
+
+	b.err = nil
+	switch keyMsg.String() {
+	case "esc", "B", "M":
+		b.Deactivate()
+	case "up", "k":
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case "down", "j":
+		if b.cursor < len(entries)-1 {
+			b.cursor++
+		}
+	case "enter":
+		id := selected.id
+		if !b.moving {
+			b.chosen = &id
+			b.Deactivate()
+		} else if id == models.AllNotes {
+			b.err = errors.New("pick a notebook, or Unfiled")
+		} else {
+			b.moveTo = &id
+			b.Deactivate()
+		}
+	case "a":
+		// New notebook inside the selected one
+		b.naming = true
+		b.input.Placeholder = "Name the notebook"
+		b.input.SetValue("")
+		b.input.Focus()
+	case "r":
+		if selected.id > 0 {
+			b.renaming = true
+			b.input.Placeholder = "New name"
+			b.input.SetValue(selected.name)
+			b.input.Focus()
+		}
+	case "d":
+		if selected.id > 0 {
+			b.confirmDelete = true
+		}
+	}
+	return b, nil
+}
+
+func (b *NotebookPanel) View() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	entries := b.entries()
+
+	heading := "📚 Notebooks"
+	if b.moving {
+		heading = "📦 Move note to..."
+	}
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213")).Render(heading), ""}
+
+	for i, entry := range entries {
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).MaxWidth(NotebookPanelWidth - 2)
+		marker := " "
+		if entry.id == b.current {
+			marker = "●"
+			style = style.Foreground(lipgloss.Color("51"))
+		}
+		if b.active && i == b.cursor {
+			style = style.Background(lipgloss.Color("238")).Bold(true)
+		}
+
+		icon := "📁"
+		switch entry.id {
+		case models.AllNotes:
+			icon = "📒"
+		case models.Unfiled:
+			icon = "📥"
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%s%s %s %s (%d)", marker, strings.Repeat("  ", entry.depth), icon, entry.name, b.notebooks.Count(entry.id))))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case b.naming || b.renaming:
+		lines = append(lines, b.input.View(), dim.Render("Enter: save • Esc: cancel"))
+	case b.confirmDelete:
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render("⚠️  Delete this notebook? y: yes • any other key: cancel"))
+	case b.moving:
+		lines = append(lines, dim.Render("Enter: move here\na: new notebook • Esc: cancel"))
+	case b.active:
+		lines = append(lines, dim.Render("Enter: show • a: new inside\nr: rename • d: delete\nEsc: close"))
+	default:
+		lines = append(lines, dim.Render("B: pick a notebook\nM: move note"))
+	}
+
+	if b.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+b.err.Error()))
+	}
+
+	border := lipgloss.Color("63")
+	if b.active {
+		border = lipgloss.Color("213")
+	}
+	return lipgloss.NewStyle().
+		Width(NotebookPanelWidth - 2).
+		Height(b.height).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,74 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	"prodBooster/internal/recur"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// previewOccurrences is how many upcoming dates the repeat preview lists
+const previewOccurrences = 3
+
+// parseRecurrence turns the repeat input into the RRULE that gets stored.
+// Empty input means the item doesn't repeat.
+func parseRecurrence(input string) (string, error) {
+	if strings.TrimSpace(input) == "" {
+		return "", nil
+	}
+
+	rule, err := recur.Parse(input)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+// recurrenceInput is what the repeat input shows for a stored RRULE: the
+// friendly description when it parses back to the same rule, else the RRULE
+func recurrenceInput(rrule string) string {
+	rule, err := recur.Parse(rrule)
+	if err != nil {
+		return rrule
+	}
+	if again, err := recur.Parse(rule.Describe()); err == nil && again.String() == rule.String() {
+		return rule.Describe()
+	}
+	return rule.String()
+}
+
+// recurrencePreview renders the live line under repeat inputs: how the rule
+// was understood and the next few dates counted from start
+func recurrencePreview(input, hint string, start time.Time) string {
+	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
+	if strings.TrimSpace(input) == "" {
+		return hintStyle.Render("  " + hint)
+	}
+
+	rule, err := recur.Parse(input)
+	if err != nil {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  ✗ " + err.Error())
+	}
+
+	var next []string
+	after := start
+	for len(next) < previewOccurrences {
+		t, ok, err := rule.Next(start, after)
+		if err != nil {
+			return lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("  ✗ " + err.Error())
+		}
+		if !ok {
+			break
+		}
+		next = append(next, t.Format("Mon Jan 2"))
+		after = t
+	}
+
+	preview := "  → " + rule.Describe()
+	if len(next) > 0 {
+		preview += " · then " + strings.Join(next, ", ")
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("45")).Render(preview)
+}
@@ -1,45 +1,70 @@
 package components
 
 import (
+	"errors"
+	"prodBooster/internal/models"
+	"prodBooster/internal/query"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// FilterType is a quick filter of a page: an index into the filters the
+// page gave its search bar, FilterNone (all items) being the first
 type FilterType int
 
-const (
-	FilterNone FilterType = iota
-	FilterPending
-	FilterCompleted
-	FilterHighPriority
-	FilterMediumPriority
-	FilterLowPriority
-	FilterToday
-	FilterOverdue
-)
+const FilterNone FilterType = 0
+
+// FilterOption is how a page's quick filter is shown in the search bar
+type FilterOption struct {
+	Label string
+	Color string
+}
+
+var allItems = FilterOption{Label: "🌍 All Items", Color: "240"}
+
+// filterOption returns how filter f of a page is shown, all items for a
+// filter the page doesn't have
+func filterOption(filters []FilterOption, f FilterType) FilterOption {
+	if f <= FilterNone || int(f) > len(filters) {
+		return allItems
+	}
+	return filters[f-1]
+}
 
 type SearchBar struct {
-	input  textinput.Model
-	active bool
-	query  string
-	filter FilterType
-	width  int
+	input     textinput.Model
+	active    bool
+	query     string
+	parsed    *query.Query       // last query that parsed
+	err       *query.SyntaxError // why the typed query doesn't parse, nil if it does
+	searchErr error              // why the page's search for the query failed, see SetSearchError
+	kind      query.Kind         // items searched, decides which fields complete
+	knownTags []string           // offered as completions while typing a #tag
+	filters   []FilterOption     // the page's quick filters, after all items
+	filter    FilterType
+	width     int
 }
 
-func NewSearchBar() *SearchBar {
+// NewSearchBar creates a search bar over kind items. Tab cycles through
+// all items and then filters, so filter i (from 0) is FilterType i+1.
+func NewSearchBar(kind query.Kind, filters []FilterOption) *SearchBar {
 	input := textinput.New()
-	input.Placeholder = "🔍 Type to search... (Esc to close, Tab for filters)"
-	input.CharLimit = 100
+	input.Placeholder = "🔍 Words, \"exact phrases\", #tags, priority:high due:<=fri -done... (Esc to close)"
+	input.CharLimit = 200
 	input.Width = 60
 
 	return &SearchBar{
-		input:  input,
-		active: false,
-		filter: FilterNone,
-		width:  80,
+		input:   input,
+		active:  false,
+		parsed:  &query.Query{},
+		kind:    kind,
+		filters: filters,
+		filter:  FilterNone,
+		width:   80,
 	}
 }
 
@@ -51,11 +76,49 @@ func (s *SearchBar) Activate() {
 func (s *SearchBar) Deactivate() {
 	s.active = false
 	s.input.Blur()
-	s.query = ""
+	s.setQuery("")
 	s.input.SetValue("")
 	s.filter = FilterNone
 }
 
+// setQuery parses the raw query. A query with a syntax error keeps the
+// last one that parsed in effect.
+func (s *SearchBar) setQuery(raw string) {
+	s.query = raw
+	parsed, err := query.Parse(raw, time.Now())
+	if err != nil {
+		if !errors.As(err, &s.err) {
+			s.err = &query.SyntaxError{Msg: err.Error()}
+		}
+		return
+	}
+	s.parsed = parsed
+	s.err = nil
+}
+
+// SetSearchError tells the bar why running its query failed, e.g. a
+// full-text index the database can't read, and opens the bar to show it.
+// nil clears it.
+func (s *SearchBar) SetSearchError(err error) {
+	s.searchErr = err
+	if err != nil && !s.active {
+		s.Activate()
+	}
+}
+
+// Apply puts a saved query and filter in effect without opening the bar
+func (s *SearchBar) Apply(raw string, filter FilterType) {
+	s.input.SetValue(raw)
+	s.input.CursorEnd()
+	s.setQuery(raw)
+	s.filter = filter
+}
+
+// SetKnownTags sets the tags offered as completions while typing a #tag
+func (s *SearchBar) SetKnownTags(tags []string) {
+	s.knownTags = tags
+}
+
 func (s *SearchBar) IsActive() bool {
 	return s.active
 }
@@ -85,21 +148,28 @@ func (s *SearchBar) Update(msg tea.Msg) (*SearchBar, tea.Cmd) {
 			s.Deactivate()
 			return s, nil
 		case "enter":
-			// Apply search and close
-			s.query = s.input.Value()
+			// Apply search and close, unless it doesn't parse
+			s.setQuery(s.input.Value())
+			if s.err != nil {
+				return s, nil
+			}
 			s.active = false
 			s.input.Blur()
 			return s, nil
 		case "tab":
-			// Cycle through filters
-			s.filter = (s.filter + 1) % 8
+			// Complete the word being typed, otherwise cycle through filters
+			if suggestions := s.suggestions(); len(suggestions) > 0 {
+				s.complete(suggestions[0])
+				return s, nil
+			}
+			s.filter = (s.filter + 1) % FilterType(len(s.filters)+1)
 			return s, nil
 		}
 	}
 
 	var cmd tea.Cmd
 	s.input, cmd = s.input.Update(msg)
-	s.query = s.input.Value()
+	s.setQuery(s.input.Value())
 
 	return s, cmd
 }
@@ -109,38 +179,10 @@ func (s *SearchBar) View() string {
 		return ""
 	}
 
-	filterLabel := ""
-	filterColor := "240"
-
-	switch s.filter {
-	case FilterPending:
-		filterLabel = "📋 Pending Only"
-		filterColor = "214"
-	case FilterCompleted:
-		filterLabel = "✅ Completed"
-		filterColor = "46"
-	case FilterHighPriority:
-		filterLabel = "🔥 High Priority"
-		filterColor = "196"
-	case FilterMediumPriority:
-		filterLabel = "📌 Medium Priority"
-		filterColor = "214"
-	case FilterLowPriority:
-		filterLabel = "💤 Low Priority"
-		filterColor = "45"
-	case FilterToday:
-		filterLabel = "📅 Due Today"
-		filterColor = "45"
-	case FilterOverdue:
-		filterLabel = "⚠️  Overdue"
-		filterColor = "196"
-	default:
-		filterLabel = "🌍 All Items"
-		filterColor = "240"
-	}
+	filter := filterOption(s.filters, s.filter)
 
 	filterStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(filterColor)).
+		Foreground(lipgloss.Color(filter.Color)).
 		Bold(true).
 		Padding(0, 1)
 
@@ -150,23 +192,102 @@ func (s *SearchBar) View() string {
 		Padding(1, 2).
 		Width(s.width - 4)
 
-	content := lipgloss.JoinVertical(lipgloss.Left,
+	lines := []string{
 		lipgloss.NewStyle().Bold(true).Render("🔍 Search & Filter"),
 		s.input.View(),
 		lipgloss.JoinHorizontal(lipgloss.Left,
 			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Filter: "),
-			filterStyle.Render(filterLabel),
+			filterStyle.Render(filter.Label),
 			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(" (Tab to change)"),
 		),
-	)
+	}
+
+	if s.err != nil {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+		// Point at the error when the input isn't scrolled past it
+		runes := []rune(s.query)
+		if s.err.Pos <= len(runes) && lipgloss.Width(s.query) < s.input.Width {
+			indent := lipgloss.Width(s.input.Prompt) + lipgloss.Width(string(runes[:s.err.Pos]))
+			lines = append(lines[:2], append([]string{errorStyle.Render(strings.Repeat(" ", indent) + "^")}, lines[2:]...)...)
+		}
+		lines = append(lines, errorStyle.Render("⚠️  "+s.err.Msg))
+	} else if s.searchErr != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  Search failed: "+s.searchErr.Error()))
+	}
+
+	if suggestions := s.suggestions(); len(suggestions) > 0 {
+		if len(suggestions) > 6 {
+			suggestions = suggestions[:6]
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Tab: "),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("141")).Bold(true).Render(suggestions[0]),
+			lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("  "+strings.Join(suggestions[1:], "  ")),
+		))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
 
 	return searchBox.Render(content)
 }
 
-// Match checks if a string matches the search query
-func (s *SearchBar) Match(text string) bool {
-	if s.query == "" {
-		return true
+// suggestions lists completions of the word being typed: field names,
+// field values and known tags
+func (s *SearchBar) suggestions() []string {
+	words := strings.Fields(s.query)
+	if len(words) == 0 || strings.HasSuffix(s.query, " ") {
+		return nil
+	}
+	return query.Complete(words[len(words)-1], s.kind, s.knownTags)
+}
+
+// complete replaces the word being typed with suggestion
+func (s *SearchBar) complete(suggestion string) {
+	words := strings.Fields(s.query)
+	value := strings.TrimSuffix(s.query, words[len(words)-1]) + suggestion
+	// A finished field name waits for its value, anything else is done
+	if !strings.HasSuffix(suggestion, ":") {
+		value += " "
+	}
+	s.input.SetValue(value)
+	s.input.CursorEnd()
+	s.setQuery(value)
+}
+
+// Text returns the query's top-level words and phrases, for full-text search
+func (s *SearchBar) Text() string {
+	return s.parsed.Text()
+}
+
+// Query returns the query in effect, for filtering items on everything
+// besides Text
+func (s *SearchBar) Query() *query.Query {
+	return s.parsed
+}
+
+var (
+	snippetStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("246")).Italic(true)
+	highlightStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("58")).Bold(true)
+)
+
+// RenderSnippet styles a search snippet, highlighting the marked hits
+func RenderSnippet(snippet string) string {
+	var b strings.Builder
+	for snippet != "" {
+		start := strings.Index(snippet, models.SnippetStart)
+		if start < 0 {
+			b.WriteString(snippetStyle.Render(snippet))
+			break
+		}
+		b.WriteString(snippetStyle.Render(snippet[:start]))
+		snippet = snippet[start+len(models.SnippetStart):]
+
+		end := strings.Index(snippet, models.SnippetEnd)
+		if end < 0 {
+			end = len(snippet)
+		}
+		b.WriteString(highlightStyle.Render(snippet[:end]))
+		snippet = strings.TrimPrefix(snippet[end:], models.SnippetEnd)
 	}
-	return strings.Contains(strings.ToLower(text), strings.ToLower(s.query))
+	return b.String()
 }
@@ -0,0 +1,139 @@
+package components
+
+import (
+	"strings"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/templates"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TemplatePicker is an overlay asking what a new note starts from: a blank
+// page or one of the templates. Enter picks one, see Chosen.
+type TemplatePicker struct {
+	templates []templates.Template // index 0 is the blank note
+	event     *models.Event        // event the note is written for, nil if none
+	cursor    int
+	chosen    *templates.Template
+	active    bool
+	err       error
+	width     int
+}
+
+func NewTemplatePicker() *TemplatePicker {
+	return &TemplatePicker{width: 60}
+}
+
+// Open shows the templates, reading them afresh. event is the event the
+// note is for, nil for a note of its own.
+func (t *TemplatePicker) Open(event *models.Event) {
+	t.event = event
+	t.active = true
+	t.chosen = nil
+	t.cursor = 0
+
+	list, err := templates.List()
+	t.err = err
+	t.templates = append([]templates.Template{{Name: "Blank note"}}, list...)
+}
+
+func (t *TemplatePicker) Close() {
+	t.active = false
+}
+
+func (t *TemplatePicker) IsActive() bool {
+	return t.active
+}
+
+// Chosen returns the picked template and the event the note is for, once
+func (t *TemplatePicker) Chosen() (templates.Template, *models.Event, bool) {
+	if t.chosen == nil {
+		return templates.Template{}, nil, false
+	}
+	chosen := *t.chosen
+	t.chosen = nil
+	return chosen, t.event, true
+}
+
+func (t *TemplatePicker) SetWidth(width int) {
+	t.width = width
+}
+
+func (t *TemplatePicker) Update(msg tea.Msg) (*TemplatePicker, tea.Cmd) {
+	if !t.active {
+		return t, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc":
+		t.Close()
+	case "up", "k", "shift+tab":
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case "down", "j", "tab":
+		if t.cursor < len(t.templates)-1 {
+			t.cursor++
+		}
+	case "enter":
+		chosen := t.templates[t.cursor]
+		t.chosen = &chosen
+		t.Close()
+	}
+	return t, nil
+}
+
+func (t *TemplatePicker) View() string {
+	if !t.active {
+		return ""
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	heading := "📋 Start the note from..."
+	if t.event != nil {
+		heading = "📋 Note for 📅 " + t.event.Title + ", start from..."
+	}
+	lines := []string{lipgloss.NewStyle().Bold(true).Render(heading), ""}
+
+	for i, tmpl := range t.templates {
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+		prefix := "  "
+		if i == t.cursor {
+			style = style.Background(lipgloss.Color("238")).Bold(true)
+			prefix = "→ "
+		}
+		icon := "📄 "
+		if i == 0 {
+			icon = "✨ "
+		}
+		line := style.Render(prefix + icon + tmpl.Name)
+		if tmpl.Title != "" {
+			line += dim.Render(" • " + tmpl.Title)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", dim.Render("↑/↓: choose • Enter: start writing • Esc: cancel"))
+	if templates.Dir() != "" {
+		lines = append(lines, dim.Render("Templates live in "+templates.Dir()))
+	}
+	if t.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+t.err.Error()))
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(t.width - 4)
+
+	return box.Render(strings.Join(lines, "\n"))
+}
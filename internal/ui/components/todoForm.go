@@ -1,7 +1,10 @@
 package components
 
 import (
+	"prodBooster/internal/dateparse"
 	"prodBooster/internal/models"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,18 +12,27 @@ import (
 )
 
 type TodoForm struct {
-	todoList   *models.TodoList
-	titleInput textinput.Model
-	descInput  textinput.Model
-	priority   models.Priority
-	focusIndex int
-	width      int
-	height     int
-	isActive   bool
-	editMode   bool
-	editingID  int
+	todoList    *models.TodoList
+	titleInput  textinput.Model
+	descInput   textinput.Model
+	dueInput    textinput.Model
+	repeatInput textinput.Model
+	tagsInput   textinput.Model
+	priority    models.Priority
+	focusIndex  int
+	width       int
+	height      int
+	isActive    bool
+	editMode    bool
+	editingID   int
+	parent      *models.Todo // set while adding or editing a subtask
+	err         error
 }
 
+// dueEndOfDay is the time given to deadlines that only name a day:
+// "due friday" means by the end of friday.
+const dueEndOfDay = 23*time.Hour + 59*time.Minute
+
 func NewTodoForm(todoList *models.TodoList) *TodoForm {
 	ti := textinput.New()
 	ti.Placeholder = "What needs to be done? (e.g., Buy groceries, Finish report)"
@@ -29,14 +41,26 @@ func NewTodoForm(todoList *models.TodoList) *TodoForm {
 	di := textinput.New()
 	di.Placeholder = "Any extra details? (optional)"
 
+	dui := textinput.New()
+	dui.Placeholder = "When is it due? → tomorrow 5pm, fri, in 3 days (optional)"
+
+	ri := textinput.New()
+	ri.Placeholder = "Repeat? → daily, weekdays, every 2 weeks, monthly on 15 (optional)"
+
+	tgi := textinput.New()
+	tgi.Placeholder = "Tags? → #work #errands (optional)"
+
 	return &TodoForm{
-		todoList:   todoList,
-		titleInput: ti,
-		descInput:  di,
-		priority:   models.PriorityMedium,
-		focusIndex: 0,
-		isActive:   false,
-		editMode:   false,
+		todoList:    todoList,
+		titleInput:  ti,
+		descInput:   di,
+		dueInput:    dui,
+		repeatInput: ri,
+		tagsInput:   tgi,
+		priority:    models.PriorityMedium,
+		focusIndex:  0,
+		isActive:    false,
+		editMode:    false,
 	}
 }
 
@@ -61,40 +85,32 @@ func (f *TodoForm) Update(msg tea.Msg) (*TodoForm, tea.Cmd) {
 
 		case "tab", "shift+tab":
 			f.focusIndex++
-			if f.focusIndex > 2 {
+			if f.focusIndex > 5 {
 				f.focusIndex = 0
 			}
-
-			f.titleInput.Blur()
-			f.descInput.Blur()
-
-			if f.focusIndex == 0 {
-				cmd = f.titleInput.Focus()
-			} else if f.focusIndex == 1 {
-				cmd = f.descInput.Focus()
-			}
-
-			return f, cmd
+			f.skipRepeat()
+			return f, f.focusField()
 
 		case "up":
-			if f.focusIndex == 2 {
+			if f.focusIndex == 5 {
 				if f.priority < models.PriorityHigh {
 					f.priority++
 				}
 			}
 
 		case "down":
-			if f.focusIndex == 2 {
+			if f.focusIndex == 5 {
 				if f.priority > models.PriorityLow {
 					f.priority--
 				}
 			}
 
 		case "enter":
-			if f.focusIndex == 2 {
-				// Submit form
+			if f.focusIndex == 5 {
+				// Submit form, keep it open if the input can't be saved
 				if err := f.Submit(); err != nil {
-					// TODO: Show error
+					f.err = err
+					return f, nil
 				}
 				f.isActive = false
 				f.Reset()
@@ -102,25 +118,59 @@ func (f *TodoForm) Update(msg tea.Msg) (*TodoForm, tea.Cmd) {
 			} else {
 				// Move to next field
 				f.focusIndex++
-				if f.focusIndex == 1 {
-					return f, f.descInput.Focus()
-				} else if f.focusIndex == 2 {
-					f.titleInput.Blur()
-					f.descInput.Blur()
-				}
+				f.skipRepeat()
+				return f, f.focusField()
 			}
 		}
 	}
 
-	if f.focusIndex == 0 {
+	switch f.focusIndex {
+	case 0:
 		f.titleInput, cmd = f.titleInput.Update(msg)
-	} else if f.focusIndex == 1 {
+	case 1:
 		f.descInput, cmd = f.descInput.Update(msg)
+	case 2:
+		f.dueInput, cmd = f.dueInput.Update(msg)
+	case 3:
+		f.repeatInput, cmd = f.repeatInput.Update(msg)
+	case 4:
+		f.tagsInput, cmd = f.tagsInput.Update(msg)
 	}
 
 	return f, cmd
 }
 
+// skipRepeat moves focus past the repeat field for subtasks, which repeat
+// along with their parent
+func (f *TodoForm) skipRepeat() {
+	if f.focusIndex == 3 && f.parent != nil {
+		f.focusIndex++
+	}
+}
+
+// focusField moves the cursor to the input matching focusIndex
+func (f *TodoForm) focusField() tea.Cmd {
+	f.titleInput.Blur()
+	f.descInput.Blur()
+	f.dueInput.Blur()
+	f.repeatInput.Blur()
+	f.tagsInput.Blur()
+
+	switch f.focusIndex {
+	case 0:
+		return f.titleInput.Focus()
+	case 1:
+		return f.descInput.Focus()
+	case 2:
+		return f.dueInput.Focus()
+	case 3:
+		return f.repeatInput.Focus()
+	case 4:
+		return f.tagsInput.Focus()
+	}
+	return nil
+}
+
 func (f *TodoForm) View() string {
 	if !f.isActive {
 		return ""
@@ -136,11 +186,22 @@ func (f *TodoForm) View() string {
 	if f.editMode {
 		title = "Edit Todo"
 	}
+	if f.parent != nil {
+		title = "New Subtask of “" + f.parent.Title + "”"
+		if f.editMode {
+			title = "Edit Subtask of “" + f.parent.Title + "”"
+		}
+	}
 
 	focusStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 	normalStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 	hintStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Italic(true)
 
+	repeatPreview := recurrencePreview(f.repeatInput.Value(), "💡 Optional - finishing it schedules the next one", f.repeatStart())
+	if f.parent != nil {
+		repeatPreview = hintStyle.Render("  💡 Subtasks repeat along with their parent")
+	}
+
 	titleLabel := "📝 What's the task?"
 	if f.focusIndex == 0 {
 		titleLabel = focusStyle.Render("→ " + titleLabel)
@@ -151,8 +212,23 @@ func (f *TodoForm) View() string {
 		descLabel = focusStyle.Render("→ " + descLabel)
 	}
 
-	priorityLabel := "⭐ How urgent is this?"
+	dueLabel := "📅 Deadline"
 	if f.focusIndex == 2 {
+		dueLabel = focusStyle.Render("→ " + dueLabel)
+	}
+
+	repeatLabel := "🔁 Repeat"
+	if f.focusIndex == 3 {
+		repeatLabel = focusStyle.Render("→ " + repeatLabel)
+	}
+
+	tagsLabel := "🏷️  Tags"
+	if f.focusIndex == 4 {
+		tagsLabel = focusStyle.Render("→ " + tagsLabel)
+	}
+
+	priorityLabel := "⭐ How urgent is this?"
+	if f.focusIndex == 5 {
 		priorityLabel = focusStyle.Render("→ " + priorityLabel)
 	} else {
 		priorityLabel = normalStyle.Render(priorityLabel)
@@ -168,9 +244,29 @@ func (f *TodoForm) View() string {
 		f.descInput.View(),
 		hintStyle.Render("  💡 Optional - add context, notes, or anything helpful"),
 		"",
+		dueLabel,
+		f.dueInput.View(),
+		datePreview(f.dueInput.Value(), "💡 Optional - leave empty for no deadline, a day alone means end of day", parseDue),
+		"",
+		repeatLabel,
+		f.repeatInput.View(),
+		repeatPreview,
+		"",
+		tagsLabel,
+		f.tagsInput.View(),
+		hintStyle.Render("  💡 Optional - separate with spaces or commas"),
+		"",
 		priorityLabel+" "+f.priority.String()+" (use ↑↓ to change)",
 		hintStyle.Render("  🔥 High = Do this ASAP! | 📌 Medium = Normal stuff | 💤 Low = When you have time"),
 		"",
+	)
+
+	if f.err != nil {
+		content = lipgloss.JoinVertical(lipgloss.Left, content,
+			lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+f.err.Error()))
+	}
+
+	content = lipgloss.JoinVertical(lipgloss.Left, content,
 		"",
 		normalStyle.Render("✨ Press Enter to save • Tab to move around • Esc to cancel"),
 	)
@@ -188,6 +284,12 @@ func (f *TodoForm) Activate() {
 	f.titleInput.Focus()
 }
 
+// ActivateSubtask opens the form to add a checklist item under parent
+func (f *TodoForm) ActivateSubtask(parent *models.Todo) {
+	f.parent = parent
+	f.Activate()
+}
+
 func (f *TodoForm) Deactivate() {
 	f.isActive = false
 	f.Reset()
@@ -200,10 +302,16 @@ func (f *TodoForm) IsActive() bool {
 func (f *TodoForm) Reset() {
 	f.titleInput.SetValue("")
 	f.descInput.SetValue("")
+	f.dueInput.SetValue("")
+	f.repeatInput.SetValue("")
+	f.tagsInput.SetValue("")
 	f.priority = models.PriorityMedium
 	f.focusIndex = 0
 	f.editMode = false
 	f.editingID = 0
+	f.parent = nil
+	f.err = nil
+	f.focusField()
 }
 
 func (f *TodoForm) Submit() error {
@@ -214,11 +322,54 @@ func (f *TodoForm) Submit() error {
 		return nil // Don't submit empty todos
 	}
 
+	dueTime, err := parseDueDate(f.dueInput.Value())
+	if err != nil {
+		return err
+	}
+
+	recurrence, err := parseRecurrence(f.repeatInput.Value())
+	if err != nil {
+		return err
+	}
+
+	tags := models.ParseTags(f.tagsInput.Value())
+
 	if f.editMode {
-		return f.todoList.Update(f.editingID, title, desc, f.priority, nil)
+		return f.todoList.Update(f.editingID, title, desc, f.priority, dueTime, tags, recurrence)
+	}
+
+	if f.parent != nil {
+		return f.todoList.AddSubtask(f.parent.ID, title, desc, f.priority, dueTime, tags)
+	}
+
+	return f.todoList.Add(title, desc, f.priority, dueTime, tags, recurrence)
+}
+
+// repeatStart is the date the repeat preview counts from: the deadline if
+// one is typed, otherwise now (which is also what completing it will use)
+func (f *TodoForm) repeatStart() time.Time {
+	if due, err := parseDueDate(f.dueInput.Value()); err == nil && due != nil {
+		return *due
+	}
+	return time.Now()
+}
+
+// parseDueDate turns the deadline input into a due time. Empty input means
+// no deadline, which is also how an existing one gets cleared.
+func parseDueDate(input string) (*time.Time, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	due, err := parseDue(input)
+	if err != nil {
+		return nil, err
 	}
+	return &due, nil
+}
 
-	return f.todoList.Add(title, desc, f.priority, nil)
+func parseDue(input string) (time.Time, error) {
+	return dateparse.Parse(input, time.Now(), dueEndOfDay)
 }
 
 func (f *TodoForm) LoadForEdit(todo *models.Todo) {
@@ -226,7 +377,17 @@ func (f *TodoForm) LoadForEdit(todo *models.Todo) {
 	f.editingID = todo.ID
 	f.titleInput.SetValue(todo.Title)
 	f.descInput.SetValue(todo.Description)
+	if todo.DueTime != nil {
+		f.dueInput.SetValue(todo.DueTime.Local().Format(dateparse.Layout))
+	}
+	if todo.IsRecurring() {
+		f.repeatInput.SetValue(recurrenceInput(todo.Recurrence))
+	}
+	f.tagsInput.SetValue(models.FormatTags(todo.Tags))
 	f.priority = todo.Priority
+	if todo.IsSubtask() {
+		f.parent = f.todoList.Get(todo.ParentID)
+	}
 	f.isActive = true
 	f.titleInput.Focus()
 }
@@ -0,0 +1,269 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"prodBooster/internal/models"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ViewsPanelWidth is the width of the saved views sidebar
+const ViewsPanelWidth = 26
+
+// ViewsPanel is the saved views sidebar of a page. Pressing v on the page
+// activates it; number keys then apply a view, see Chosen. alt+1 to alt+9
+// apply one straight from the page, see Shortcut.
+type ViewsPanel struct {
+	views         *models.ViewList
+	page          models.PageType
+	filters       []FilterOption   // the page's quick filters, for describing views
+	current       models.SavedView // search in effect on the page, what a saves
+	cursor        int
+	input         textinput.Model
+	naming        bool // typing the name of a new view
+	confirmDelete bool // waiting for y/n before deleting the selected view
+	chosen        *models.SavedView
+	active        bool
+	err           error
+	height        int
+}
+
+func NewViewsPanel(views *models.ViewList, page models.PageType, filters []FilterOption) *ViewsPanel {
+	input := textinput.New()
+	input.Placeholder = "Name this view"
+	input.CharLimit = 40
+	input.Width = ViewsPanelWidth - 6
+
+	return &ViewsPanel{
+		views:   views,
+		page:    page,
+		filters: filters,
+		input:   input,
+		height:  20,
+	}
+}
+
+func (v *ViewsPanel) Activate() {
+	v.active = true
+	v.chosen = nil
+	v.err = nil
+	v.cursor = min(v.cursor, max(len(v.views.ForPage(v.page))-1, 0))
+}
+
+func (v *ViewsPanel) Deactivate() {
+	v.active = false
+	v.naming = false
+	v.confirmDelete = false
+	v.input.Blur()
+}
+
+func (v *ViewsPanel) IsActive() bool {
+	return v.active
+}
+
+// Visible reports whether the page should show the panel: while it is
+// active or once the page has saved views
+func (v *ViewsPanel) Visible() bool {
+	return v.active || len(v.views.ForPage(v.page)) > 0
+}
+
+// SetCurrent tells the panel the page's search, sort included, so it can
+// save it and mark the view it came from
+func (v *ViewsPanel) SetCurrent(query string, filter int, sort string) {
+	v.current = models.SavedView{Query: query, Filter: filter, Sort: sort}
+}
+
+// Chosen returns the view picked to apply, once
+func (v *ViewsPanel) Chosen() (*models.SavedView, bool) {
+	if v.chosen == nil {
+		return nil, false
+	}
+	chosen := v.chosen
+	v.chosen = nil
+	return chosen, true
+}
+
+// Shortcut returns the view key applies from the page: alt+1 to alt+9 pick
+// the views in the order the panel lists them. Plain 1-5 switch pages.
+func (v *ViewsPanel) Shortcut(key string) (*models.SavedView, bool) {
+	n, ok := strings.CutPrefix(key, "alt+")
+	if !ok || len(n) != 1 || n[0] < '1' || n[0] > '9' {
+		return nil, false
+	}
+	views := v.views.ForPage(v.page)
+	if i := int(n[0] - '1'); i < len(views) {
+		return views[i], true
+	}
+	return nil, false
+}
+
+func (v *ViewsPanel) SetHeight(height int) {
+	v.height = height
+}
+
+func (v *ViewsPanel) Update(msg tea.Msg) (*ViewsPanel, tea.Cmd) {
+	if !v.active {
+		return v, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+	views := v.views.ForPage(v.page)
+
+	if v.naming {
+		switch keyMsg.String() {
+		case "esc":
+			v.naming = false
+			v.input.Blur()
+			return v, nil
+		case "enter":
+			name := strings.TrimSpace(v.input.Value())
+			v.err = v.views.Add(name, v.page, v.current.Query, v.current.Filter, v.current.Sort)
+			if v.err == nil {
+				v.naming = false
+				v.input.Blur()
+				v.cursor = len(views)
+			}
+			return v, nil
+		}
+		var cmd tea.Cmd
+		v.input, cmd = v.input.Update(msg)
+		return v, cmd
+	}
+
+	if v.confirmDelete {
+		if keyMsg.String() == "y" && v.cursor < len(views) {
+			v.err = v.views.Remove(views[v.cursor].ID)
+			v.cursor = max(min(v.cursor, len(views)-2), 0)
+		}
+		v.confirmDelete = false
+		return v, nil
+	}
+
+	v.err = nil
+	switch key := keyMsg.String(); key {
+	case "esc", "v":
+		v.Deactivate()
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "down", "j":
+		if v.cursor < len(views)-1 {
+			v.cursor++
+		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(key[0] - '1'); i < len(views) {
+			v.cursor = i
+			v.choose(views[i])
+		}
+	case "enter":
+		if v.cursor < len(views) {
+			v.choose(views[v.cursor])
+		}
+	case "a":
+		// Save the page's current search as a new view
+		v.naming = true
+		v.input.SetValue("")
+		v.input.Focus()
+	case "d":
+		if v.cursor < len(views) {
+			v.confirmDelete = true
+		}
+	case "c":
+		if v.cursor < len(views) {
+			v.err = v.views.ToggleDashboard(views[v.cursor].ID)
+		}
+	}
+	return v, nil
+}
+
+func (v *ViewsPanel) choose(view *models.SavedView) {
+	v.chosen = view
+	v.Deactivate()
+}
+
+func (v *ViewsPanel) View() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	views := v.views.ForPage(v.page)
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213")).Render("⭐ Views"), ""}
+	if len(views) == 0 {
+		lines = append(lines, dim.Render("No saved views yet"))
+	}
+	for i, view := range views {
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+		marker := " "
+		if view.Query == v.current.Query && view.Filter == v.current.Filter && view.Sort == v.current.Sort {
+			// The page shows this view right now
+			marker = "●"
+			style = style.Foreground(lipgloss.Color("51"))
+		}
+		if v.active && i == v.cursor {
+			style = style.Background(lipgloss.Color("238")).Bold(true)
+		}
+		line := fmt.Sprintf("%d %s %s", i+1, marker, view.Name)
+		if view.OnDashboard {
+			line += " 📊"
+		}
+		lines = append(lines, style.Render(line))
+	}
+	lines = append(lines, "")
+
+	switch {
+	case v.naming:
+		lines = append(lines,
+			"Save current search:",
+			v.input.View(),
+			dim.Render(describeSearch(v.current, v.filters)),
+			dim.Render("Enter: save • Esc: cancel"),
+		)
+	case v.confirmDelete:
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render("⚠️  Delete this view? y: yes • any other key: cancel"))
+	case v.active:
+		lines = append(lines, dim.Render("1-9/Enter: apply\na: save current search\nd: delete • c: dashboard card\nEsc: close"))
+	default:
+		// 1-5 switch pages until the sidebar has focus
+		lines = append(lines, dim.Render("alt+1-9: apply • v: pick"))
+	}
+
+	if v.err != nil {
+		lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+v.err.Error()))
+	}
+
+	border := lipgloss.Color("63")
+	if v.active {
+		border = lipgloss.Color("213")
+	}
+	return lipgloss.NewStyle().
+		Width(ViewsPanelWidth - 2).
+		Height(v.height).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// describeSearch summarises what a view would save
+func describeSearch(view models.SavedView, filters []FilterOption) string {
+	var parts []string
+	if view.Query != "" {
+		parts = append(parts, "🔍 "+view.Query)
+	}
+	if view.Filter != 0 {
+		parts = append(parts, filterOption(filters, FilterType(view.Filter)).Label)
+	}
+	if view.Sort != "" {
+		parts = append(parts, "↕ "+view.Sort)
+	}
+	if len(parts) == 0 {
+		return "everything, default order"
+	}
+	return strings.Join(parts, " • ")
+}
@@ -3,24 +3,55 @@ package pages
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"prodBooster/internal/models"
+	"prodBooster/internal/query"
 	"prodBooster/internal/ui/components"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Quick filters of the calendar search bar
+const (
+	filterEventsToday components.FilterType = iota + 1
+	filterEventsThisWeek
+	filterEventsPast
+	filterEventsUpcoming
+	filterEventsWithLocation
+)
+
+var eventFilters = []components.FilterOption{
+	{Label: "📅 Today", Color: "214"},
+	{Label: "🗓️  This Week", Color: "45"},
+	{Label: "⏪ Past", Color: "240"},
+	{Label: "⏩ Upcoming", Color: "147"},
+	{Label: "📍 Has Location", Color: "213"},
+}
+
 // eventItem implements list.Item interface
 type eventItem struct {
 	event *models.Event
 }
 
 func (e eventItem) Title() string {
-	return "📅 " + e.event.Title
+	title := "📅 " + e.event.Title
+	if e.event.IsRecurring() {
+		title += " 🔁"
+		if _, ok := e.event.Rule(); !ok {
+			// Shown once at its start, the details say why
+			title += " ⚠️"
+		}
+	}
+	if e.event.Pinned {
+		title += pinMark
+	}
+	return title
 }
 
 func (e eventItem) Description() string {
@@ -72,26 +103,44 @@ func (d eventDelegate) Render(w io.Writer, m list.Model, index int, item list.It
 		style = style.Background(lipgloss.Color("238"))
 	}
 
-	fmt.Fprint(w, style.Render(event.Title()))
+	fmt.Fprint(w, style.Render(event.Title())+renderTags(event.event.Tags, index == m.Index()))
 }
 
+// scopeAction is an edit or delete on a repeating event that waits for the
+// user to pick which occurrences it applies to
+type scopeAction int
+
+const (
+	scopeNone scopeAction = iota
+	scopeEdit
+	scopeDelete
+)
+
 type CalendarPage struct {
 	EventList    *models.EventList
+	links        *models.LinkList
 	form         *components.EventForm
 	searchBar    *components.SearchBar
+	hits         map[int]models.SearchHit // full-text matches, nil when not searching
+	views        *components.ViewsPanel
+	order        int // index into eventOrders
 	list         list.Model
 	width        int
 	height       int
 	sidebarWidth int
+	pending      scopeAction
+	pendingEvent *models.Event
+	message      string // why the last pin or scoped delete failed, cleared on the next key
 }
 
-func NewCalendarPage(eventList_ *models.EventList) *CalendarPage {
+func NewCalendarPage(eventList_ *models.EventList, viewList_ *models.ViewList, linkList_ *models.LinkList) *CalendarPage {
 	// Sort events initially - today > this week > future > past
-	sortEvents(eventList_.Events)
+	events := eventList_.Timeline(time.Now())
+	sortEvents(events)
 
 	// Create list items from events
-	items := make([]list.Item, len(eventList_.Events))
-	for i, event := range eventList_.Events {
+	items := make([]list.Item, len(events))
+	for i, event := range events {
 		items[i] = eventItem{event: event}
 	}
 
@@ -105,8 +154,10 @@ func NewCalendarPage(eventList_ *models.EventList) *CalendarPage {
 
 	return &CalendarPage{
 		EventList:    eventList_,
+		links:        linkList_,
 		form:         components.NewEventForm(eventList_),
-		searchBar:    components.NewSearchBar(),
+		searchBar:    components.NewSearchBar(query.Events, eventFilters),
+		views:        components.NewViewsPanel(viewList_, models.PageCalendar, eventFilters),
 		list:         l,
 		width:        80,
 		height:       24,
@@ -146,9 +197,37 @@ func (p *CalendarPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 		return p, cmd
 	}
 
+	// If the saved views sidebar is active, route to it
+	if p.views.IsActive() {
+		updatedViews, cmd := p.views.Update(msg)
+		p.views = updatedViews
+		if view, ok := p.views.Chosen(); ok {
+			p.order = applyView(p.searchBar, view, eventOrders)
+			p.updateListItems()
+			p.list.Select(0)
+		}
+		return p, cmd
+	}
+
+	// Waiting for "which occurrences?" on a repeating event
+	if p.pending != scopeNone {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			p.applyScope(msg.String())
+		}
+		return p, nil
+	}
+
 	// Normal page navigation
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		p.message = ""
+		if view, ok := p.views.Shortcut(msg.String()); ok {
+			// Apply a saved view without opening the sidebar
+			p.order = applyView(p.searchBar, view, eventOrders)
+			p.updateListItems()
+			p.list.Select(0)
+			return p, nil
+		}
 		switch msg.String() {
 		case "n":
 			// Create new event
@@ -157,12 +236,20 @@ func (p *CalendarPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 		case "e":
 			// Edit selected event
 			if item, ok := p.list.SelectedItem().(eventItem); ok {
-				p.form.LoadForEdit(item.event)
+				if item.event.IsOccurrence() {
+					p.pending, p.pendingEvent = scopeEdit, item.event
+				} else {
+					p.form.LoadForEdit(item.event)
+				}
 			}
 
 		case "d", "delete":
 			// Delete selected event
 			if item, ok := p.list.SelectedItem().(eventItem); ok {
+				if item.event.IsOccurrence() {
+					p.pending, p.pendingEvent = scopeDelete, item.event
+					return p, nil
+				}
 				if err := p.EventList.Remove(item.event.ID); err != nil {
 					// TODO: Handle error display
 				}
@@ -170,10 +257,42 @@ func (p *CalendarPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 				p.list.Select(0) // Reset to first item
 			}
 
+		case "p":
+			// Pin the selected event (the whole series if it repeats) to
+			// the top, or unpin it
+			if item, ok := p.list.SelectedItem().(eventItem); ok {
+				if err := p.EventList.TogglePinned(item.event.ID); err != nil {
+					p.message = "⚠️  " + err.Error()
+				}
+				p.updateListItems()
+				p.selectEvent(item.event)
+			}
+			return p, nil
+
 		case "/":
 			// Activate search
+			p.searchBar.SetKnownTags(p.EventList.AllTags())
 			p.searchBar.Activate()
 			return p, nil
+
+		case "s":
+			// Cycle the sort order
+			p.order = (p.order + 1) % len(eventOrders)
+			p.updateListItems()
+			p.list.Select(0)
+			return p, nil
+
+		case "v":
+			// Pick or save a view
+			p.views.Activate()
+			return p, nil
+
+		case "N":
+			// Write a note for the selected event, on the notes page
+			if item, ok := p.list.SelectedItem().(eventItem); ok {
+				event := item.event
+				return p, func() tea.Msg { return NewNoteMsg{Event: event} }
+			}
 		}
 	}
 
@@ -187,52 +306,161 @@ func (p *CalendarPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 	return p, cmd
 }
 
+// applyScope finishes the pending edit or delete of a repeating event:
+// o = only this occurrence, f = this and all following, a = whole series
+func (p *CalendarPage) applyScope(key string) {
+	action, event := p.pending, p.pendingEvent
+	p.pending, p.pendingEvent = scopeNone, nil
+
+	if action == scopeEdit {
+		switch key {
+		case "o":
+			p.form.LoadOccurrenceForEdit(event, components.ScopeOccurrence)
+		case "f":
+			p.form.LoadOccurrenceForEdit(event, components.ScopeFuture)
+		case "a":
+			if series := p.EventList.Get(event.ID); series != nil {
+				p.form.LoadForEdit(series)
+			}
+		}
+		return
+	}
+
+	var err error
+	switch key {
+	case "o":
+		err = p.EventList.SkipOccurrence(event.ID, event.Occurrence)
+	case "f":
+		err = p.EventList.EndSeriesBefore(event.ID, event.Occurrence)
+	case "a":
+		err = p.EventList.Remove(event.ID)
+	default:
+		return
+	}
+	if err != nil {
+		p.message = "⚠️  " + err.Error()
+	}
+	p.updateListItems()
+	p.list.Select(0) // Reset to first item
+}
+
+// Refresh picks up changes made on other pages
+func (p *CalendarPage) Refresh() {
+	p.updateListItems()
+}
+
+// Focus selects the event with the given id, the first listed occurrence
+// for a series, clearing the search
+func (p *CalendarPage) Focus(id int) {
+	p.searchBar.Deactivate()
+	p.updateListItems()
+	for i, item := range p.list.Items() {
+		if item.(eventItem).event.ID == id {
+			p.list.Select(i)
+			p.EventList.Selected = i
+			return
+		}
+	}
+}
+
+// selectEvent moves the list selection to the event, or the same
+// occurrence of it
+func (p *CalendarPage) selectEvent(event *models.Event) {
+	for i, item := range p.list.Items() {
+		listed := item.(eventItem).event
+		if listed.ID == event.ID && listed.StartTime.Equal(event.StartTime) {
+			p.list.Select(i)
+			p.EventList.Selected = i
+			return
+		}
+	}
+}
+
 // updateListItems refreshes the list with current events and filters
 func (p *CalendarPage) updateListItems() {
-	now := time.Now()
+	filteredEvents, hits, err := findEvents(p.EventList, p.searchBar.Query(), p.searchBar.GetFilter(), eventOrders[p.order], time.Now())
+	p.hits = hits
+	p.searchBar.SetSearchError(err)
+	p.list.Title = listTitle("📅 My Calendar", eventOrders, p.order)
+	p.views.SetCurrent(p.searchBar.GetQuery(), int(p.searchBar.GetFilter()), eventOrders[p.order])
+
+	// Convert to list items
+	items := make([]list.Item, len(filteredEvents))
+	for i, event := range filteredEvents {
+		items[i] = eventItem{event: event}
+	}
+
+	p.list.SetItems(items)
+}
+
+// findEvents returns the events and occurrences matching a search, sorted
+// by order, and the full-text hits (nil when the query has no text). When
+// the full-text search fails nothing matches and the error comes back.
+func findEvents(eventList *models.EventList, q *query.Query, filter components.FilterType, order string, now time.Time) ([]*models.Event, map[int]models.SearchHit, error) {
 	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	todayEnd := todayStart.Add(24 * time.Hour)
 
+	hits, err := searchHits(q.Text(), eventList.Search)
+
 	filteredEvents := []*models.Event{}
-	for _, event := range p.EventList.Events {
+	for _, event := range eventList.Timeline(now) {
 		// Apply text search
-		if !p.searchBar.Match(event.Title + " " + event.Content + " " + event.Location) {
+		if _, ok := hits[event.ID]; hits != nil && !ok {
+			continue
+		}
+		// Apply fields, tags and the rest of the query
+		if !q.MatchEvent(event) {
 			continue
 		}
 
-		// Apply filter (reuse filter types for calendar context)
-		filter := p.searchBar.GetFilter()
+		// Apply filter
 		switch filter {
-		case components.FilterToday:
-			// Events happening today
+		case filterEventsToday:
 			if !event.StartTime.After(todayStart) || !event.StartTime.Before(todayEnd) {
 				continue
 			}
-		case components.FilterOverdue:
-			// Past events
+		case filterEventsThisWeek:
+			// Today and the six days after it
+			if event.StartTime.Before(todayStart) || !event.StartTime.Before(todayStart.AddDate(0, 0, 7)) {
+				continue
+			}
+		case filterEventsPast:
 			if !event.StartTime.Before(now) {
 				continue
 			}
-		case components.FilterPending:
-			// Future events
+		case filterEventsUpcoming:
 			if !event.StartTime.After(now) {
 				continue
 			}
+		case filterEventsWithLocation:
+			if strings.TrimSpace(event.Location) == "" {
+				continue
+			}
 		}
 
 		filteredEvents = append(filteredEvents, event)
 	}
 
-	// Sort events by time: today > this week > future > past
+	// Sort events by time: today > this week > future > past,
+	// best match first when searching
 	sortEvents(filteredEvents)
-
-	// Convert to list items
-	items := make([]list.Item, len(filteredEvents))
-	for i, event := range filteredEvents {
-		items[i] = eventItem{event: event}
+	switch order {
+	case "start":
+		sort.SliceStable(filteredEvents, func(i, j int) bool {
+			return filteredEvents[i].StartTime.Before(filteredEvents[j].StartTime)
+		})
+	case "title":
+		sort.SliceStable(filteredEvents, func(i, j int) bool {
+			return strings.ToLower(filteredEvents[i].Title) < strings.ToLower(filteredEvents[j].Title)
+		})
+	default:
+		if hits != nil {
+			sortByRank(filteredEvents, hits, func(event *models.Event) int { return event.ID })
+		}
 	}
+	pinnedEventsFirst(filteredEvents, eventList, now)
 
-	p.list.SetItems(items)
+	return filteredEvents, hits, err
 }
 
 // sortEvents sorts by priority: today > this week > future > past
@@ -290,6 +518,7 @@ func (p *CalendarPage) SetSize(width, height int) {
 	}
 
 	p.list.SetSize(p.sidebarWidth-4, height-6) // Account for borders and padding
+	p.views.SetHeight(height - 6)
 	p.form.SetSize(width, height)
 }
 
@@ -317,7 +546,7 @@ func (p *CalendarPage) View() string {
 	sidebar := sidebarStyle.Render(p.list.View())
 
 	// Content pane with selected event detail
-	contentWidth := p.width - p.sidebarWidth - 4
+	contentWidth := p.width - p.sidebarWidth - 4 - viewsWidth(p.views)
 	contentStyle := lipgloss.NewStyle().
 		Width(contentWidth).
 		Height(p.height-6).
@@ -330,8 +559,8 @@ func (p *CalendarPage) View() string {
 		content = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
 			Render("✨ No events scheduled!\n\nPress 'n' to plan something 📅")
-	} else if p.EventList.Selected < len(p.EventList.Events) {
-		event := p.EventList.Events[p.EventList.Selected]
+	} else if item, ok := p.list.SelectedItem().(eventItem); ok {
+		event := item.event
 
 		// Title
 		titleStyle := lipgloss.NewStyle().
@@ -363,6 +592,12 @@ func (p *CalendarPage) View() string {
 			endTimeStr = "🕑 Ends " + event.EndTime.Format("Monday, Jan 2 at 3:04 PM")
 		}
 
+		zoneStr := ""
+		if event.HasForeignZone() {
+			zone := event.Zone()
+			zoneStr = "🌍 " + event.StartTime.In(zone).Format("Mon 3:04 PM MST") + " in " + event.Timezone
+		}
+
 		locationStr := ""
 		if event.Location != "" {
 			locationStr = "📍 " + event.Location
@@ -380,6 +615,31 @@ func (p *CalendarPage) View() string {
 				lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(endTimeStr))
 		}
 
+		if zoneStr != "" {
+			contentParts = append(contentParts,
+				lipgloss.NewStyle().Foreground(lipgloss.Color("147")).Render(zoneStr))
+		}
+
+		if rule, ok := event.Rule(); ok {
+			repeatStr := "🔁 Repeats " + rule.Describe()
+			if n := len(event.Exceptions); n > 0 {
+				repeatStr += fmt.Sprintf(" · %d skipped", n)
+			}
+			contentParts = append(contentParts,
+				lipgloss.NewStyle().Foreground(lipgloss.Color("81")).Render(repeatStr))
+		}
+		if err := event.RuleError(time.Now()); err != nil {
+			contentParts = append(contentParts,
+				lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  Can't show its dates: "+err.Error()))
+		}
+
+		if len(event.Tags) > 0 {
+			contentParts = append(contentParts, tagsLine(event.Tags))
+		}
+		if line := snippetLine(p.hits, event.ID); line != "" {
+			contentParts = append(contentParts, line)
+		}
+
 		if locationStr != "" {
 			contentParts = append(contentParts, "",
 				lipgloss.NewStyle().Foreground(lipgloss.Color("45")).Render(locationStr))
@@ -393,6 +653,10 @@ func (p *CalendarPage) View() string {
 					Render(event.Content))
 		}
 
+		if lines := linkLines(p.links, models.ItemRef{Page: models.PageCalendar, ID: event.ID}); lines != "" {
+			contentParts = append(contentParts, lines)
+		}
+
 		content = lipgloss.JoinVertical(lipgloss.Left, contentParts...)
 	} else {
 		content = lipgloss.NewStyle().
@@ -403,10 +667,23 @@ func (p *CalendarPage) View() string {
 	// Add help text
 	helpText := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
-		Render("✨ n: new event • e: edit • d: delete • /: search • ↑/↓: browse • q: quit")
+		Render("✨ n: new event • e: edit • d: delete • p: pin • N: note for event • /: search • s: sort • v: views • l: link • o: links • ↑/↓: browse • q: quit")
+
+	switch p.pending {
+	case scopeEdit:
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).
+			Render("🔁 Edit which? o: only this one • f: this and following • a: all of them • esc: cancel")
+	case scopeDelete:
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).
+			Render("🔁 Delete which? o: skip only this one • f: this and following • a: the whole series • esc: cancel")
+	default:
+		if p.message != "" {
+			helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("213")).Render(p.message)
+		}
+	}
 
 	// Combine sidebar and content
-	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, contentStyle.Render(content))
+	mainContent := withViews(p.views, lipgloss.JoinHorizontal(lipgloss.Top, sidebar, contentStyle.Render(content)))
 
 	// Build the view
 	return lipgloss.JoinVertical(lipgloss.Left,
@@ -416,6 +693,15 @@ func (p *CalendarPage) View() string {
 	)
 }
 
+// Selected returns the event under the cursor, occurrences standing in
+// for their series
+func (p *CalendarPage) Selected() (models.ItemRef, bool) {
+	if item, ok := p.list.SelectedItem().(eventItem); ok {
+		return models.ItemRef{Page: models.PageCalendar, ID: item.event.ID}, true
+	}
+	return models.ItemRef{}, false
+}
+
 func (p *CalendarPage) IsFormActive() bool {
-	return p.form.IsActive() || p.searchBar.IsActive()
+	return p.form.IsActive() || p.searchBar.IsActive() || p.views.IsActive() || p.pending != scopeNone
 }
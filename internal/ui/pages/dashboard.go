@@ -4,12 +4,14 @@ package pages
 import (
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"prodBooster/internal/models"
+	"prodBooster/internal/query"
 	"prodBooster/internal/ui/components"
 
 	"github.com/charmbracelet/lipgloss"
@@ -23,6 +25,9 @@ const (
 	focusNotes
 )
 
+// viewCardItems is how many items a saved view card lists
+const viewCardItems = 4
+
 type dashboardMode int
 
 const (
@@ -33,7 +38,10 @@ const (
 )
 
 // Dashboard list items
-type dashboardTodoItem struct{ todo *models.Todo }
+type dashboardTodoItem struct {
+	todo        *models.Todo
+	done, total int // subtask progress
+}
 
 func (t dashboardTodoItem) Title() string       { return t.todo.Title }
 func (t dashboardTodoItem) Description() string { return "" }
@@ -94,7 +102,18 @@ func (d dashboardTodoDelegate) Render(w io.Writer, m list.Model, index int, item
 		icon = "◐"
 	}
 
-	fmt.Fprint(w, style.Render(fmt.Sprintf("%s %s", icon, todo.Title())))
+	title := todo.Title()
+	if todo.todo.IsRecurring() {
+		title += " 🔁"
+	}
+	if todo.total > 0 {
+		title += fmt.Sprintf(" %d/%d", todo.done, todo.total)
+	}
+	if todo.todo.Pinned {
+		title += pinMark
+	}
+
+	fmt.Fprint(w, style.Render(fmt.Sprintf("%s %s", icon, title))+renderTags(todo.todo.Tags, index == m.Index()))
 }
 
 type dashboardEventDelegate struct{}
@@ -125,8 +144,16 @@ func (d dashboardEventDelegate) Render(w io.Writer, m list.Model, index int, ite
 		style = style.Background(lipgloss.Color("238"))
 	}
 
+	title := event.Title()
+	if event.event.IsRecurring() {
+		title += " 🔁"
+	}
+	if event.event.Pinned {
+		title += pinMark
+	}
+
 	timeStr := event.event.StartTime.Format("Jan 2 15:04")
-	fmt.Fprint(w, style.Render(fmt.Sprintf("📅 %s • %s", timeStr, event.Title())))
+	fmt.Fprint(w, style.Render(fmt.Sprintf("📅 %s • %s", timeStr, title))+renderTags(event.event.Tags, index == m.Index()))
 }
 
 type dashboardNoteDelegate struct{}
@@ -155,13 +182,22 @@ func (d dashboardNoteDelegate) Render(w io.Writer, m list.Model, index int, item
 		style = style.Background(lipgloss.Color("238"))
 	}
 
-	fmt.Fprint(w, style.Render(fmt.Sprintf("📝 %s", note.Title())))
+	icon := "📝"
+	if note.note.IsJournal() {
+		icon = "📓"
+	}
+	title := note.Title()
+	if note.note.Pinned {
+		title += pinMark
+	}
+	fmt.Fprint(w, style.Render(fmt.Sprintf("%s %s", icon, title))+renderTags(note.note.Tags, index == m.Index()))
 }
 
 type DashboardPage struct {
 	TodoList    *models.TodoList
 	NoteList    *models.NoteList
 	EventList   *models.EventList
+	Views       *models.ViewList
 	currentPage models.PageType
 	width       int
 	height      int
@@ -173,9 +209,10 @@ type DashboardPage struct {
 	todoForm    *components.TodoForm
 	eventForm   *components.EventForm
 	noteForm    *components.NoteForm
+	message     string // why the last pin failed, cleared on the next key
 }
 
-func NewDashboardPage(todoList_ *models.TodoList, noteList_ *models.NoteList, eventList_ *models.EventList) *DashboardPage {
+func NewDashboardPage(todoList_ *models.TodoList, noteList_ *models.NoteList, eventList_ *models.EventList, viewList_ *models.ViewList) *DashboardPage {
 	// Sort and create todo list
 	now := time.Now()
 	todayEnd := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
@@ -183,7 +220,12 @@ func NewDashboardPage(todoList_ *models.TodoList, noteList_ *models.NoteList, ev
 
 	todoItems := make([]list.Item, 0)
 	for _, todo := range todoList_.Todos {
-		todoItems = append(todoItems, dashboardTodoItem{todo: todo})
+		// Subtasks only count towards their parent's progress here
+		if todo.IsSubtask() {
+			continue
+		}
+		done, total := todoList_.Progress(todo.ID)
+		todoItems = append(todoItems, dashboardTodoItem{todo: todo, done: done, total: total})
 	}
 	todoListModel := list.New(todoItems, dashboardTodoDelegate{}, 0, 0)
 	todoListModel.Title = "Todos"
@@ -191,10 +233,11 @@ func NewDashboardPage(todoList_ *models.TodoList, noteList_ *models.NoteList, ev
 	todoListModel.SetFilteringEnabled(false)
 
 	// Sort and create event list
-	sortEvents(eventList_.Events)
+	events := eventList_.Timeline(now)
+	sortEvents(events)
 
 	eventItems := make([]list.Item, 0)
-	for _, event := range eventList_.Events {
+	for _, event := range events {
 		eventItems = append(eventItems, dashboardEventItem{event: event})
 	}
 	eventListModel := list.New(eventItems, dashboardEventDelegate{}, 0, 0)
@@ -218,6 +261,7 @@ func NewDashboardPage(todoList_ *models.TodoList, noteList_ *models.NoteList, ev
 		TodoList:    todoList_,
 		NoteList:    noteList_,
 		EventList:   eventList_,
+		Views:       viewList_,
 		currentPage: models.PageTypeDashboard(),
 		width:       80,
 		height:      24,
@@ -277,6 +321,7 @@ func (p *DashboardPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 	// Normal mode navigation
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		p.message = ""
 		switch msg.String() {
 		case "tab":
 			// Cycle focus: Todos -> Events -> Notes -> Todos
@@ -297,6 +342,11 @@ func (p *DashboardPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 				p.noteForm.Activate()
 			}
 			return p, nil
+
+		case "p":
+			// Pin or unpin the selected item of the focused card
+			p.togglePinned()
+			return p, nil
 		}
 	}
 
@@ -314,6 +364,37 @@ func (p *DashboardPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 	return p, cmd
 }
 
+// Refresh picks up changes made on other pages
+func (p *DashboardPage) Refresh() {
+	p.updateLists()
+	// Views may have been added to or taken off the dashboard
+	p.SetSize(p.width, p.height)
+}
+
+// togglePinned pins or unpins the item selected in the focused card
+func (p *DashboardPage) togglePinned() {
+	var err error
+	switch p.focus {
+	case focusTodos:
+		if item, ok := p.todoList.SelectedItem().(dashboardTodoItem); ok {
+			err = p.TodoList.TogglePinned(item.todo.ID)
+		}
+	case focusEvents:
+		if item, ok := p.eventList.SelectedItem().(dashboardEventItem); ok {
+			err = p.EventList.TogglePinned(item.event.ID)
+		}
+	case focusNotes:
+		if item, ok := p.noteList.SelectedItem().(dashboardNoteItem); ok {
+			err = p.NoteList.TogglePinned(item.note.ID)
+		}
+	}
+	if err != nil {
+		p.message = "⚠️  " + err.Error()
+	}
+	// The pinned card may have come or gone
+	p.Refresh()
+}
+
 // updateLists refreshes all lists after CRUD operations
 func (p *DashboardPage) updateLists() {
 	// Update todo list
@@ -323,15 +404,21 @@ func (p *DashboardPage) updateLists() {
 
 	todoItems := make([]list.Item, 0)
 	for _, todo := range p.TodoList.Todos {
-		todoItems = append(todoItems, dashboardTodoItem{todo: todo})
+		// Subtasks only count towards their parent's progress here
+		if todo.IsSubtask() {
+			continue
+		}
+		done, total := p.TodoList.Progress(todo.ID)
+		todoItems = append(todoItems, dashboardTodoItem{todo: todo, done: done, total: total})
 	}
 	p.todoList.SetItems(todoItems)
 
 	// Update event list
-	sortEvents(p.EventList.Events)
+	events := p.EventList.Timeline(now)
+	sortEvents(events)
 
 	eventItems := make([]list.Item, 0)
-	for _, event := range p.EventList.Events {
+	for _, event := range events {
 		eventItems = append(eventItems, dashboardEventItem{event: event})
 	}
 	p.eventList.SetItems(eventItems)
@@ -352,7 +439,7 @@ func (p *DashboardPage) SetSize(width, height int) {
 
 	// Calculate card dimensions
 	cardWidth := (width - 8) / 3
-	cardHeight := height - 7
+	cardHeight := height - 7 - p.viewCardsHeight() - p.pinnedHeight()
 
 	p.todoList.SetSize(cardWidth-2, cardHeight)
 	p.eventList.SetSize(cardWidth-2, cardHeight)
@@ -395,11 +482,12 @@ func (p *DashboardPage) View() string {
 	}
 
 	// Hero section with key stats
-	heroText := fmt.Sprintf("✨ %s • %d tasks pending • %d overdue • %d due today",
+	heroText := fmt.Sprintf("✨ %s • %d tasks pending • %d overdue • %d due today • %s",
 		now.Format("Monday, January 2, 2006"),
 		pendingTodos,
 		overdueTodos,
-		todayTodos)
+		todayTodos,
+		journalStatus(p.NoteList, now))
 	heroStyle := lipgloss.NewStyle().
 		Bold(true).
 		Foreground(lipgloss.Color("15")).
@@ -410,7 +498,7 @@ func (p *DashboardPage) View() string {
 
 	// Card dimensions
 	cardWidth := (p.width - 8) / 3
-	cardHeight := p.height - 8
+	cardHeight := p.height - 8 - p.viewCardsHeight() - p.pinnedHeight()
 
 	// Create card styles
 	todoCardStyle := lipgloss.NewStyle().
@@ -451,15 +539,112 @@ func (p *DashboardPage) View() string {
 	// Help text
 	helpText := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
-		Render("✨ Tab: switch cards • a: quick add • ↑/↓: browse • Enter: go to page • q: quit")
+		Render("✨ Tab: switch cards • a: quick add • p: pin/unpin • ↑/↓: browse • Enter: go to page • q: quit")
+
+	if p.message != "" {
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("213")).Render(p.message)
+	}
 
 	// Assemble view
-	return lipgloss.JoinVertical(lipgloss.Left,
-		topBar.View(),
-		heroStyle.Render(heroText),
-		cardsRow,
-		helpText,
-	)
+	rows := []string{topBar.View(), heroStyle.Render(heroText)}
+	if pinned := p.pinnedCard(now); pinned != "" {
+		rows = append(rows, pinned)
+	}
+	if viewCards := p.viewCards(now); viewCards != "" {
+		rows = append(rows, viewCards)
+	}
+	rows = append(rows, cardsRow, helpText)
+
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// viewCardsHeight is the room the saved view cards take, 0 without any
+func (p *DashboardPage) viewCardsHeight() int {
+	if len(p.Views.OnDashboard()) == 0 {
+		return 0
+	}
+	return viewCardItems + 4 // title, blank line and borders
+}
+
+// viewCards renders a row with a card for each saved view pinned to the
+// dashboard, showing how many items it matches and the first few of them
+func (p *DashboardPage) viewCards(now time.Time) string {
+	views := p.Views.OnDashboard()
+	if len(views) == 0 {
+		return ""
+	}
+
+	cardWidth := (p.width-2)/len(views) - 2
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213"))
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).MaxWidth(cardWidth)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	cards := make([]string, len(views))
+	for i, view := range views {
+		titles, err := viewTitles(view, p.TodoList, p.NoteList, p.EventList, now)
+
+		lines := []string{titleStyle.Render(fmt.Sprintf("⭐ %s (%d)", view.Name, len(titles))), ""}
+		switch {
+		case err != nil:
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠️  "+err.Error()))
+		case len(titles) == 0:
+			lines = append(lines, dim.Render("Nothing here 🎉"))
+		}
+		for _, title := range titles[:min(len(titles), viewCardItems)] {
+			lines = append(lines, itemStyle.Render(title))
+		}
+
+		cards[i] = lipgloss.NewStyle().
+			Width(cardWidth).
+			Height(viewCardItems + 2).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("63")).
+			Render(strings.Join(lines, "\n"))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, cards...)
+}
+
+// viewTitles runs a saved view against the items of its page and returns
+// a line for each match, in the view's order
+func viewTitles(view *models.SavedView, todoList *models.TodoList, noteList *models.NoteList, eventList *models.EventList, now time.Time) ([]string, error) {
+	q, err := query.Parse(view.Query, now)
+	if err != nil {
+		return nil, err
+	}
+	filter := components.FilterType(view.Filter)
+
+	var titles []string
+	switch view.Page {
+	case models.PageTodos:
+		todos, _, err := findTodos(todoList, q, filter, view.Sort, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, todo := range todos {
+			icon := "○"
+			if todo.Completed {
+				icon = "✓"
+			}
+			titles = append(titles, icon+" "+todo.Title)
+		}
+	case models.PageNotes:
+		notes, _, err := findNotes(noteList, q, filter, view.Sort, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range notes {
+			titles = append(titles, "📝 "+note.Title)
+		}
+	case models.PageCalendar:
+		events, _, err := findEvents(eventList, q, filter, view.Sort, now)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			titles = append(titles, "📅 "+event.StartTime.Format("Jan 2 15:04")+" • "+event.Title)
+		}
+	}
+	return titles, nil
 }
 
 func (p *DashboardPage) IsFormActive() bool {
@@ -0,0 +1,97 @@
+package pages
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/notefile"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noteEditedMsg is sent when the editor opened by editNote exits
+type noteEditedMsg struct {
+	id   int
+	path string // temp file holding the note, empty if it couldn't be written
+	err  error  // the editor didn't run or exited with an error
+}
+
+// editorCommand returns the user's editor and its arguments: $VISUAL, then
+// $EDITOR, then vi
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// editNote writes note to a temp Markdown file and opens it in the user's
+// editor, suspending the app until the editor exits
+func editNote(note *models.Note) tea.Cmd {
+	failed := func(err error) tea.Cmd {
+		return func() tea.Msg { return noteEditedMsg{id: note.ID, err: err} }
+	}
+
+	file, err := os.CreateTemp("", "prodbooster-note-*.md")
+	if err != nil {
+		return failed(err)
+	}
+	_, err = file.WriteString(notefile.Format(note))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return failed(err)
+	}
+
+	args := editorCommand()
+	cmd := exec.Command(args[0], append(slices.Clone(args[1:]), file.Name())...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return noteEditedMsg{id: note.ID, path: file.Name(), err: err}
+	})
+}
+
+// saveEditedNote reads the file of an edit back into its note and returns
+// what happened, for the status line. The file is kept unless the note is
+// saved, so an edit isn't lost when the editor fails or the file can't be
+// read back.
+func saveEditedNote(noteList *models.NoteList, msg noteEditedMsg) string {
+	if msg.err != nil {
+		if msg.path != "" {
+			return fmt.Sprintf("⚠️  Editor failed, note left unchanged: %v. Your text is kept in %s", msg.err, msg.path)
+		}
+		return "⚠️  Editor failed, note left unchanged: " + msg.err.Error()
+	}
+
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		return "⚠️  Couldn't read the edited note: " + err.Error()
+	}
+	title, tags, content, err := notefile.Parse(string(data))
+	if err != nil {
+		return fmt.Sprintf("⚠️  Couldn't read the edited note, %v. Your text is kept in %s", err, msg.path)
+	}
+
+	note := noteList.Get(msg.id)
+	if note == nil {
+		return "⚠️  The note was deleted while you edited it. Your text is kept in " + msg.path
+	}
+
+	// Parse drops trailing newlines, a note that had some is still unchanged
+	if title == note.Title && content == strings.TrimRight(note.Content, "\n") && slices.Equal(tags, note.Tags) {
+		os.Remove(msg.path)
+		return "No changes to “" + note.Title + "”"
+	}
+	if err := noteList.Update(note.ID, title, content, tags); err != nil {
+		return fmt.Sprintf("⚠️  Couldn't save the note, %v. Your text is kept in %s", err, msg.path)
+	}
+	os.Remove(msg.path)
+	return "✏️  Saved “" + title + "”"
+}
@@ -0,0 +1,94 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/ui/components"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// graphDepth is how many links away from the item linkGraph goes
+const graphDepth = 2
+
+// linkGraph draws the neighbourhood of ref as a tree: what it links to,
+// then what references it, each followed graphDepth links deep. Items
+// drawn before are marked ↺ further down instead of being expanded again.
+//
+//	◉ Standup notes
+//	├─▶ 📝 Project X
+//	│   └─▶ 📝 Roadmap
+//	└─◀ 📝 Weekly review
+func linkGraph(links *models.LinkList, ref models.ItemRef) string {
+	title, _ := links.Title(ref)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	outStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("81"))
+	inStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("213"))
+
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213")).Render("◉ " + title)}
+	seen := map[models.ItemRef]bool{ref: true}
+
+	var walk func(ref models.ItemRef, incoming bool, prefix string, depth int)
+	branch := func(others []models.ItemRef, incoming bool, prefix string, depth int, last bool) {
+		arrow, style := "─▶ ", outStyle
+		if incoming {
+			arrow, style = "─◀ ", inStyle
+		}
+		for i, other := range others {
+			isLast := last && i == len(others)-1
+			connector, indent := "├", "│   "
+			if isLast {
+				connector, indent = "└", "    "
+			}
+
+			title, _ := links.Title(other)
+			line := dim.Render(prefix+connector) + style.Render(arrow) + components.ItemIcon(other.Page) + " " + title
+			// Direct neighbours are always expanded, deeper repeats are not
+			if depth > 1 && seen[other] {
+				lines = append(lines, line+dim.Render(" ↺"))
+				continue
+			}
+			lines = append(lines, line)
+			seen[other] = true
+			if depth < graphDepth {
+				walk(other, incoming, prefix+indent, depth+1)
+			}
+		}
+	}
+	walk = func(ref models.ItemRef, incoming bool, prefix string, depth int) {
+		others := neighbours(links, ref, incoming)
+		branch(others, incoming, prefix, depth, true)
+	}
+
+	outgoing := neighbours(links, ref, false)
+	incoming := neighbours(links, ref, true)
+	for _, other := range append(append([]models.ItemRef{}, outgoing...), incoming...) {
+		seen[other] = true
+	}
+	branch(outgoing, false, "", 1, len(incoming) == 0)
+	branch(incoming, true, "", 1, true)
+
+	if len(outgoing)+len(incoming) == 0 {
+		lines = append(lines, "", dim.Render("Not connected to anything yet. Write [[Another Note]] or press l to link"))
+	} else {
+		lines = append(lines, "", dim.Render(fmt.Sprintf("▶ links to %d • ◀ referenced by %d", len(outgoing), len(incoming))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// neighbours returns the items ref links to, or the ones linking to it
+func neighbours(links *models.LinkList, ref models.ItemRef, incoming bool) []models.ItemRef {
+	others := []models.ItemRef{}
+	if incoming {
+		for _, link := range links.Incoming(ref) {
+			others = append(others, link.From)
+		}
+	} else {
+		for _, link := range links.Outgoing(ref) {
+			others = append(others, link.To)
+		}
+	}
+	return others
+}
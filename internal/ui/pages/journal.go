@@ -0,0 +1,62 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/templates"
+)
+
+// JournalMsg asks the notes page to show today's journal note, e.g. from
+// the T key on any page
+type JournalMsg struct{}
+
+// todaysJournal returns today's journal note, starting it from the journal
+// template on the first open of the day. A note that started from the
+// built-in template because the journal template is broken comes back
+// together with the template's error.
+func todaysJournal(noteList *models.NoteList, now time.Time) (*models.Note, error) {
+	if note := noteList.Journal(now); note != nil {
+		return note, nil
+	}
+
+	tmpl, tmplErr := templates.Journal()
+	title, tags, content := tmpl.Expand(templates.Vars{Now: now})
+	note, err := noteList.CreateJournal(now, title, content, tags)
+	if err != nil {
+		return nil, err
+	}
+	return note, tmplErr
+}
+
+// journalStatus sums up today's journal for the dashboard
+func journalStatus(noteList *models.NoteList, now time.Time) string {
+	note := noteList.Journal(now)
+	if note == nil {
+		return "📓 No journal yet, T to start"
+	}
+	if words := journalWords(note.Content); words > 0 {
+		return fmt.Sprintf("📓 Journal: %d words", words)
+	}
+	return "📓 Journal still empty"
+}
+
+// journalWords counts the words written in a journal note, leaving out the
+// headings and empty bullets its template starts with
+func journalWords(content string) int {
+	words := 0
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, field := range strings.Fields(line) {
+			if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+				words++
+			}
+		}
+	}
+	return words
+}
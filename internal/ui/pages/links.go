@@ -0,0 +1,42 @@
+package pages
+
+import (
+	"prodBooster/internal/models"
+	"prodBooster/internal/ui/components"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// linkLines renders the items linked to and from ref, empty when there are
+// none
+func linkLines(links *models.LinkList, ref models.ItemRef) string {
+	heading := lipgloss.NewStyle().Bold(true)
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("147"))
+
+	var lines []string
+	section := func(title string, others []models.ItemRef) {
+		if len(others) == 0 {
+			return
+		}
+		lines = append(lines, "", heading.Render(title))
+		for _, other := range others {
+			title, _ := links.Title(other)
+			lines = append(lines, itemStyle.Render("  "+components.ItemIcon(other.Page)+" "+title))
+		}
+	}
+
+	var outgoing, incoming []models.ItemRef
+	for _, link := range links.Outgoing(ref) {
+		outgoing = append(outgoing, link.To)
+	}
+	for _, link := range links.Incoming(ref) {
+		incoming = append(incoming, link.From)
+	}
+	section("🔗 Linked items", outgoing)
+	section("↩️  Referenced by", incoming)
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
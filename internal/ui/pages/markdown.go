@@ -0,0 +1,70 @@
+package pages
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// markdownRenderer renders note content as styled Markdown. It keeps the
+// last result around, as the notes page redraws far more often than the
+// shown note changes.
+type markdownRenderer struct {
+	renderer *glamour.TermRenderer
+	style    string // glamour style name, dark when empty
+	width    int
+	content  string
+	out      string
+	cached   bool
+}
+
+// Render returns content rendered as Markdown, wrapped to width
+func (m *markdownRenderer) Render(content string, width int) (string, error) {
+	if m.renderer == nil || width != m.width {
+		style := m.style
+		if style == "" {
+			style = styles.DarkStyle
+		}
+		renderer, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(style),
+			glamour.WithWordWrap(width),
+			// Notes are often jotted line by line, keep their line breaks
+			glamour.WithPreservedNewLines(),
+		)
+		if err != nil {
+			return "", err
+		}
+		m.renderer = renderer
+		m.width = width
+		m.cached = false
+	}
+
+	if m.cached && content == m.content {
+		return m.out, nil
+	}
+
+	out, err := m.renderer.Render(content)
+	if err != nil {
+		return "", err
+	}
+	m.content = content
+	m.out = strings.Trim(out, "\n")
+	m.cached = true
+	return m.out, nil
+}
+
+// SetStyle switches to the named glamour style. "auto" picks dark or light
+// by the terminal's background here and now: asking the terminal later,
+// while Bubble Tea reads the keyboard, would swallow its answer as keys.
+func (m *markdownRenderer) SetStyle(style string) {
+	if style == styles.AutoStyle {
+		style = styles.LightStyle
+		if lipgloss.HasDarkBackground() {
+			style = styles.DarkStyle
+		}
+	}
+	m.style = style
+	m.renderer = nil
+}
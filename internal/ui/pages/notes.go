@@ -3,32 +3,66 @@ package pages
 import (
 	"fmt"
 	"io"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"prodBooster/internal/models"
+	"prodBooster/internal/query"
+	"prodBooster/internal/templates"
 	"prodBooster/internal/ui/components"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Quick filters of the notes search bar
+const (
+	filterNotesToday components.FilterType = iota + 1
+	filterNotesThisWeek
+	filterNotesOld
+	filterNotesLong
+	filterNotesJournal
+)
+
+var noteFilters = []components.FilterOption{
+	{Label: "✨ Created Today", Color: "51"},
+	{Label: "🗓️  This Week", Color: "45"},
+	{Label: "🕸️  Older than 30 days", Color: "240"},
+	{Label: "📜 Long Notes", Color: "228"},
+	{Label: "📓 Journal", Color: "180"},
+}
+
+// longNoteWords is how many words make a note long
+const longNoteWords = 300
+
 // noteItem implements list.Item interface
 type noteItem struct {
 	note *models.Note
 }
 
 func (n noteItem) Title() string {
-	return "📝 " + n.note.Title
+	icon := "📝 "
+	if n.note.IsJournal() {
+		icon = "📓 "
+	}
+	if n.note.Pinned {
+		return icon + n.note.Title + pinMark
+	}
+	return icon + n.note.Title
 }
 
 func (n noteItem) Description() string {
-	preview := n.note.Content
+	// Cut by runes, not bytes, so multi-byte characters stay whole
+	preview := []rune(n.note.Content)
 	if len(preview) > 100 {
-		preview = preview[:100] + "..."
+		return string(preview[:100]) + "..."
 	}
-	return preview
+	return n.note.Content
 }
 
 func (n noteItem) FilterValue() string {
@@ -70,13 +104,38 @@ func (d noteDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		style = style.Background(lipgloss.Color("238"))
 	}
 
-	fmt.Fprint(w, style.Render(note.Title()))
+	fmt.Fprint(w, style.Render(note.Title())+renderTags(note.note.Tags, index == m.Index()))
+}
+
+// NewNoteMsg asks the notes page to start a new note for an event, e.g.
+// from the calendar. It offers the templates first.
+type NewNoteMsg struct {
+	Event *models.Event
 }
 
 type NotesPage struct {
 	NoteList     *models.NoteList
+	links        *models.LinkList
 	form         *components.NoteForm
+	templates    *components.TemplatePicker
+	notebooks    *models.NotebookList
+	notebookTree *components.NotebookPanel
+	history      *components.NoteHistory
+	notebook     int           // notebook shown, models.AllNotes or models.Unfiled for the groups
+	searchAll    bool          // search every notebook instead of the one shown
+	forEvent     *models.Event // event the note in the form is for, linked to it once saved
 	searchBar    *components.SearchBar
+	hits         map[int]models.SearchHit // full-text matches, nil when not searching
+	views        *components.ViewsPanel
+	order        int    // index into noteOrders
+	wikiCursor   int    // selected [[link]] of the shown note
+	trail        []int  // notes [[links]] were followed from, for going back
+	graph        bool   // show the link graph instead of the note
+	message      string // outcome of the last action, e.g. an $EDITOR edit, cleared on the next key
+	raw          bool   // show the note's Markdown source instead of rendering it
+	markdown     markdownRenderer
+	viewer       viewport.Model // scrolls the shown note
+	shown        int            // note in the viewer, to start new ones at the top
 	list         list.Model
 	currentPage  models.PageType
 	width        int
@@ -84,7 +143,7 @@ type NotesPage struct {
 	sidebarWidth int
 }
 
-func NewNotesPage(noteList_ *models.NoteList) *NotesPage {
+func NewNotesPage(noteList_ *models.NoteList, viewList_ *models.ViewList, linkList_ *models.LinkList, notebookList_ *models.NotebookList) *NotesPage {
 	// Sort notes initially - newest first
 	sortNotes(noteList_.Notes)
 
@@ -104,8 +163,16 @@ func NewNotesPage(noteList_ *models.NoteList) *NotesPage {
 
 	return &NotesPage{
 		NoteList:     noteList_,
+		links:        linkList_,
 		form:         components.NewNoteForm(noteList_),
-		searchBar:    components.NewSearchBar(),
+		templates:    components.NewTemplatePicker(),
+		notebooks:    notebookList_,
+		notebookTree: components.NewNotebookPanel(notebookList_),
+		history:      components.NewNoteHistory(noteList_),
+		notebook:     models.AllNotes,
+		searchBar:    components.NewSearchBar(query.Notes, noteFilters),
+		views:        components.NewViewsPanel(viewList_, models.PageNotes, noteFilters),
+		viewer:       viewport.New(0, 0),
 		list:         l,
 		currentPage:  models.PageTypeNotes(),
 		width:        80,
@@ -119,6 +186,37 @@ func (p *NotesPage) Init() tea.Cmd {
 }
 
 func (p *NotesPage) Update(msg tea.Msg) (Page, tea.Cmd) {
+	// Back from editing a note in $EDITOR
+	if msg, ok := msg.(noteEditedMsg); ok {
+		p.message = saveEditedNote(p.NoteList, msg)
+		p.updateListItems()
+		return p, nil
+	}
+
+	// A note for an event, asked for by another page
+	if msg, ok := msg.(NewNoteMsg); ok {
+		p.form.Deactivate()
+		p.history.Close()
+		p.templates.Open(msg.Event)
+		return p, nil
+	}
+
+	// Today's journal, asked for from any page
+	if _, ok := msg.(JournalMsg); ok {
+		p.form.Deactivate()
+		p.templates.Close()
+		p.history.Close()
+		note, err := todaysJournal(p.NoteList, time.Now())
+		if err != nil {
+			p.message = "⚠️  " + err.Error()
+		}
+		if note == nil {
+			return p, nil
+		}
+		p.Focus(note.ID)
+		return p, nil
+	}
+
 	// If form is active, route all input to form
 	if p.form.IsActive() {
 		updatedForm, cmd := p.form.Update(msg)
@@ -126,6 +224,7 @@ func (p *NotesPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 
 		// Reload list after form submission
 		if !p.form.IsActive() {
+			p.linkToEvent()
 			p.updateListItems()
 			p.list.Select(0) // Reset to first item
 		}
@@ -133,6 +232,37 @@ func (p *NotesPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 		return p, cmd
 	}
 
+	// Picking what a new note starts from
+	if p.templates.IsActive() {
+		updatedPicker, cmd := p.templates.Update(msg)
+		p.templates = updatedPicker
+		if tmpl, event, ok := p.templates.Chosen(); ok {
+			source := tmpl.Name
+			if event != nil {
+				source += " • 📅 " + event.Title
+			}
+			p.forEvent = event
+			p.form.SetNotebook(max(p.notebook, models.Unfiled))
+			p.form.ActivateFrom(tmpl, templates.Vars{Now: time.Now(), Event: event}, source)
+		}
+		return p, cmd
+	}
+
+	// Browsing the versions of the selected note
+	if p.history.IsActive() {
+		updatedHistory, cmd := p.history.Update(msg)
+		p.history = updatedHistory
+		if revision, ok := p.history.Restored(); ok {
+			when := "the original version"
+			if !revision.CreatedAt.IsZero() {
+				when = "the version of " + revision.CreatedAt.Format("Jan 2 15:04:05")
+			}
+			p.message = "↩️  Restored " + when + " • u: undo"
+			p.updateListItems()
+		}
+		return p, cmd
+	}
+
 	// If search is active, route to search bar
 	if p.searchBar.IsActive() {
 		updatedSearch, cmd := p.searchBar.Update(msg)
@@ -146,13 +276,54 @@ func (p *NotesPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 		return p, cmd
 	}
 
+	// If the notebook tree is active, route to it
+	if p.notebookTree.IsActive() {
+		updatedTree, cmd := p.notebookTree.Update(msg)
+		p.notebookTree = updatedTree
+		if id, ok := p.notebookTree.Chosen(); ok {
+			p.notebook = id
+			p.updateListItems()
+			p.list.Select(0)
+		}
+		if id, ok := p.notebookTree.MoveTarget(); ok {
+			p.moveSelected(id)
+		}
+		return p, cmd
+	}
+
+	// If the saved views sidebar is active, route to it
+	if p.views.IsActive() {
+		updatedViews, cmd := p.views.Update(msg)
+		p.views = updatedViews
+		if view, ok := p.views.Chosen(); ok {
+			p.order = applyView(p.searchBar, view, noteOrders)
+			p.updateListItems()
+			p.list.Select(0)
+		}
+		return p, cmd
+	}
+
 	// Normal page navigation
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		p.message = ""
+		if view, ok := p.views.Shortcut(msg.String()); ok {
+			// Apply a saved view without opening the sidebar
+			p.order = applyView(p.searchBar, view, noteOrders)
+			p.updateListItems()
+			p.list.Select(0)
+			return p, nil
+		}
 		switch msg.String() {
 		case "n":
-			// Create new note
-			p.form.Activate()
+			// Create new note, from a template if one is picked
+			p.templates.Open(nil)
+
+		case "E":
+			// Edit selected note in $VISUAL/$EDITOR
+			if item, ok := p.list.SelectedItem().(noteItem); ok {
+				return p, editNote(item.note)
+			}
 
 		case "e":
 			// Edit selected note
@@ -170,16 +341,124 @@ func (p *NotesPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 				p.list.Select(0) // Reset to first item
 			}
 
+		case "p":
+			// Pin the selected note to the top, or unpin it
+			p.togglePinned()
+			return p, nil
+
+		case "H":
+			// Browse the saved versions of the selected note
+			if item, ok := p.list.SelectedItem().(noteItem); ok {
+				p.history.Open(item.note)
+			}
+			return p, nil
+
 		case "/":
 			// Activate search
+			p.searchBar.SetKnownTags(p.NoteList.AllTags())
 			p.searchBar.Activate()
 			return p, nil
+
+		case "s":
+			// Cycle the sort order
+			p.order = (p.order + 1) % len(noteOrders)
+			p.updateListItems()
+			p.list.Select(0)
+			return p, nil
+
+		case "v":
+			// Pick or save a view
+			p.views.Activate()
+			return p, nil
+
+		case "B":
+			// Pick the notebook to show
+			p.notebookTree.Activate()
+			return p, nil
+
+		case "M":
+			// Move the selected note to another notebook
+			if _, ok := p.list.SelectedItem().(noteItem); ok {
+				p.notebookTree.ActivateMove()
+			}
+			return p, nil
+
+		case "G":
+			// Search every notebook or just the one shown
+			p.searchAll = !p.searchAll
+			p.updateListItems()
+			p.list.Select(0)
+			return p, nil
+
+		case "]", "[":
+			// Pick the next/previous [[link]] of the note
+			if item, ok := p.list.SelectedItem().(noteItem); ok {
+				if n := len(models.ParseWikiLinks(item.note.Content)); n > 0 {
+					if msg.String() == "]" {
+						p.wikiCursor = (p.wikiCursor + 1) % n
+					} else {
+						p.wikiCursor = (p.wikiCursor + n - 1) % n
+					}
+				}
+			}
+			return p, nil
+
+		case "f":
+			// Follow the picked [[link]]
+			p.followWiki()
+			return p, nil
+
+		case "b":
+			// Back to the note the last link was followed from
+			if n := len(p.trail); n > 0 {
+				id := p.trail[n-1]
+				p.trail = p.trail[:n-1]
+				p.Focus(id)
+			}
+			return p, nil
+
+		case "g":
+			// Toggle the link graph
+			p.graph = !p.graph
+			p.viewer.GotoTop()
+			return p, nil
+
+		case "<", ">":
+			// Previous/next day of the journal
+			p.stepJournal(msg.String())
+			return p, nil
+
+		case "m":
+			// Toggle between rendered Markdown and the raw text
+			p.raw = !p.raw
+			return p, nil
+
+		case "pgdown", "J":
+			// Scroll the shown note
+			if msg.String() == "J" {
+				p.viewer.LineDown(1)
+			} else {
+				p.viewer.HalfViewDown()
+			}
+			return p, nil
+
+		case "pgup", "K":
+			if msg.String() == "K" {
+				p.viewer.LineUp(1)
+			} else {
+				p.viewer.HalfViewUp()
+			}
+			return p, nil
 		}
 	}
 
 	// Update the list for navigation and sync selection
 	var cmd tea.Cmd
+	previous := p.list.Index()
 	p.list, cmd = p.list.Update(msg)
+	if p.list.Index() != previous {
+		p.wikiCursor = 0
+	}
 
 	// Sync NoteList.Selected with list's selected index
 	p.NoteList.Selected = p.list.Index()
@@ -187,19 +466,151 @@ func (p *NotesPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 	return p, cmd
 }
 
+// Refresh picks up changes made on other pages
+func (p *NotesPage) Refresh() {
+	p.updateListItems()
+}
+
+// Focus selects the note with the given id, clearing the search and
+// leaving the notebook shown if the note isn't in it
+func (p *NotesPage) Focus(id int) {
+	p.searchBar.Deactivate()
+	p.wikiCursor = 0
+	if note := p.NoteList.Get(id); note != nil && !p.notebooks.Contains(p.notebook, note) {
+		p.notebook = models.AllNotes
+	}
+	p.updateListItems()
+	for i, item := range p.list.Items() {
+		if item.(noteItem).note.ID == id {
+			p.list.Select(i)
+			p.NoteList.Selected = i
+			return
+		}
+	}
+}
+
+// linkToEvent links the note just written for an event to the event
+func (p *NotesPage) linkToEvent() {
+	event := p.forEvent
+	p.forEvent = nil
+	note, ok := p.form.Created()
+	if !ok || event == nil {
+		return
+	}
+	err := p.links.Add(models.ItemRef{Page: models.PageNotes, ID: note.ID}, models.ItemRef{Page: models.PageCalendar, ID: event.ID})
+	if err != nil {
+		p.message = "⚠️  " + err.Error()
+	}
+}
+
+// moveSelected moves the selected note to a notebook
+func (p *NotesPage) moveSelected(notebookID int) {
+	item, ok := p.list.SelectedItem().(noteItem)
+	if !ok {
+		return
+	}
+	if err := p.NoteList.Move(item.note.ID, notebookID); err != nil {
+		p.message = "⚠️  " + err.Error()
+		return
+	}
+
+	p.message = "📦 Moved to Unfiled"
+	if notebookID != models.Unfiled {
+		p.message = "📦 Moved to " + p.notebooks.Path(notebookID)
+	}
+	p.updateListItems()
+}
+
+// togglePinned pins the selected note to the top of the list, or unpins it
+func (p *NotesPage) togglePinned() {
+	item, ok := p.list.SelectedItem().(noteItem)
+	if !ok {
+		return
+	}
+	if err := p.NoteList.TogglePinned(item.note.ID); err != nil {
+		p.message = "⚠️  " + err.Error()
+		return
+	}
+
+	p.message = "📌 Pinned to the top"
+	if !item.note.Pinned {
+		p.message = "Unpinned"
+	}
+	p.updateListItems()
+	for i, listed := range p.list.Items() {
+		if listed.(noteItem).note.ID == item.note.ID {
+			p.list.Select(i)
+			p.NoteList.Selected = i
+		}
+	}
+}
+
+// stepJournal shows the journal note of the day before ("<") or after
+// (">") the shown one, skipping days without one
+func (p *NotesPage) stepJournal(key string) {
+	item, ok := p.list.SelectedItem().(noteItem)
+	if !ok || !item.note.IsJournal() {
+		p.message = "Not a journal note • T opens today's"
+		return
+	}
+
+	step, word := -1, "earlier"
+	if key == ">" {
+		step, word = 1, "later"
+	}
+	if target := p.NoteList.AdjacentJournal(item.note, step); target != nil {
+		p.Focus(target.ID)
+		return
+	}
+	p.message = "No " + word + " journal entry"
+}
+
+// followWiki jumps to the note the picked [[link]] of the shown note
+// points at
+func (p *NotesPage) followWiki() {
+	item, ok := p.list.SelectedItem().(noteItem)
+	if !ok {
+		return
+	}
+	refs := models.ParseWikiLinks(item.note.Content)
+	if p.wikiCursor >= len(refs) {
+		return
+	}
+
+	target, err := p.NoteList.WikiTarget(item.note, refs[p.wikiCursor].Title)
+	if err != nil {
+		p.message = "⚠️  " + err.Error()
+		return
+	}
+	p.trail = append(p.trail, item.note.ID)
+	p.Focus(target.ID)
+}
+
 // updateListItems refreshes the list with current notes and filters
 func (p *NotesPage) updateListItems() {
-	filteredNotes := []*models.Note{}
-	for _, note := range p.NoteList.Notes {
-		// Apply text search
-		if !p.searchBar.Match(note.Title + " " + note.Content) {
-			continue
-		}
-		filteredNotes = append(filteredNotes, note)
+	if p.notebook > 0 && p.notebooks.Get(p.notebook) == nil {
+		// The notebook shown is gone
+		p.notebook = models.AllNotes
 	}
 
-	// Sort notes by creation date - newest first
-	sortNotes(filteredNotes)
+	filteredNotes, hits, err := findNotes(p.NoteList, p.searchBar.Query(), p.searchBar.GetFilter(), noteOrders[p.order], time.Now())
+	p.hits = hits
+	p.searchBar.SetSearchError(err)
+	p.list.Title = listTitle(p.notebookTitle(), noteOrders, p.order)
+	p.views.SetCurrent(p.searchBar.GetQuery(), int(p.searchBar.GetFilter()), noteOrders[p.order])
+	p.notebookTree.SetCurrent(p.notebook)
+
+	// Only the notebook shown, unless searching them all
+	searching := p.searchBar.GetQuery() != "" || p.searchBar.GetFilter() != components.FilterNone
+	if !(p.searchAll && searching) {
+		inNotebook := filteredNotes[:0]
+		for _, note := range filteredNotes {
+			if p.notebooks.Contains(p.notebook, note) {
+				inNotebook = append(inNotebook, note)
+			}
+		}
+		filteredNotes = inNotebook
+	}
 
 	// Convert to list items
 	items := make([]list.Item, len(filteredNotes))
@@ -210,6 +621,88 @@ func (p *NotesPage) updateListItems() {
 	p.list.SetItems(items)
 }
 
+// notebookTitle names what the list shows: the notebook and, when it
+// matters, whether searches look in every notebook
+func (p *NotesPage) notebookTitle() string {
+	title := "📒 My Notes"
+	switch {
+	case p.notebook == models.Unfiled:
+		title = "📥 Unfiled"
+	case p.notebook != models.AllNotes:
+		title = "📁 " + p.notebooks.Path(p.notebook)
+	}
+	if p.searchAll && p.notebook != models.AllNotes {
+		title += " 🌐"
+	}
+	return title
+}
+
+// findNotes returns the notes matching a search, sorted by order, and the
+// full-text hits (nil when the query has no text). When the full-text
+// search fails nothing matches and the error comes back.
+func findNotes(noteList *models.NoteList, q *query.Query, filter components.FilterType, order string, now time.Time) ([]*models.Note, map[int]models.SearchHit, error) {
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	hits, err := searchHits(q.Text(), noteList.Search)
+
+	filteredNotes := []*models.Note{}
+	for _, note := range noteList.Notes {
+		// Apply text search
+		if _, ok := hits[note.ID]; hits != nil && !ok {
+			continue
+		}
+		// Apply fields, tags and the rest of the query
+		if !q.MatchNote(note) {
+			continue
+		}
+
+		// Apply filter
+		switch filter {
+		case filterNotesToday:
+			if note.CreatedAt.Before(todayStart) {
+				continue
+			}
+		case filterNotesThisWeek:
+			// Today and the six days before it
+			if note.CreatedAt.Before(todayStart.AddDate(0, 0, -6)) {
+				continue
+			}
+		case filterNotesOld:
+			if !note.CreatedAt.Before(now.AddDate(0, 0, -30)) {
+				continue
+			}
+		case filterNotesLong:
+			if len(strings.Fields(note.Content)) < longNoteWords {
+				continue
+			}
+		case filterNotesJournal:
+			if !note.IsJournal() {
+				continue
+			}
+		}
+
+		filteredNotes = append(filteredNotes, note)
+	}
+
+	// Sort notes by creation date - newest first, best match first when searching
+	sortNotes(filteredNotes)
+	switch order {
+	case "oldest":
+		slices.Reverse(filteredNotes)
+	case "title":
+		sort.SliceStable(filteredNotes, func(i, j int) bool {
+			return strings.ToLower(filteredNotes[i].Title) < strings.ToLower(filteredNotes[j].Title)
+		})
+	default:
+		if hits != nil {
+			sortByRank(filteredNotes, hits, func(note *models.Note) int { return note.ID })
+		}
+	}
+	pinnedFirst(filteredNotes, func(note *models.Note) bool { return note.Pinned })
+
+	return filteredNotes, hits, err
+}
+
 // sortNotes sorts by creation date - newest first
 func sortNotes(notes []*models.Note) {
 	for i := 0; i < len(notes); i++ {
@@ -234,6 +727,8 @@ func (p *NotesPage) SetSize(width, height int) {
 
 	p.list.SetSize(p.sidebarWidth-4, height-6) // Account for borders and padding
 	p.form.SetSize(width, height)
+	p.templates.SetWidth(min(width, 80))
+	p.history.SetSize(width, height)
 }
 
 func (p *NotesPage) View() string {
@@ -242,6 +737,14 @@ func (p *NotesPage) View() string {
 		return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center, p.form.View())
 	}
 
+	if p.templates.IsActive() {
+		return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center, p.templates.View())
+	}
+
+	if p.history.IsActive() {
+		return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center, p.history.View())
+	}
+
 	// If search is active, show search overlay
 	if p.searchBar.IsActive() {
 		return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Top, p.searchBar.View())
@@ -260,7 +763,7 @@ func (p *NotesPage) View() string {
 	sidebar := sidebarStyle.Render(p.list.View())
 
 	// Content pane with selected note detail
-	contentWidth := p.width - p.sidebarWidth - 4
+	contentWidth := p.width - p.sidebarWidth - 4 - p.panelsWidth()
 	contentStyle := lipgloss.NewStyle().
 		Width(contentWidth).
 		Height(p.height-6).
@@ -273,8 +776,13 @@ func (p *NotesPage) View() string {
 		content = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
 			Render("✨ No notes yet!\n\nPress 'n' to jot down your first thought 💭")
-	} else if p.NoteList.Selected < len(p.NoteList.Notes) {
-		note := p.NoteList.Notes[p.NoteList.Selected]
+	} else if item, ok := p.list.SelectedItem().(noteItem); ok {
+		note := item.note
+
+		if note.ID != p.shown {
+			p.shown = note.ID
+			p.viewer.GotoTop()
+		}
 
 		// Title
 		titleStyle := lipgloss.NewStyle().
@@ -283,6 +791,9 @@ func (p *NotesPage) View() string {
 
 		// Created date
 		dateStr := "📅 Created " + note.CreatedAt.Format("Mon, Jan 2, 2006 at 3:04 PM")
+		if day, err := time.ParseInLocation(models.JournalDateFormat, note.JournalDate, time.Local); err == nil {
+			dateStr = "📓 Journal of " + day.Format("Monday, Jan 2, 2006") + " • </>: previous/next day"
+		}
 
 		content = lipgloss.JoinVertical(lipgloss.Left,
 			titleStyle.Render(note.Title),
@@ -290,12 +801,34 @@ func (p *NotesPage) View() string {
 			lipgloss.NewStyle().
 				Foreground(lipgloss.Color("240")).
 				Render(dateStr),
+			tagsLine(note.Tags),
+			p.notebookLine(note),
+			snippetLine(p.hits, note.ID),
+			p.pickedWikiLine(note),
 			"",
-			lipgloss.NewStyle().
-				Foreground(lipgloss.Color("252")).
-				Width(contentWidth-4).
-				Render(note.Content),
+			p.noteBody(note, contentWidth-4),
+			linkLines(p.links, models.ItemRef{Page: models.PageNotes, ID: note.ID}),
 		)
+		if p.graph {
+			content = lipgloss.JoinVertical(lipgloss.Left,
+				titleStyle.Render("🕸️  Link graph"),
+				"",
+				linkGraph(p.links, models.ItemRef{Page: models.PageNotes, ID: note.ID}),
+			)
+		}
+
+		// Scroll the note when it's taller than the pane, leaving a line
+		// to say so
+		p.viewer.Width = contentWidth - 4
+		p.viewer.Height = p.height - 9
+		p.viewer.SetContent(content)
+		content = p.viewer.View()
+		if p.viewer.TotalLineCount() > p.viewer.VisibleLineCount() {
+			content = lipgloss.JoinVertical(lipgloss.Left, content,
+				lipgloss.NewStyle().
+					Foreground(lipgloss.Color("240")).
+					Render(fmt.Sprintf("↕ %d%% • pgup/pgdown: scroll • J/K: line", int(p.viewer.ScrollPercent()*100))))
+		}
 	} else {
 		content = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
@@ -305,10 +838,14 @@ func (p *NotesPage) View() string {
 	// Add help text
 	helpText := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
-		Render("✨ n: new note • e: edit • d: delete • /: search • ↑/↓: browse • q: quit")
+		Render("✨ n: new note • e: edit • E: edit in $EDITOR • d: delete • p: pin • H: history • m: raw/markdown • /: search • s: sort • v: views • B: notebooks • M: move • G: search all notebooks • l: link • o: links • [/]: pick [[link]] • f: follow • b: back • g: graph • ↑/↓: browse • q: quit")
+
+	if p.message != "" {
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("213")).Render(p.message)
+	}
 
-	// Combine sidebar and content
-	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, contentStyle.Render(content))
+	// Combine saved views, sidebar and content
+	mainContent := p.withPanels(lipgloss.JoinHorizontal(lipgloss.Top, sidebar, contentStyle.Render(content)))
 
 	// Build the view
 	return lipgloss.JoinVertical(lipgloss.Left,
@@ -318,6 +855,93 @@ func (p *NotesPage) View() string {
 	)
 }
 
+// notebookLine says which notebook the note is in, empty when unfiled
+func (p *NotesPage) notebookLine(note *models.Note) string {
+	path := p.notebooks.Path(note.NotebookID)
+	if path == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("📁 " + path)
+}
+
+// withPanels puts the notebook tree and the saved views, when shown, in a
+// column left of the page's main content
+func (p *NotesPage) withPanels(mainContent string) string {
+	panels := []string{}
+	total := p.height - 6 // each panel's borders take 2 more lines, like the list's
+	switch {
+	case p.notebookTree.Visible() && p.views.Visible():
+		notebooksHeight := total / 2
+		p.notebookTree.SetHeight(notebooksHeight)
+		p.views.SetHeight(total - notebooksHeight - 2)
+		panels = append(panels, p.notebookTree.View(), p.views.View())
+	case p.notebookTree.Visible():
+		p.notebookTree.SetHeight(total)
+		panels = append(panels, p.notebookTree.View())
+	case p.views.Visible():
+		p.views.SetHeight(total)
+		panels = append(panels, p.views.View())
+	default:
+		return mainContent
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.JoinVertical(lipgloss.Left, panels...), mainContent)
+}
+
+// panelsWidth is how much room the column of withPanels takes
+func (p *NotesPage) panelsWidth() int {
+	if !p.notebookTree.Visible() && !p.views.Visible() {
+		return 0
+	}
+	return components.ViewsPanelWidth
+}
+
+// noteBody renders the text of a note as Markdown, or raw with its
+// [[links]] highlighted when raw mode is on
+func (p *NotesPage) noteBody(note *models.Note, width int) string {
+	var failed string
+	if !p.raw {
+		body, err := p.markdown.Render(note.Content, width)
+		if err == nil {
+			return body
+		}
+		// Show the text as it is rather than nothing
+		failed = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render("⚠️  Can't render Markdown: "+err.Error()) + "\n\n"
+	}
+
+	exists := func(title string) bool { return p.NoteList.FindByTitle(title) != nil }
+	body := renderWiki(note.Content, p.wikiCursor, exists, lipgloss.NewStyle().Foreground(lipgloss.Color("252")))
+	return lipgloss.NewStyle().Width(width).Render(failed + body)
+}
+
+// SetMarkdownStyle picks the glamour style notes are rendered in, e.g.
+// "dark", "light" or "auto" for the one that suits the terminal. Call it
+// before the UI starts, see markdownRenderer.SetStyle.
+func (p *NotesPage) SetMarkdownStyle(style string) {
+	p.markdown.SetStyle(style)
+}
+
+// pickedWikiLine shows which [[link]] f follows, as rendered Markdown
+// doesn't highlight them. Empty in raw mode or when there are none.
+func (p *NotesPage) pickedWikiLine(note *models.Note) string {
+	refs := models.ParseWikiLinks(note.Content)
+	if p.raw || p.wikiCursor >= len(refs) {
+		return ""
+	}
+	ref := refs[p.wikiCursor]
+	exists := func(title string) bool { return p.NoteList.FindByTitle(title) != nil }
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("↪ f: follow ") +
+		renderWiki(note.Content[ref.Start:ref.End], 0, exists, lipgloss.NewStyle())
+}
+
+// Selected returns the note under the cursor
+func (p *NotesPage) Selected() (models.ItemRef, bool) {
+	if item, ok := p.list.SelectedItem().(noteItem); ok {
+		return models.ItemRef{Page: models.PageNotes, ID: item.note.ID}, true
+	}
+	return models.ItemRef{}, false
+}
+
 func (p *NotesPage) IsFormActive() bool {
-	return p.form.IsActive() || p.searchBar.IsActive()
+	return p.form.IsActive() || p.templates.IsActive() || p.searchBar.IsActive() || p.views.IsActive() || p.notebookTree.IsActive() || p.history.IsActive()
 }
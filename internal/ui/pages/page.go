@@ -2,6 +2,8 @@
 package pages
 
 import (
+	"prodBooster/internal/models"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -28,3 +30,22 @@ type Page interface {
 	// IsFormActive returns true if a form is currently active
 	IsFormActive() bool
 }
+
+// Refresher is implemented by pages that show data which other pages can
+// change (e.g. restoring from the trash). Refresh is called when the page
+// is switched to.
+type Refresher interface {
+	Refresh()
+}
+
+// Focuser is implemented by pages that can jump to one of their items, e.g.
+// from the global search. Focus clears any search hiding the item.
+type Focuser interface {
+	Focus(id int)
+}
+
+// Linker is implemented by pages whose items can be linked to other items.
+// Selected returns the item under the cursor.
+type Linker interface {
+	Selected() (models.ItemRef, bool)
+}
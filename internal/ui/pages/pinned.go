@@ -0,0 +1,110 @@
+package pages
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"prodBooster/internal/models"
+)
+
+// pinnedCardItems is how many items each column of the dashboard's pinned
+// card lists
+const pinnedCardItems = 4
+
+// pinMark is what pinned items get after their title
+const pinMark = " 📌"
+
+// pinnedFirst moves the pinned items to the top, keeping the order among
+// pinned and among unpinned items
+func pinnedFirst[T any](items []T, pinned func(T) bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return pinned(items[i]) && !pinned(items[j])
+	})
+}
+
+// pinnedEventsFirst moves pinned events to the top. Of a repeating event
+// only the occurrence EventList.Pinned picks goes up, not every standup of
+// the next three months.
+func pinnedEventsFirst(events []*models.Event, eventList *models.EventList, now time.Time) {
+	lifted := map[int]time.Time{}
+	for _, event := range eventList.Pinned(now) {
+		lifted[event.ID] = event.StartTime
+	}
+	pinnedFirst(events, func(event *models.Event) bool {
+		start, ok := lifted[event.ID]
+		return ok && event.StartTime.Equal(start)
+	})
+}
+
+// pinnedHeight is the room the pinned card takes, 0 when nothing is pinned
+func (p *DashboardPage) pinnedHeight() int {
+	if len(p.TodoList.Pinned()) == 0 && len(p.NoteList.Pinned()) == 0 && len(p.EventList.Pinned(time.Now())) == 0 {
+		return 0
+	}
+	return pinnedCardItems + 4 // title, blank line and borders
+}
+
+// pinnedCard renders the pinned todos, events and notes in a column each,
+// empty when nothing is pinned
+func (p *DashboardPage) pinnedCard(now time.Time) string {
+	var columns [][]string
+	var count int
+
+	var todos []string
+	for _, todo := range p.TodoList.Pinned() {
+		icon := "○"
+		if todo.Completed {
+			icon = "✓"
+		}
+		todos = append(todos, icon+" "+todo.Title)
+	}
+	var events []string
+	for _, event := range p.EventList.Pinned(now) {
+		events = append(events, "📅 "+event.StartTime.Format("Jan 2 15:04")+" • "+event.Title)
+	}
+	var notes []string
+	for _, note := range p.NoteList.Pinned() {
+		notes = append(notes, "📝 "+note.Title)
+	}
+
+	for _, titles := range [][]string{todos, events, notes} {
+		if len(titles) > 0 {
+			columns = append(columns, titles)
+			count += len(titles)
+		}
+	}
+	if count == 0 {
+		return ""
+	}
+
+	cardWidth := p.width - 2
+	columnWidth := cardWidth/len(columns) - 2
+	itemStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Width(columnWidth).MaxWidth(columnWidth)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	rendered := make([]string, len(columns))
+	for i, titles := range columns {
+		lines := []string{}
+		for j, title := range titles {
+			if j == pinnedCardItems-1 && len(titles) > pinnedCardItems {
+				lines = append(lines, dim.Render(fmt.Sprintf("+%d more", len(titles)-j)))
+				break
+			}
+			lines = append(lines, itemStyle.Render(title))
+		}
+		rendered[i] = lipgloss.NewStyle().MarginRight(2).Render(strings.Join(lines, "\n"))
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213")).
+		Render(fmt.Sprintf("📌 Pinned (%d)", count))
+	return lipgloss.NewStyle().
+		Width(cardWidth).
+		Height(pinnedCardItems + 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63")).
+		Render(title + "\n\n" + lipgloss.JoinHorizontal(lipgloss.Top, rendered...))
+}
@@ -0,0 +1,46 @@
+package pages
+
+import (
+	"sort"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/ui/components"
+)
+
+// searchHits runs the free text of a query through search, keyed by item
+// id. It is nil when there is no text to search for, so callers can tell
+// "no filter" from "no results". A search that fails matches nothing.
+func searchHits(text string, search func(string) ([]models.SearchHit, error)) (map[int]models.SearchHit, error) {
+	if len(models.ParseSearch(text)) == 0 {
+		return nil, nil
+	}
+
+	hits, err := search(text)
+	if err != nil {
+		return map[int]models.SearchHit{}, err
+	}
+
+	byID := make(map[int]models.SearchHit, len(hits))
+	for _, hit := range hits {
+		byID[hit.ID] = hit
+	}
+	return byID, nil
+}
+
+// sortByRank orders items by search rank, best first, keeping the existing
+// order among equally ranked ones (e.g. occurrences of one event)
+func sortByRank[T any](items []T, hits map[int]models.SearchHit, id func(T) int) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return hits[id(items[i])].Rank < hits[id(items[j])].Rank
+	})
+}
+
+// snippetLine renders where the search matched the item, empty when not
+// searching
+func snippetLine(hits map[int]models.SearchHit, id int) string {
+	hit, ok := hits[id]
+	if !ok || hit.Snippet == "" {
+		return ""
+	}
+	return components.RenderSnippet("🔎 " + hit.Snippet)
+}
@@ -0,0 +1,30 @@
+package pages
+
+import (
+	"prodBooster/internal/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var tagStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+
+// renderTags renders " #work #home" to append after an item title in list
+// delegates, keeping the selection background when the row is selected
+func renderTags(tags []string, selected bool) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	style := tagStyle
+	if selected {
+		style = style.Background(lipgloss.Color("238"))
+	}
+	return style.Render(" " + models.FormatTags(tags))
+}
+
+// tagsLine renders the tags row of a detail pane, empty when untagged
+func tagsLine(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return tagStyle.Render("🏷️  " + models.FormatTags(tags))
+}
@@ -3,25 +3,58 @@ package pages
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"prodBooster/internal/models"
+	"prodBooster/internal/query"
 	"prodBooster/internal/ui/components"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Quick filters of the todos search bar
+const (
+	filterPending components.FilterType = iota + 1
+	filterCompleted
+	filterHighPriority
+	filterMediumPriority
+	filterLowPriority
+	filterDueToday
+	filterOverdue
+)
+
+var todoFilters = []components.FilterOption{
+	{Label: "📋 Pending Only", Color: "214"},
+	{Label: "✅ Completed", Color: "46"},
+	{Label: "🔥 High Priority", Color: "196"},
+	{Label: "📌 Medium Priority", Color: "214"},
+	{Label: "💤 Low Priority", Color: "45"},
+	{Label: "📅 Due Today", Color: "45"},
+	{Label: "⚠️  Overdue", Color: "196"},
+}
+
 // todoItem implements list.Item interface
 type todoItem struct {
-	todo *models.Todo
+	todo      *models.Todo
+	subtask   bool // drawn indented under its parent
+	done      int  // subtasks done
+	total     int  // subtasks in total
+	collapsed bool // subtasks hidden
 }
 
 func (t todoItem) Title() string {
+	indent := ""
+	if t.subtask {
+		indent = "  └ "
+	}
+
 	if t.todo.Completed {
-		return "✓ " + t.todo.Title
+		return indent + "✓ " + t.todo.Title + t.progress() + t.pin()
 	}
 
 	icon := "○"
@@ -31,7 +64,31 @@ func (t todoItem) Title() string {
 		icon = "◐"
 	}
 
-	return icon + " " + t.todo.Title
+	title := indent + icon + " " + t.todo.Title
+	if t.todo.IsRecurring() {
+		title += " 🔁"
+	}
+	return title + t.progress() + t.pin()
+}
+
+// pin marks pinned todos
+func (t todoItem) pin() string {
+	if t.todo.Pinned {
+		return pinMark
+	}
+	return ""
+}
+
+// progress renders " 3/5" for todos with subtasks, with ▸ when folded
+func (t todoItem) progress() string {
+	if t.total == 0 {
+		return ""
+	}
+	progress := fmt.Sprintf(" %d/%d", t.done, t.total)
+	if t.collapsed {
+		progress += " ▸"
+	}
+	return progress
 }
 
 func (t todoItem) Description() string {
@@ -95,50 +152,53 @@ func (d todoDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 		style = style.Background(lipgloss.Color("238"))
 	}
 
-	fmt.Fprint(w, style.Render(todo.Title()))
+	fmt.Fprint(w, style.Render(todo.Title())+renderTags(todo.todo.Tags, index == m.Index()))
 }
 
 type TodosPage struct {
 	currentPage  models.PageType
 	TodoList     *models.TodoList
+	links        *models.LinkList
 	form         *components.TodoForm
 	searchBar    *components.SearchBar
+	hits         map[int]models.SearchHit // full-text matches, nil when not searching
+	views        *components.ViewsPanel
+	order        int // index into todoOrders
 	list         list.Model
 	width        int
 	height       int
 	sidebarWidth int
+	collapsed    map[int]bool // parents whose subtasks are hidden
+	message      string       // why the last pin failed, cleared on the next key
 }
 
-func NewTodosPage(todoList_ *models.TodoList) *TodosPage {
-	// Sort todos initially
-	now := time.Now()
-	todayEnd := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
-	sortTodos(todoList_.Todos, now, todayEnd)
-
-	// Create list items from todos
-	items := make([]list.Item, len(todoList_.Todos))
-	for i, todo := range todoList_.Todos {
-		items[i] = todoItem{todo: todo}
-	}
-
+func NewTodosPage(todoList_ *models.TodoList, viewList_ *models.ViewList, linkList_ *models.LinkList) *TodosPage {
 	// Use custom delegate for colored rendering
 	delegate := todoDelegate{}
 
-	l := list.New(items, delegate, 0, 0)
+	l := list.New(nil, delegate, 0, 0)
 	l.Title = "✅ My Tasks"
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(false) // We use our own search
 
-	return &TodosPage{
+	p := &TodosPage{
 		currentPage:  models.PageTypeTodos(),
 		TodoList:     todoList_,
+		links:        linkList_,
 		form:         components.NewTodoForm(todoList_),
-		searchBar:    components.NewSearchBar(),
+		searchBar:    components.NewSearchBar(query.Todos, todoFilters),
+		views:        components.NewViewsPanel(viewList_, models.PageTodos, todoFilters),
 		list:         l,
 		width:        80,
 		height:       24,
 		sidebarWidth: 40,
+		collapsed:    map[int]bool{},
 	}
+
+	// Sort todos initially
+	p.updateListItems()
+
+	return p
 }
 
 func (p *TodosPage) Init() tea.Cmd {
@@ -173,9 +233,29 @@ func (p *TodosPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 		return p, cmd
 	}
 
+	// If the saved views sidebar is active, route to it
+	if p.views.IsActive() {
+		updatedViews, cmd := p.views.Update(msg)
+		p.views = updatedViews
+		if view, ok := p.views.Chosen(); ok {
+			p.order = applyView(p.searchBar, view, todoOrders)
+			p.updateListItems()
+			p.list.Select(0)
+		}
+		return p, cmd
+	}
+
 	// Normal page navigation
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		p.message = ""
+		if view, ok := p.views.Shortcut(msg.String()); ok {
+			// Apply a saved view without opening the sidebar
+			p.order = applyView(p.searchBar, view, todoOrders)
+			p.updateListItems()
+			p.list.Select(0)
+			return p, nil
+		}
 		switch msg.String() {
 		case "enter", " ":
 			// Toggle completed status
@@ -197,12 +277,50 @@ func (p *TodosPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 			// Create new todo
 			p.form.Activate()
 
+		case "a":
+			// Add a subtask to the selected todo (or its parent)
+			if item, ok := p.list.SelectedItem().(todoItem); ok {
+				parent := item.todo
+				if parent.IsSubtask() {
+					parent = p.TodoList.Get(parent.ParentID)
+				}
+				if parent != nil {
+					delete(p.collapsed, parent.ID)
+					p.form.ActivateSubtask(parent)
+				}
+			}
+			return p, nil
+
+		case "c":
+			// Collapse/expand the subtasks of the selected todo
+			if item, ok := p.list.SelectedItem().(todoItem); ok {
+				parentID := item.todo.ID
+				if item.todo.IsSubtask() {
+					parentID = item.todo.ParentID
+				}
+				p.collapsed[parentID] = !p.collapsed[parentID]
+				p.updateListItems()
+				p.selectTodo(parentID)
+			}
+			return p, nil
+
 		case "e":
 			// Edit selected todo
 			if item, ok := p.list.SelectedItem().(todoItem); ok {
 				p.form.LoadForEdit(item.todo)
 			}
 
+		case "p":
+			// Pin the selected todo to the top, or unpin it
+			if item, ok := p.list.SelectedItem().(todoItem); ok {
+				if err := p.TodoList.TogglePinned(item.todo.ID); err != nil {
+					p.message = "⚠️  " + err.Error()
+				}
+				p.updateListItems()
+				p.selectTodo(item.todo.ID)
+			}
+			return p, nil
+
 		case "d":
 			// Delete selected todo
 			if item, ok := p.list.SelectedItem().(todoItem); ok {
@@ -215,7 +333,20 @@ func (p *TodosPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 
 		case "/":
 			// Open search
+			p.searchBar.SetKnownTags(p.TodoList.AllTags())
 			p.searchBar.Activate()
+
+		case "s":
+			// Cycle the sort order
+			p.order = (p.order + 1) % len(todoOrders)
+			p.updateListItems()
+			p.list.Select(0)
+			return p, nil
+
+		case "v":
+			// Pick or save a view
+			p.views.Activate()
+			return p, nil
 		}
 	}
 
@@ -229,47 +360,111 @@ func (p *TodosPage) Update(msg tea.Msg) (Page, tea.Cmd) {
 	return p, cmd
 }
 
+// Refresh picks up changes made on other pages
+func (p *TodosPage) Refresh() {
+	p.updateListItems()
+}
+
+// Focus selects the todo with the given id, clearing the search and
+// expanding its parent so it is listed
+func (p *TodosPage) Focus(id int) {
+	p.searchBar.Deactivate()
+	if todo := p.TodoList.Get(id); todo != nil && todo.IsSubtask() {
+		delete(p.collapsed, todo.ParentID)
+	}
+	p.updateListItems()
+	p.selectTodo(id)
+}
+
 // updateListItems refreshes the list with current todos and filters
 func (p *TodosPage) updateListItems() {
-	now := time.Now()
+	filteredTodos, hits, err := findTodos(p.TodoList, p.searchBar.Query(), p.searchBar.GetFilter(), todoOrders[p.order], time.Now())
+	p.hits = hits
+	p.searchBar.SetSearchError(err)
+	p.list.Title = listTitle("✅ My Tasks", todoOrders, p.order)
+	p.views.SetCurrent(p.searchBar.GetQuery(), int(p.searchBar.GetFilter()), todoOrders[p.order])
+
+	// Subtasks go under their parent when it is listed too
+	listed := map[int]bool{}
+	for _, todo := range filteredTodos {
+		listed[todo.ID] = true
+	}
+	var roots []*models.Todo
+	subtasks := map[int][]*models.Todo{}
+	for _, todo := range filteredTodos {
+		if todo.IsSubtask() && listed[todo.ParentID] {
+			subtasks[todo.ParentID] = append(subtasks[todo.ParentID], todo)
+		} else {
+			roots = append(roots, todo)
+		}
+	}
+
+	// Convert to list items, subtasks in the order they were added
+	items := make([]list.Item, 0, len(filteredTodos))
+	for _, todo := range roots {
+		done, total := p.TodoList.Progress(todo.ID)
+		collapsed := p.collapsed[todo.ID]
+		items = append(items, todoItem{todo: todo, done: done, total: total, collapsed: collapsed})
+		if collapsed {
+			continue
+		}
+		children := subtasks[todo.ID]
+		sort.SliceStable(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+		for _, child := range children {
+			items = append(items, todoItem{todo: child, subtask: true})
+		}
+	}
+
+	p.list.SetItems(items)
+}
+
+// findTodos returns the todos matching a search, sorted by order, and the
+// full-text hits (nil when the query has no text). When the full-text
+// search fails nothing matches and the error comes back.
+func findTodos(todoList *models.TodoList, q *query.Query, filter components.FilterType, order string, now time.Time) ([]*models.Todo, map[int]models.SearchHit, error) {
 	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	todayEnd := todayStart.Add(24 * time.Hour)
 
+	hits, err := searchHits(q.Text(), todoList.Search)
+
 	filteredTodos := []*models.Todo{}
-	for _, todo := range p.TodoList.Todos {
+	for _, todo := range todoList.Todos {
 		// Apply text search
-		if !p.searchBar.Match(todo.Title + " " + todo.Description) {
+		if _, ok := hits[todo.ID]; hits != nil && !ok {
+			continue
+		}
+		// Apply fields, tags and the rest of the query
+		if !q.MatchTodo(todo) {
 			continue
 		}
 
 		// Apply filter
-		filter := p.searchBar.GetFilter()
 		switch filter {
-		case components.FilterPending:
+		case filterPending:
 			if todo.Completed {
 				continue
 			}
-		case components.FilterCompleted:
+		case filterCompleted:
 			if !todo.Completed {
 				continue
 			}
-		case components.FilterHighPriority:
+		case filterHighPriority:
 			if todo.Priority != models.PriorityHigh {
 				continue
 			}
-		case components.FilterMediumPriority:
+		case filterMediumPriority:
 			if todo.Priority != models.PriorityMedium {
 				continue
 			}
-		case components.FilterLowPriority:
+		case filterLowPriority:
 			if todo.Priority != models.PriorityLow {
 				continue
 			}
-		case components.FilterToday:
+		case filterDueToday:
 			if todo.DueTime == nil || !todo.DueTime.After(todayStart) || !todo.DueTime.Before(todayEnd) {
 				continue
 			}
-		case components.FilterOverdue:
+		case filterOverdue:
 			if todo.DueTime == nil || !todo.DueTime.Before(now) || todo.Completed {
 				continue
 			}
@@ -278,16 +473,46 @@ func (p *TodosPage) updateListItems() {
 		filteredTodos = append(filteredTodos, todo)
 	}
 
-	// Sort todos by priority: overdue → today → pending → completed
+	// Smart order: overdue → today → pending → completed, best match
+	// first when searching
 	sortTodos(filteredTodos, now, todayEnd)
-
-	// Convert to list items
-	items := make([]list.Item, len(filteredTodos))
-	for i, todo := range filteredTodos {
-		items[i] = todoItem{todo: todo}
+	switch order {
+	case "due":
+		// Soonest first, no deadline last
+		sort.SliceStable(filteredTodos, func(i, j int) bool {
+			a, b := filteredTodos[i].DueTime, filteredTodos[j].DueTime
+			return a != nil && (b == nil || a.Before(*b))
+		})
+	case "priority":
+		sort.SliceStable(filteredTodos, func(i, j int) bool {
+			return filteredTodos[i].Priority > filteredTodos[j].Priority
+		})
+	case "newest":
+		sort.SliceStable(filteredTodos, func(i, j int) bool {
+			return filteredTodos[i].CreatedAt.After(filteredTodos[j].CreatedAt)
+		})
+	case "title":
+		sort.SliceStable(filteredTodos, func(i, j int) bool {
+			return strings.ToLower(filteredTodos[i].Title) < strings.ToLower(filteredTodos[j].Title)
+		})
+	default:
+		if hits != nil {
+			sortByRank(filteredTodos, hits, func(todo *models.Todo) int { return todo.ID })
+		}
 	}
+	pinnedFirst(filteredTodos, func(todo *models.Todo) bool { return todo.Pinned })
 
-	p.list.SetItems(items)
+	return filteredTodos, hits, err
+}
+
+// selectTodo moves the list selection to the todo with the given id
+func (p *TodosPage) selectTodo(id int) {
+	for i, item := range p.list.Items() {
+		if item.(todoItem).todo.ID == id {
+			p.list.Select(i)
+			return
+		}
+	}
 }
 
 // sortTodos sorts by priority: overdue > today > high priority > medium > low > completed
@@ -343,6 +568,7 @@ func (p *TodosPage) SetSize(width, height int) {
 	}
 
 	p.list.SetSize(p.sidebarWidth-4, height-8) // Account for borders and padding
+	p.views.SetHeight(height - 6)
 	p.form.SetSize(width, height)
 }
 
@@ -370,7 +596,7 @@ func (p *TodosPage) View() string {
 	sidebar := sidebarStyle.Render(p.list.View())
 
 	// Content pane with selected todo detail
-	contentWidth := p.width - p.sidebarWidth - 4
+	contentWidth := p.width - p.sidebarWidth - 4 - viewsWidth(p.views)
 	contentStyle := lipgloss.NewStyle().
 		Width(contentWidth).
 		Height(p.height-6).
@@ -383,8 +609,8 @@ func (p *TodosPage) View() string {
 		content = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("240")).
 			Render("No todos yet.\nPress 'n' to create one!")
-	} else if p.TodoList.Selected < len(p.TodoList.Todos) {
-		todo := p.TodoList.Todos[p.TodoList.Selected]
+	} else if item, ok := p.list.SelectedItem().(todoItem); ok {
+		todo := item.todo
 
 		// Title with status icon
 		titleStyle := lipgloss.NewStyle().
@@ -417,10 +643,11 @@ func (p *TodosPage) View() string {
 		dueColor := "240"
 		if todo.DueTime != nil {
 			now := time.Now()
+			todayEnd := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
 			if todo.DueTime.Before(now) && !todo.Completed {
 				dueStr = "⚠️  OVERDUE! Was due " + todo.DueTime.Format("Mon, Jan 2 at 3:04 PM")
 				dueColor = "196"
-			} else if todo.DueTime.After(now) && todo.DueTime.Before(now.Add(24*time.Hour)) {
+			} else if todo.DueTime.After(now) && !todo.DueTime.After(todayEnd) {
 				dueStr = "⏰ Due today at " + todo.DueTime.Format("3:04 PM")
 				dueColor = "214"
 			} else {
@@ -435,11 +662,16 @@ func (p *TodosPage) View() string {
 			lipgloss.NewStyle().Foreground(lipgloss.Color(statusColor)).Render(statusIcon),
 			lipgloss.NewStyle().Foreground(lipgloss.Color(priorityColor)).Render(priorityIcon),
 			lipgloss.NewStyle().Foreground(lipgloss.Color(dueColor)).Render(dueStr),
+			p.repeatLine(todo),
+			tagsLine(todo.Tags),
+			snippetLine(p.hits, todo.ID),
 			"",
 			lipgloss.NewStyle().
 				Foreground(lipgloss.Color("252")).
 				Width(contentWidth-4).
 				Render(todo.Description),
+			p.subtaskLines(todo),
+			linkLines(p.links, models.ItemRef{Page: models.PageTodos, ID: todo.ID}),
 		)
 	} else {
 		content = lipgloss.NewStyle().
@@ -450,10 +682,14 @@ func (p *TodosPage) View() string {
 	// Add help text
 	helpText := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("240")).
-		Render("✨ n: new task • e: edit • d: delete • /: search • space: mark done • ↑/↓: browse • q: quit")
+		Render("✨ n: new task • a: add subtask • c: fold subtasks • e: edit • d: delete • p: pin • /: search • s: sort • v: views • l: link • o: links • space: mark done • ↑/↓: browse • q: quit")
+
+	if p.message != "" {
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("213")).Render(p.message)
+	}
 
-	// Combine sidebar and content
-	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, contentStyle.Render(content))
+	// Combine saved views, sidebar and content
+	mainContent := withViews(p.views, lipgloss.JoinHorizontal(lipgloss.Top, sidebar, contentStyle.Render(content)))
 
 	// Build the view
 	return lipgloss.JoinVertical(lipgloss.Left,
@@ -463,6 +699,68 @@ func (p *TodosPage) View() string {
 	)
 }
 
+// subtaskLines renders the checklist of a parent todo, or which todo a
+// subtask belongs to
+func (p *TodosPage) subtaskLines(todo *models.Todo) string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	if todo.IsSubtask() {
+		if parent := p.TodoList.Get(todo.ParentID); parent != nil {
+			return "\n" + dim.Render("↳ Subtask of "+parent.Title)
+		}
+		return ""
+	}
+
+	subtasks := p.TodoList.Subtasks(todo.ID)
+	if len(subtasks) == 0 {
+		return ""
+	}
+
+	done, total := p.TodoList.Progress(todo.ID)
+	lines := []string{"", lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("📋 Subtasks %d/%d", done, total))}
+	for _, subtask := range subtasks {
+		if subtask.Completed {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("70")).Render("  ☑ "+subtask.Title))
+		} else {
+			lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Render("  ☐ "+subtask.Title))
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// repeatLine describes the todo's recurrence and its series' completion
+// history, empty for one-off todos
+func (p *TodosPage) repeatLine(todo *models.Todo) string {
+	history := p.TodoList.Completions(todo.Series())
+	if !todo.IsRecurring() && len(history) == 0 {
+		return ""
+	}
+
+	line := "🔁 Earlier occurrence of a repeating todo"
+	if rule, ok := todo.Rule(); ok {
+		line = "🔁 Repeats " + rule.Describe()
+	} else if todo.IsRecurring() {
+		line = "🔁 Repeats (" + todo.Recurrence + ")"
+	}
+
+	switch len(history) {
+	case 0:
+		line += " · not done yet"
+	case 1:
+		line += " · done once, " + history[0].CompletedAt.Format("Mon, Jan 2")
+	default:
+		line += fmt.Sprintf(" · done %d times, last %s", len(history), history[0].CompletedAt.Format("Mon, Jan 2"))
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("81")).Render(line)
+}
+
+// Selected returns the todo under the cursor
+func (p *TodosPage) Selected() (models.ItemRef, bool) {
+	if item, ok := p.list.SelectedItem().(todoItem); ok {
+		return models.ItemRef{Page: models.PageTodos, ID: item.todo.ID}, true
+	}
+	return models.ItemRef{}, false
+}
+
 func (p *TodosPage) IsFormActive() bool {
-	return p.form.IsActive() || p.searchBar.IsActive()
+	return p.form.IsActive() || p.searchBar.IsActive() || p.views.IsActive()
 }
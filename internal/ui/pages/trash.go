@@ -0,0 +1,362 @@
+package pages
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/ui/components"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// trashItem implements list.Item for a deleted todo, note or event
+type trashItem struct {
+	todo  *models.Todo
+	note  *models.Note
+	event *models.Event
+}
+
+func (t trashItem) Title() string {
+	switch {
+	case t.todo != nil:
+		return "✅ " + t.todo.Title
+	case t.note != nil:
+		return "📝 " + t.note.Title
+	case t.event != nil:
+		return "📅 " + t.event.Title
+	}
+	return ""
+}
+
+func (t trashItem) Description() string {
+	return "deleted " + t.deletedAt().Format("Mon, Jan 2 15:04")
+}
+
+func (t trashItem) FilterValue() string {
+	return t.Title()
+}
+
+func (t trashItem) deletedAt() time.Time {
+	switch {
+	case t.todo != nil:
+		return t.todo.DeletedAt
+	case t.note != nil:
+		return t.note.DeletedAt
+	case t.event != nil:
+		return t.event.DeletedAt
+	}
+	return time.Time{}
+}
+
+type trashDelegate struct{}
+
+func (d trashDelegate) Height() int                             { return 1 }
+func (d trashDelegate) Spacing() int                            { return 0 }
+func (d trashDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
+func (d trashDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	trashed, ok := item.(trashItem)
+	if !ok {
+		return
+	}
+
+	style := lipgloss.NewStyle().Foreground(lipgloss.Color("246"))
+	if index == m.Index() {
+		style = style.Foreground(lipgloss.Color("252")).Background(lipgloss.Color("238"))
+	}
+
+	fmt.Fprint(w, style.Render(trashed.Title()))
+}
+
+type TrashPage struct {
+	TodoList      *models.TodoList
+	NoteList      *models.NoteList
+	EventList     *models.EventList
+	history       *models.History
+	retentionDays int
+	list          list.Model
+	confirmPurge  bool   // waiting for y/n before deleting the selected item for good
+	confirmEmpty  bool   // waiting for y/n before emptying the whole trash
+	message       string // why the last restore or delete failed, cleared on the next key
+	width         int
+	height        int
+	sidebarWidth  int
+}
+
+func NewTrashPage(todoList_ *models.TodoList, noteList_ *models.NoteList, eventList_ *models.EventList, history *models.History, retentionDays int) *TrashPage {
+	l := list.New(nil, trashDelegate{}, 0, 0)
+	l.Title = "🗑️  Trash"
+	l.SetShowStatusBar(true)
+	l.SetFilteringEnabled(false)
+
+	p := &TrashPage{
+		TodoList:      todoList_,
+		NoteList:      noteList_,
+		EventList:     eventList_,
+		history:       history,
+		retentionDays: retentionDays,
+		list:          l,
+		width:         80,
+		height:        24,
+		sidebarWidth:  40,
+	}
+	p.updateListItems()
+	return p
+}
+
+func (p *TrashPage) Init() tea.Cmd {
+	return nil
+}
+
+func (p *TrashPage) Update(msg tea.Msg) (Page, tea.Cmd) {
+	// Waiting for a yes/no
+	if p.confirmPurge || p.confirmEmpty {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.String() == "y" {
+				var err error
+				if p.confirmEmpty {
+					err = p.empty()
+				} else if item, ok := p.list.SelectedItem().(trashItem); ok {
+					err = p.purge(item)
+				}
+				if err != nil {
+					p.message = "⚠️  " + err.Error()
+				}
+				p.updateListItems()
+			}
+			p.confirmPurge, p.confirmEmpty = false, false
+		}
+		return p, nil
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		p.message = ""
+		switch msg.String() {
+		case "r", "enter":
+			// Restore selected item
+			if item, ok := p.list.SelectedItem().(trashItem); ok {
+				index := p.list.Index()
+				if err := p.restore(item); err != nil {
+					p.message = "⚠️  " + err.Error()
+				}
+				p.updateListItems()
+				p.list.Select(min(index, len(p.list.Items())-1))
+			}
+			return p, nil
+
+		case "d", "delete":
+			// Delete selected item for good, after confirmation
+			if _, ok := p.list.SelectedItem().(trashItem); ok {
+				p.confirmPurge = true
+			}
+			return p, nil
+
+		case "E":
+			// Empty the trash, after confirmation
+			if len(p.list.Items()) > 0 {
+				p.confirmEmpty = true
+			}
+			return p, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	p.list, cmd = p.list.Update(msg)
+	return p, cmd
+}
+
+func (p *TrashPage) restore(item trashItem) error {
+	switch {
+	case item.todo != nil:
+		return p.TodoList.Restore(item.todo.ID)
+	case item.note != nil:
+		return p.NoteList.Restore(item.note.ID)
+	case item.event != nil:
+		return p.EventList.Restore(item.event.ID)
+	}
+	return nil
+}
+
+func (p *TrashPage) purge(item trashItem) error {
+	switch {
+	case item.todo != nil:
+		return p.TodoList.Purge(item.todo.ID)
+	case item.note != nil:
+		return p.NoteList.Purge(item.note.ID)
+	case item.event != nil:
+		return p.EventList.Purge(item.event.ID)
+	}
+	return nil
+}
+
+// empty deletes everything in the trash for good, as one undo step
+func (p *TrashPage) empty() error {
+	return p.history.Group("Empty trash", func() error {
+		for _, item := range p.list.Items() {
+			if err := p.purge(item.(trashItem)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// updateListItems lists everything in the trash, most recently deleted first.
+// Subtasks trashed along with their parent are shown with the parent.
+func (p *TrashPage) updateListItems() {
+	trashedTodos := map[int]bool{}
+	for _, todo := range p.TodoList.Deleted {
+		trashedTodos[todo.ID] = true
+	}
+
+	var items []trashItem
+	for _, todo := range p.TodoList.Deleted {
+		if !trashedTodos[todo.ParentID] {
+			items = append(items, trashItem{todo: todo})
+		}
+	}
+	for _, note := range p.NoteList.Deleted {
+		items = append(items, trashItem{note: note})
+	}
+	for _, event := range p.EventList.Deleted {
+		items = append(items, trashItem{event: event})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].deletedAt().After(items[j].deletedAt())
+	})
+
+	listItems := make([]list.Item, len(items))
+	for i, item := range items {
+		listItems[i] = item
+	}
+	p.list.SetItems(listItems)
+}
+
+// Refresh picks up items deleted or restored on other pages
+func (p *TrashPage) Refresh() {
+	p.updateListItems()
+}
+
+func (p *TrashPage) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	p.sidebarWidth = width / 3
+	if p.sidebarWidth < 30 {
+		p.sidebarWidth = 30
+	}
+	if p.sidebarWidth > 50 {
+		p.sidebarWidth = 50
+	}
+
+	p.list.SetSize(p.sidebarWidth-4, height-6)
+}
+
+func (p *TrashPage) View() string {
+	topBar := components.NewTopBar(models.PageTypeTrash())
+	topBar.SetSize(p.width, 1)
+
+	sidebarStyle := lipgloss.NewStyle().
+		Width(p.sidebarWidth).
+		Height(p.height - 6).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63"))
+
+	sidebar := sidebarStyle.Render(p.list.View())
+
+	contentWidth := p.width - p.sidebarWidth - 4
+	contentStyle := lipgloss.NewStyle().
+		Width(contentWidth).
+		Height(p.height-6).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("63"))
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var content string
+	if item, ok := p.list.SelectedItem().(trashItem); ok {
+		content = p.detail(item, contentWidth)
+	} else {
+		content = dim.Render("✨ The trash is empty.\n\nDeleted tasks, notes and events wait here before they're gone for good.")
+	}
+
+	retention := "Items stay here until you delete them"
+	if p.retentionDays > 0 {
+		retention = fmt.Sprintf("Items are deleted for good after %d days", p.retentionDays)
+	}
+	content = lipgloss.JoinVertical(lipgloss.Left, content, "", dim.Italic(true).Render("⏳ "+retention))
+
+	helpText := dim.Render("✨ r: restore • d: delete forever • E: empty trash • ↑/↓: browse • q: quit")
+	switch {
+	case p.confirmPurge:
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render("⚠️  Delete this forever? y: yes • any other key: cancel")
+	case p.confirmEmpty:
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render(fmt.Sprintf("⚠️  Delete all %d items forever? y: yes • any other key: cancel", len(p.list.Items())))
+	case p.message != "":
+		helpText = lipgloss.NewStyle().Foreground(lipgloss.Color("213")).Render(p.message)
+	}
+
+	mainContent := lipgloss.JoinHorizontal(lipgloss.Top, sidebar, contentStyle.Render(content))
+
+	return lipgloss.JoinVertical(lipgloss.Left,
+		topBar.View(),
+		mainContent,
+		helpText,
+	)
+}
+
+// detail renders the preview of a trashed item
+func (p *TrashPage) detail(item trashItem, width int) string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("213"))
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	body := lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Width(width - 4)
+
+	parts := []string{}
+	switch {
+	case item.todo != nil:
+		todo := item.todo
+		parts = append(parts, titleStyle.Render(todo.Title), "", dim.Render("✅ Task · "+todo.Priority.String()+" priority"))
+		if todo.DueTime != nil {
+			parts = append(parts, dim.Render("📅 Was due "+todo.DueTime.Format("Mon, Jan 2 at 3:04 PM")))
+		}
+		subtasks := 0
+		for _, other := range p.TodoList.Deleted {
+			if other.ParentID == todo.ID {
+				subtasks++
+			}
+		}
+		if subtasks > 0 {
+			parts = append(parts, dim.Render(fmt.Sprintf("📋 %d subtasks come back with it", subtasks)))
+		}
+		parts = append(parts, tagsLine(todo.Tags), "", body.Render(todo.Description))
+	case item.note != nil:
+		note := item.note
+		parts = append(parts, titleStyle.Render(note.Title), "", dim.Render("📝 Note · written "+note.CreatedAt.Format("Jan 2, 2006")),
+			tagsLine(note.Tags), "", body.Render(note.Content))
+	case item.event != nil:
+		event := item.event
+		parts = append(parts, titleStyle.Render(event.Title), "", dim.Render("📅 Event · "+event.StartTime.Format("Monday, Jan 2, 2006 at 3:04 PM")))
+		if rule, ok := event.Rule(); ok {
+			parts = append(parts, dim.Render("🔁 Repeats "+rule.Describe()))
+		}
+		if event.Location != "" {
+			parts = append(parts, dim.Render("📍 "+event.Location))
+		}
+		parts = append(parts, tagsLine(event.Tags), "", body.Render(event.Content))
+	}
+
+	parts = append(parts, "", dim.Render("🗑️  Deleted "+item.deletedAt().Format("Mon, Jan 2 at 3:04 PM")))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+func (p *TrashPage) IsFormActive() bool {
+	return p.confirmPurge || p.confirmEmpty
+}
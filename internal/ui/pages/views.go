@@ -0,0 +1,57 @@
+package pages
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"prodBooster/internal/models"
+	"prodBooster/internal/ui/components"
+)
+
+// Sort orders each page cycles through with s, the default first. Saved
+// views store the name.
+var (
+	todoOrders  = []string{"smart", "due", "priority", "newest", "title"}
+	noteOrders  = []string{"newest", "oldest", "title"}
+	eventOrders = []string{"smart", "start", "title"}
+)
+
+// orderIndex finds a sort order by name, falling back to the default
+func orderIndex(orders []string, name string) int {
+	for i, order := range orders {
+		if order == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// listTitle adds the sort order to a list title unless it is the default
+func listTitle(title string, orders []string, order int) string {
+	if order == 0 {
+		return title
+	}
+	return title + " ↕ " + orders[order]
+}
+
+// applyView puts a saved view's search in effect and returns its sort order
+func applyView(bar *components.SearchBar, view *models.SavedView, orders []string) int {
+	bar.Apply(view.Query, components.FilterType(view.Filter))
+	return orderIndex(orders, view.Sort)
+}
+
+// withViews puts the saved views sidebar, when there is one, left of a
+// page's main content
+func withViews(views *components.ViewsPanel, mainContent string) string {
+	if !views.Visible() {
+		return mainContent
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, views.View(), mainContent)
+}
+
+// viewsWidth is how much room the saved views sidebar takes
+func viewsWidth(views *components.ViewsPanel) int {
+	if !views.Visible() {
+		return 0
+	}
+	return components.ViewsPanelWidth
+}
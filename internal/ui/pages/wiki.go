@@ -0,0 +1,48 @@
+package pages
+
+import (
+	"strings"
+
+	"prodBooster/internal/models"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	wikiLinkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("81")).Underline(true)
+	wikiBrokenStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Underline(true)
+)
+
+// renderWiki styles the [[links]] of a note's text: links to existing notes
+// highlighted, the others red, the selected one reversed. The rest of the
+// text gets base.
+func renderWiki(content string, selected int, exists func(title string) bool, base lipgloss.Style) string {
+	var b strings.Builder
+	offset := 0
+	for i, ref := range models.ParseWikiLinks(content) {
+		b.WriteString(renderPlain(content[offset:ref.Start], base))
+
+		style := wikiLinkStyle
+		if !exists(ref.Title) {
+			style = wikiBrokenStyle
+		}
+		if i == selected {
+			style = style.Reverse(true)
+		}
+		b.WriteString(style.Render(content[ref.Start:ref.End]))
+		offset = ref.End
+	}
+	b.WriteString(renderPlain(content[offset:], base))
+	return b.String()
+}
+
+// renderPlain styles text line by line, so line breaks survive wrapping
+func renderPlain(text string, base lipgloss.Style) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = base.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -6,7 +6,9 @@ import (
 	"path/filepath"
 
 	index "prodBooster/internal"
+	"prodBooster/internal/config"
 	"prodBooster/internal/db"
+	"prodBooster/internal/templates"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -19,6 +21,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	configPath := filepath.Join(homeDir, ".prodbooster", "config.json")
+	if err := config.Load(configPath); err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	templates.Init(filepath.Join(homeDir, ".prodbooster", "templates"))
+
 	dbPath := filepath.Join(homeDir, ".prodbooster", "data.db")
 	if err := db.Init(dbPath); err != nil {
 		fmt.Printf("Error initializing database: %v\n", err)